@@ -24,60 +24,66 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/meals/entries": {
-            "get": {
-                "description": "Returns meal entries for a user within a date range",
+        "/auth/login": {
+            "post": {
+                "description": "Verifies email and password, records the login time and returns an access and refresh token",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "auth"
                 ],
-                "summary": "Get meal entries for a user",
+                "summary": "Log in",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "startDate",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "endDate",
-                        "in": "query"
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.loginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MealEntry"
-                            }
+                            "$ref": "#/definitions/handlers.loginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/password": {
             "post": {
-                "description": "Logs a meal for a user with specified portion size",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the authenticated user's password after verifying the current one. Every access and refresh token issued before the change is revoked, including the caller's; the response has a new pair for the caller to continue with.",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,141 +91,181 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "auth"
                 ],
-                "summary": "Create a new meal entry",
+                "summary": "Change password",
                 "parameters": [
                     {
-                        "description": "Meal entry details",
-                        "name": "entry",
+                        "description": "Current and new password",
+                        "name": "passwords",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.mealEntryRequest"
+                            "$ref": "#/definitions/handlers.changePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MealEntry"
+                            "$ref": "#/definitions/auth.TokenPair"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/meals/packages": {
-            "get": {
-                "description": "Returns a list of all meal packages, optionally filtered by goal type",
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a valid refresh token for a new access and refresh token. Tokens issued before the user's last password change are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "auth"
                 ],
-                "summary": "Get all meal packages",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Goal type filter (LOSE, GAIN, ALL)",
-                        "name": "goalType",
-                        "in": "query"
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.refreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MealPackage"
-                            }
+                            "$ref": "#/definitions/auth.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/meals/packages/{id}": {
+        "/foods": {
             "get": {
-                "description": "Returns details of a specific meal package",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one page of foods ordered by name. Text search matches words in the name. Pass nextCursor from a response as cursor to get the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "foods"
                 ],
-                "summary": "Get a meal package by ID",
+                "summary": "List foods",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Meal Package ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Text search",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-100), defaults to 20",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MealPackage"
+                            "$ref": "#/definitions/models.FoodPage"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "Returns a list of all users in the system",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Get all users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new user with the provided details and calculates their nutritional goals",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a food with its nutrition per 100 g to the food database. A packaged food may have a barcode, which must be valid, not store-internal and not used by another food. Requires the ADMIN role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -227,17 +273,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "foods"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create a food",
                 "parameters": [
                     {
-                        "description": "User details",
-                        "name": "user",
+                        "description": "Food",
+                        "name": "food",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.userRegistrationRequest"
+                            "$ref": "#/definitions/handlers.createFoodRequest"
                         }
                     }
                 ],
@@ -245,36 +291,68 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Food"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/foods/barcode/{code}": {
             "get": {
-                "description": "Returns details of a specific user",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the packaged food with a barcode and its nutrition per serving. UPC-A, EAN-8, EAN-13 and GTIN-14 codes are accepted and the check digit is verified, so a UPC-A finds the same food as the EAN-13 it is printed as. The serving is the labelled serving size, or 100 g when the food has none.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "foods"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Look up a food by barcode",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Barcode",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     }
@@ -283,75 +361,50 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.FoodServing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/workouts/entries": {
-            "get": {
-                "description": "Returns workout entries for a user within a date range",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "workouts"
-                ],
-                "summary": "Get workout entries for a user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "startDate",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "endDate",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.WorkoutEntry"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/foods/barcode/{code}/entries": {
             "post": {
-                "description": "Logs a workout for a user with specified intensity and duration",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs the packaged food with a barcode as a meal entry for the authenticated user. Nutrition is computed from the food's serving size, or 100 g when it has none, times the number of servings. The entry keeps the food ID and the nutrition of one serving, so changing its servings later rescales it, while later corrections to the food leave it unchanged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -359,17 +412,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "workouts"
+                    "foods"
                 ],
-                "summary": "Create a new workout entry",
+                "summary": "Log a food by barcode",
                 "parameters": [
                     {
-                        "description": "Workout entry details",
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Servings, meal type and date",
                         "name": "entry",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.workoutEntryRequest"
+                            "$ref": "#/definitions/handlers.foodEntryRequest"
                         }
                     }
                 ],
@@ -377,447 +437,6217 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.WorkoutEntry"
+                            "$ref": "#/definitions/models.MealEntry"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "/workouts/packages": {
+        "/foods/{id}": {
             "get": {
-                "description": "Returns a list of all workout packages, optionally filtered by goal type",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a food with its nutrition per 100 g",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workouts"
+                    "foods"
                 ],
-                "summary": "Get all workout packages",
+                "summary": "Get a food by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Goal type filter (LOSE, GAIN, ALL)",
-                        "name": "goalType",
-                        "in": "query"
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.WorkoutPackage"
-                            }
+                            "$ref": "#/definitions/models.Food"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/workouts/packages/{id}": {
-            "get": {
-                "description": "Returns details of a specific workout package",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a food's name, brand, serving size and nutrition; the barcode and source of an imported food are kept. Every meal package whose recipe uses the food is recomputed and gets a new version; entries logged earlier keep their values. Requires the ADMIN role.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workouts"
+                    "foods"
                 ],
-                "summary": "Get a workout package by ID",
+                "summary": "Correct a food",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workout Package ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Food",
+                        "name": "food",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.foodRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.WorkoutPackage"
+                            "$ref": "#/definitions/handlers.updateFoodResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.mealEntryRequest": {
-            "type": "object",
-            "required": [
-                "date",
-                "packageId",
-                "portionMultiplier",
-                "userId"
-            ],
-            "properties": {
-                "date": {
-                    "type": "string",
-                    "example": "2023-03-18"
-                },
-                "packageId": {
-                    "type": "string",
-                    "example": "meal1"
-                },
-                "portionMultiplier": {
-                    "type": "number",
-                    "maximum": 3,
-                    "minimum": 0.1,
-                    "example": 1
-                },
-                "userId": {
-                    "type": "string",
-                    "example": "usr1"
+        },
+        "/meals/entries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated user's meal entries within a date range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Get meal entries for the caller",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "endDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MealEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs a meal for the authenticated user with specified portion size",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Create a new meal entry",
+                "parameters": [
+                    {
+                        "description": "Meal entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.mealEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "handlers.userRegistrationRequest": {
-            "type": "object",
-            "required": [
-                "activityLevel",
-                "birthDate",
-                "email",
-                "gender",
-                "goal",
-                "height",
-                "name",
-                "password",
-                "weight"
-            ],
-            "properties": {
-                "activityLevel": {
-                    "type": "string",
-                    "enum": [
-                        "LOW",
-                        "MODERATE",
-                        "HIGH"
-                    ],
-                    "example": "MODERATE"
-                },
-                "birthDate": {
-                    "type": "string",
-                    "example": "1990-01-01"
-                },
-                "email": {
-                    "type": "string",
-                    "example": "john@example.com"
-                },
-                "gender": {
-                    "type": "string",
-                    "enum": [
-                        "MALE",
-                        "FEMALE",
-                        "OTHER"
-                    ],
-                    "example": "MALE"
-                },
-                "goal": {
-                    "type": "string",
+        "/meals/entries/custom": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs a meal for the authenticated user from manually entered calories and macros instead of a package. Calories must be within 15% of the calories implied by the macros. Custom entries count toward summaries and trends like package entries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Create a custom meal entry",
+                "parameters": [
+                    {
+                        "description": "Custom meal entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.customMealEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/entries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one of the authenticated user's meal entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Get a meal entry by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes one of the authenticated user's meal entries",
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Delete a meal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the package, portion size or date of a package entry, the name, meal type, calories, macros or date of a custom entry, or the servings, meal type or date of a food entry. Package entries are recomputed from the package version they were logged with, or from the current version when the package changes. Custom entries must keep calories within 15% of the macros. Food entries are rescaled from the nutrition per serving they were logged with when the servings change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Update a meal entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateMealEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/entries/{id}/promote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a meal package owned by the entry's user from a custom entry's name, meal type, calories and macros, so that the meal can be logged again like any package. The package is private unless another visibility is given; LINK packages get a shareToken that others pass to view, clone or log them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Save a custom meal entry as a package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Package details",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.promoteMealEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/packages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one page of meal packages matching the filters. Filtering on LOSE or GAIN includes packages for BOTH goals, and suitsGoal marks the packages that fit the caller's current goal. Text search matches words in the name, description and ingredients. Lists include the catalog, the caller's own packages and other users' public packages unless scope narrows them. Pass nextCursor from a response as cursor to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "List meal packages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Goal type filter (LOSE, GAIN, BOTH, ALL)",
+                        "name": "goalType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Meal type filter (BREAKFAST, LUNCH, DINNER, SNACK)",
+                        "name": "mealType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum calories",
+                        "name": "minCalories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum calories",
+                        "name": "maxCalories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum protein in grams",
+                        "name": "minProtein",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum protein in grams",
+                        "name": "maxProtein",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum carbs in grams",
+                        "name": "minCarbs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum carbs in grams",
+                        "name": "maxCarbs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum fat in grams",
+                        "name": "minFat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum fat in grams",
+                        "name": "maxFat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key (name, calories, protein, proteinDensity), prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-100), defaults to 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Packages to list: all (default) for the catalog, your own and public packages; catalog; or mine",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived packages (ADMIN only)",
+                        "name": "includeArchived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackagePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a meal package to the catalog at version 1. Calories must agree with the macros within 15%, unless the package has a recipe of foods, from which calories, macros and ingredients are computed. Requires the ADMIN role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Create a meal package",
+                "parameters": [
+                    {
+                        "description": "Meal package",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createMealPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/packages/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns details of a specific meal package, with suitsGoal set for the caller's current goal. Private packages are visible only to their owner, and packages shared by link need their share token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Get a meal package by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a meal package with a new version. The request names the version it replaces and fails with 409 if another update came first. Entries logged with earlier versions keep their values. Catalog packages require the ADMIN role; users may update their own packages and change their visibility.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Update a meal package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meal package",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateMealPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hides a meal package from lists and stops new entries from using it. Existing entries and versions are kept. Catalog packages require the ADMIN role; users may archive their own packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Archive a meal package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/packages/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Copies a catalog package, a public package or one shared by link into a new package owned by the caller, with the changes in the request applied. A package with a recipe is recomputed from the current foods. The clone starts at version 1 and is private unless another visibility is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Clone a meal package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to change in the clone",
+                        "name": "changes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.cloneMealPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/packages/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes an archived meal package available again. Catalog packages require the ADMIN role; users may restore their own packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Restore an archived meal package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/packages/{id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a meal package as it was at the given version, such as the version a meal entry was logged with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Get a version of a meal package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Meal Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Package version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MealPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of all users in the system. Requires the ADMIN role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new user with the provided details and calculates their nutritional goals",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.userRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns details of a specific user. Users may only read their own profile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a user by ID. Users may only delete their own account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes body metrics, activity level or goal. In AUTO target mode the calorie and macro targets are recalculated, from the adaptive TDEE estimate when adaptiveTargets is on and the estimate is confident; in MANUAL mode the targets given by the user are kept. Users may only update their own profile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/macros/preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Validates a macro profile or custom percentages and returns the resulting daily grams for the user's weight and calorie target, without saving anything",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Preview macro targets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Macro profile to preview",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.macroPreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MacroTargets"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Computes BMI with its WHO category, BMR, TDEE, the ideal weight range and recommended macro grams from the user's current profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Get current health metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/metrics/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the health metrics snapshots stored whenever the user's profile or weight changed, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Get health metrics history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to 90 days before endDate",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "endDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/projection": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Estimates when the user reaches goal.targetWeight from their current weight and average daily intake against TDEE over recently logged days (workout burn is not subtracted, since TDEE already includes activity), falling back to the planned calorie target when fewer than 7 days are logged. Returns the projected date, a weekly weight curve and warnings when the rate of change exceeds safe limits (losing more than 1% or gaining more than 0.5% of body weight per week).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Project the goal timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of logs to average (7-90), defaults to 28",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "kcal per kg of weight change (3000-10000), defaults to 7700",
+                        "name": "energyDensity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalProjection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns calorie and macro targets, consumption, burn, net balance and progress toward the goal for one day",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get the daily calorie balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day to summarize (YYYY-MM-DD), defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DailySummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tdee": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Estimates the user's actual energy expenditure from logged meal calories and the trend of their weight entries over the days before today, with a confidence score from 0 to 1. A background job refreshes the stored estimate and, for users with adaptiveTargets on, recalculates AUTO targets from it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Get the adaptive TDEE estimate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window length in days (14-28), defaults to 28",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.adaptiveTDEEResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/trends": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a per-day series of intake, burn, net balance and macros for a date range, with days without logs filled in, plus seven-day aggregates and the protein/carb/fat calorie split. Defaults to the last seven days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "summary"
+                ],
+                "summary": "Get calorie balance trends",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to six days before endDate",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "endDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrendReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/entries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated user's weight entries within a date range with trailing moving averages, plus the change since the goal start date and progress toward the target weight",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weight"
+                ],
+                "summary": "Get weight history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to 30 days before endDate",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Moving average window in days (1-90), defaults to 7",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WeightHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs body weight and optional body fat percentage and girth measurements for the authenticated user. With updateProfile, the profile weight and body fat are set as well when the entry is the most recent one, which recalculates AUTO goal targets.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weight"
+                ],
+                "summary": "Log a weight entry",
+                "parameters": [
+                    {
+                        "description": "Weight entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.weightEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.weightEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/weight/entries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one of the authenticated user's weight entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weight"
+                ],
+                "summary": "Get a weight entry by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Weight Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WeightEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes one of the authenticated user's weight entries. The profile weight is left unchanged.",
+                "tags": [
+                    "weight"
+                ],
+                "summary": "Delete a weight entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Weight Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/entries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated user's workout entries within a date range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Get workout entries for the caller",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "endDate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WorkoutEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs a workout for the authenticated user with specified intensity and duration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Create a new workout entry",
+                "parameters": [
+                    {
+                        "description": "Workout entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.workoutEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/entries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one of the authenticated user's workout entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Get a workout entry by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes one of the authenticated user's workout entries",
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Delete a workout entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the package, intensity, duration or date of a workout entry. Calories burned are recomputed from the package version the entry was logged with, or from the current version when the package changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Update a workout entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateWorkoutEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/packages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one page of workout packages matching the filters. Filtering on LOSE or GAIN includes packages for BOTH goals, and suitsGoal marks the packages that fit the caller's current goal. Text search matches words in the name, description and instructions. Lists include the catalog, the caller's own packages and other users' public packages unless scope narrows them. Pass nextCursor from a response as cursor to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "List workout packages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Goal type filter (LOSE, GAIN, BOTH, ALL)",
+                        "name": "goalType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workout type filter, e.g. HIIT or CARDIO",
+                        "name": "workoutType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum calories burned",
+                        "name": "minCalories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum calories burned",
+                        "name": "maxCalories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum duration in minutes",
+                        "name": "minDuration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum duration in minutes",
+                        "name": "maxDuration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key (name, calories, duration, burnRate), prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-100), defaults to 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Packages to list: all (default) for the catalog, your own and public packages; catalog; or mine",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived packages (ADMIN only)",
+                        "name": "includeArchived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackagePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a workout package to the catalog at version 1. Requires the ADMIN role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Create a workout package",
+                "parameters": [
+                    {
+                        "description": "Workout package",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createWorkoutPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/packages/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns details of a specific workout package, with suitsGoal set for the caller's current goal. Private packages are visible only to their owner, and packages shared by link need their share token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Get a workout package by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a workout package with a new version. The request names the version it replaces and fails with 409 if another update came first. Entries logged with earlier versions keep their values. Catalog packages require the ADMIN role; users may update their own packages and change their visibility.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Update a workout package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workout package",
+                        "name": "package",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateWorkoutPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hides a workout package from lists and stops new entries from using it. Existing entries and versions are kept. Catalog packages require the ADMIN role; users may archive their own packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Archive a workout package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/packages/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Copies a catalog package, a public package or one shared by link into a new package owned by the caller, with the changes in the request applied. The clone starts at version 1 and is private unless another visibility is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Clone a workout package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to change in the clone",
+                        "name": "changes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.cloneWorkoutPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/packages/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes an archived workout package available again. Catalog packages require the ADMIN role; users may restore their own packages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Restore an archived workout package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/packages/{id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a workout package as it was at the given version, such as the version a workout entry was logged with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workouts"
+                ],
+                "summary": "Get a version of a workout package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workout Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Package version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of a package shared by link",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkoutPackage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.TokenPair": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "expiresIn": {
+                    "description": "Access token lifetime in seconds",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "tokenType": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "db.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.APIError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "NOT_FOUND"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "user 42: not found"
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "1742300000000000000123"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/handlers.APIError"
+                }
+            }
+        },
+        "handlers.adaptiveTDEEResponse": {
+            "type": "object",
+            "properties": {
+                "adaptiveTargets": {
+                    "description": "Whether the user opted in to adaptive targets",
+                    "type": "boolean"
+                },
+                "averageIntake": {
+                    "description": "kcal per logged day",
+                    "type": "integer",
+                    "example": 2150
+                },
+                "computedAt": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "description": "0 (none) to 1 (high)",
+                    "type": "number",
+                    "example": 0.72
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2023-03-17"
+                },
+                "formulaTdee": {
+                    "description": "BMR times the activity factor",
+                    "type": "integer",
+                    "example": 2480
+                },
+                "intakeDays": {
+                    "description": "Days with logged meals",
+                    "type": "integer",
+                    "example": 24
+                },
+                "reason": {
+                    "description": "Why the data is insufficient",
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2023-02-18"
+                },
+                "sufficient": {
+                    "description": "Whether there was enough data for an estimate",
+                    "type": "boolean"
+                },
+                "tdee": {
+                    "description": "kcal per day; 0 when the data is insufficient",
+                    "type": "integer",
+                    "example": 2650
+                },
+                "usable": {
+                    "description": "Confident enough to replace the formula TDEE",
+                    "type": "boolean"
+                },
+                "weighIns": {
+                    "description": "Weight entries in the window",
+                    "type": "integer",
+                    "example": 12
+                },
+                "weightChangePerWeek": {
+                    "description": "kg per week from the weight trend",
+                    "type": "number",
+                    "example": -0.45
+                },
+                "windowDays": {
+                    "type": "integer",
+                    "example": 28
+                }
+            }
+        },
+        "handlers.bodyMeasurementsRequest": {
+            "type": "object",
+            "properties": {
+                "arm": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 34
+                },
+                "chest": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 102
+                },
+                "hips": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 98
+                },
+                "neck": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 38
+                },
+                "thigh": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 58
+                },
+                "waist": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 86
+                }
+            }
+        },
+        "handlers.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "oldPassword"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 6,
+                    "example": "EvenMoreSecure456"
+                },
+                "oldPassword": {
+                    "type": "string",
+                    "example": "SecurePassword123"
+                }
+            }
+        },
+        "handlers.cloneMealPackageRequest": {
+            "type": "object",
+            "properties": {
+                "baseCalories": {
+                    "description": "Without a recipe, the result must be within 15% of the calories implied by the macros",
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 250
+                },
+                "baseCarbs": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 20
+                },
+                "baseFat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 5
+                },
+                "baseProtein": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 25
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "BREAKFAST"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Lighter Protein Bowl"
+                },
+                "preparationSteps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipe": {
+                    "description": "Replaces the recipe; an empty list removes it",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/handlers.recipeItemRequest"
+                    }
+                },
+                "visibility": {
+                    "description": "Defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                }
+            }
+        },
+        "handlers.cloneWorkoutPackageRequest": {
+            "type": "object",
+            "properties": {
+                "baseCaloriesBurn": {
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 200
+                },
+                "baseDurationMinutes": {
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 1,
+                    "example": 20
+                },
+                "caloriesBurnFormula": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Short HIIT"
+                },
+                "visibility": {
+                    "description": "Defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                },
+                "workoutType": {
+                    "type": "string",
+                    "maxLength": 30,
+                    "minLength": 1,
+                    "example": "HIIT"
+                }
+            }
+        },
+        "handlers.createFoodRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "barcode": {
+                    "description": "UPC-A, EAN-8, EAN-13 or GTIN-14 of a packaged food, optional",
+                    "type": "string",
+                    "example": "0012345678905"
+                },
+                "brand": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Acme"
+                },
+                "foodId": {
+                    "description": "Generated when omitted, or derived from the barcode",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "chicken-breast"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Chicken breast, cooked"
+                },
+                "per100g": {
+                    "$ref": "#/definitions/handlers.nutrientsRequest"
+                },
+                "servingGrams": {
+                    "description": "Labelled serving size, optional",
+                    "type": "number",
+                    "maximum": 5000,
+                    "minimum": 0,
+                    "example": 30
+                }
+            }
+        },
+        "handlers.createMealPackageRequest": {
+            "type": "object",
+            "required": [
+                "goalType",
+                "mealType",
+                "name"
+            ],
+            "properties": {
+                "baseCalories": {
+                    "description": "Required without a recipe; must be within 15% of the calories implied by the macros",
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 0,
+                    "example": 300
+                },
+                "baseCarbs": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 30
+                },
+                "baseFat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 7
+                },
+                "baseProtein": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 25
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Greek yogurt topped with berries and low-sugar granola"
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://example.com/bowl.jpg"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "BREAKFAST"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Protein Breakfast Bowl"
+                },
+                "packageId": {
+                    "description": "Generated when omitted",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "meal8"
+                },
+                "preparationSteps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipe": {
+                    "description": "When given, calories, macros and ingredients are computed from it",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/handlers.recipeItemRequest"
+                    }
+                },
+                "visibility": {
+                    "description": "Only for packages owned by a user; defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                }
+            }
+        },
+        "handlers.createWorkoutPackageRequest": {
+            "type": "object",
+            "required": [
+                "baseCaloriesBurn",
+                "baseDurationMinutes",
+                "goalType",
+                "name",
+                "workoutType"
+            ],
+            "properties": {
+                "baseCaloriesBurn": {
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 300
+                },
+                "baseDurationMinutes": {
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 1,
+                    "example": 30
+                },
+                "caloriesBurnFormula": {
+                    "description": "Defaults to the standard formula",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "High-intensity interval training to maximize calorie burn"
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://example.com/hiit.jpg"
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "HIIT Fat Burner"
+                },
+                "packageId": {
+                    "description": "Generated when omitted",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "workout4"
+                },
+                "visibility": {
+                    "description": "Only for packages owned by a user; defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                },
+                "workoutType": {
+                    "type": "string",
+                    "maxLength": 30,
+                    "example": "HIIT"
+                }
+            }
+        },
+        "handlers.customMealEntryRequest": {
+            "type": "object",
+            "required": [
+                "calories",
+                "date",
+                "mealType",
+                "name"
+            ],
+            "properties": {
+                "calories": {
+                    "description": "Must be within 15% of the calories implied by the macros",
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 650
+                },
+                "carbs": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 60
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "fat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 28
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "DINNER"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Homemade lasagna"
+                },
+                "protein": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 35
+                }
+            }
+        },
+        "handlers.foodEntryRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "mealType",
+                "servings"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "SNACK"
+                },
+                "servings": {
+                    "type": "number",
+                    "maximum": 20,
+                    "example": 1.5
+                }
+            }
+        },
+        "handlers.foodRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Acme"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Chicken breast, cooked"
+                },
+                "per100g": {
+                    "$ref": "#/definitions/handlers.nutrientsRequest"
+                },
+                "servingGrams": {
+                    "description": "Labelled serving size, optional",
+                    "type": "number",
+                    "maximum": 5000,
+                    "minimum": 0,
+                    "example": 30
+                }
+            }
+        },
+        "handlers.loginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "SecurePassword123"
+                }
+            }
+        },
+        "handlers.loginResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "expiresIn": {
+                    "description": "Access token lifetime in seconds",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "tokenType": {
+                    "type": "string",
+                    "example": "Bearer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "handlers.macroPreviewRequest": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "description": "Defaults to the current calorie target",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 800,
+                    "example": 2200
+                },
+                "customMacros": {
+                    "$ref": "#/definitions/models.MacroSplit"
+                },
+                "macroProfile": {
+                    "type": "string",
+                    "enum": [
+                        "BALANCED",
+                        "HIGH_PROTEIN_CUT",
+                        "LEAN_BULK",
+                        "KETO",
+                        "LOW_FAT",
+                        "CUSTOM"
+                    ],
+                    "example": "KETO"
+                }
+            }
+        },
+        "handlers.mealEntryRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "packageId",
+                "portionMultiplier"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "packageId": {
+                    "type": "string",
+                    "example": "meal1"
+                },
+                "portionMultiplier": {
+                    "type": "number",
+                    "maximum": 3,
+                    "minimum": 0.1,
+                    "example": 1
+                },
+                "shareToken": {
+                    "description": "Needed for packages shared by link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.nutrientsRequest": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "number",
+                    "maximum": 900,
+                    "minimum": 0,
+                    "example": 165
+                },
+                "carbs": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 0
+                },
+                "fat": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 3.6
+                },
+                "fiber": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 0
+                },
+                "protein": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 31
+                },
+                "sodium": {
+                    "description": "Milligrams",
+                    "type": "number",
+                    "maximum": 100000,
+                    "minimum": 0,
+                    "example": 74
+                },
+                "sugar": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "handlers.promoteMealEntryRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Grandma's recipe, one slice"
+                },
+                "goalType": {
+                    "description": "Defaults to BOTH",
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "BOTH"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Defaults to the entry's name",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Homemade lasagna"
+                },
+                "visibility": {
+                    "description": "Defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                }
+            }
+        },
+        "handlers.recipeItemRequest": {
+            "type": "object",
+            "required": [
+                "foodId",
+                "grams"
+            ],
+            "properties": {
+                "foodId": {
+                    "type": "string",
+                    "example": "chicken-breast"
+                },
+                "grams": {
+                    "type": "number",
+                    "maximum": 5000,
+                    "example": 150
+                }
+            }
+        },
+        "handlers.refreshRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateFoodResponse": {
+            "type": "object",
+            "properties": {
+                "food": {
+                    "$ref": "#/definitions/models.Food"
+                },
+                "recomputedPackages": {
+                    "description": "IDs of the meal packages that got a new version",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.updateMealEntryRequest": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 650
+                },
+                "carbs": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 60
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "fat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 28
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "DINNER"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Homemade lasagna"
+                },
+                "packageId": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "meal2"
+                },
+                "portionMultiplier": {
+                    "type": "number",
+                    "maximum": 3,
+                    "minimum": 0.1,
+                    "example": 1.5
+                },
+                "protein": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 35
+                },
+                "servings": {
+                    "type": "number",
+                    "maximum": 20,
+                    "example": 2
+                },
+                "shareToken": {
+                    "description": "Needed when changing to a package shared by link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateMealPackageRequest": {
+            "type": "object",
+            "required": [
+                "goalType",
+                "mealType",
+                "name",
+                "version"
+            ],
+            "properties": {
+                "baseCalories": {
+                    "description": "Required without a recipe; must be within 15% of the calories implied by the macros",
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 0,
+                    "example": 300
+                },
+                "baseCarbs": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 30
+                },
+                "baseFat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 7
+                },
+                "baseProtein": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 25
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Greek yogurt topped with berries and low-sugar granola"
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://example.com/bowl.jpg"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mealType": {
+                    "type": "string",
+                    "enum": [
+                        "BREAKFAST",
+                        "LUNCH",
+                        "DINNER",
+                        "SNACK"
+                    ],
+                    "example": "BREAKFAST"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Protein Breakfast Bowl"
+                },
+                "preparationSteps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipe": {
+                    "description": "When given, calories, macros and ingredients are computed from it",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/handlers.recipeItemRequest"
+                    }
+                },
+                "version": {
+                    "description": "The version being replaced",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "visibility": {
+                    "description": "Only for packages owned by a user; defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                }
+            }
+        },
+        "handlers.updateUserRequest": {
+            "type": "object",
+            "properties": {
+                "activityLevel": {
+                    "type": "string",
+                    "enum": [
+                        "SEDENTARY",
+                        "LOW",
+                        "MODERATE",
+                        "HIGH",
+                        "VERY_HIGH"
+                    ],
+                    "example": "HIGH"
+                },
+                "adaptiveTargets": {
+                    "description": "Base AUTO targets on the adaptive TDEE estimate",
+                    "type": "boolean",
+                    "example": true
+                },
+                "birthDate": {
+                    "type": "string",
+                    "example": "1990-01-01"
+                },
+                "bmrFormula": {
+                    "type": "string",
+                    "enum": [
+                        "HARRIS_BENEDICT",
+                        "MIFFLIN_ST_JEOR",
+                        "KATCH_MCARDLE"
+                    ],
+                    "example": "KATCH_MCARDLE"
+                },
+                "bodyFatPercent": {
+                    "description": "0 clears it",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 18.5
+                },
+                "customMacros": {
+                    "$ref": "#/definitions/models.MacroSplit"
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "MALE",
+                        "FEMALE",
+                        "OTHER"
+                    ],
+                    "example": "MALE"
+                },
+                "goal": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN"
+                    ],
+                    "example": "LOSE"
+                },
+                "height": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 180
+                },
+                "macroProfile": {
+                    "type": "string",
+                    "enum": [
+                        "BALANCED",
+                        "HIGH_PROTEIN_CUT",
+                        "LEAN_BULK",
+                        "KETO",
+                        "LOW_FAT",
+                        "CUSTOM"
+                    ],
+                    "example": "LEAN_BULK"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "John Doe"
+                },
+                "targetCalories": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 800,
+                    "example": 2200
+                },
+                "targetCarbs": {
+                    "type": "integer",
+                    "maximum": 1500,
+                    "minimum": 0,
+                    "example": 220
+                },
+                "targetFat": {
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 0,
+                    "example": 70
+                },
+                "targetMode": {
+                    "type": "string",
+                    "enum": [
+                        "AUTO",
+                        "MANUAL"
+                    ],
+                    "example": "MANUAL"
+                },
+                "targetProtein": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0,
+                    "example": 160
+                },
+                "targetWeight": {
+                    "type": "number",
+                    "maximum": 700,
+                    "minimum": 0,
+                    "example": 72
+                },
+                "weight": {
+                    "type": "number",
+                    "maximum": 700,
+                    "example": 78.5
+                }
+            }
+        },
+        "handlers.updateWorkoutEntryRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "durationMinutes": {
+                    "type": "integer",
+                    "maximum": 180,
+                    "minimum": 5,
+                    "example": 45
+                },
+                "intensityMultiplier": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0.5,
+                    "example": 1.2
+                },
+                "packageId": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "workout2"
+                },
+                "shareToken": {
+                    "description": "Needed when changing to a package shared by link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateWorkoutPackageRequest": {
+            "type": "object",
+            "required": [
+                "baseCaloriesBurn",
+                "baseDurationMinutes",
+                "goalType",
+                "name",
+                "version",
+                "workoutType"
+            ],
+            "properties": {
+                "baseCaloriesBurn": {
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 300
+                },
+                "baseDurationMinutes": {
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 1,
+                    "example": 30
+                },
+                "caloriesBurnFormula": {
+                    "description": "Defaults to the standard formula",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "High-intensity interval training to maximize calorie burn"
+                },
+                "goalType": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
+                    "example": "LOSE"
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "example": "https://example.com/hiit.jpg"
+                },
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "HIIT Fat Burner"
+                },
+                "version": {
+                    "description": "The version being replaced",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "visibility": {
+                    "description": "Only for packages owned by a user; defaults to PRIVATE",
+                    "type": "string",
+                    "enum": [
+                        "PRIVATE",
+                        "LINK",
+                        "PUBLIC"
+                    ],
+                    "example": "PRIVATE"
+                },
+                "workoutType": {
+                    "type": "string",
+                    "maxLength": 30,
+                    "example": "HIIT"
+                }
+            }
+        },
+        "handlers.userRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "activityLevel",
+                "birthDate",
+                "email",
+                "gender",
+                "goal",
+                "height",
+                "name",
+                "password",
+                "weight"
+            ],
+            "properties": {
+                "activityLevel": {
+                    "type": "string",
+                    "enum": [
+                        "SEDENTARY",
+                        "LOW",
+                        "MODERATE",
+                        "HIGH",
+                        "VERY_HIGH"
+                    ],
+                    "example": "MODERATE"
+                },
+                "birthDate": {
+                    "type": "string",
+                    "example": "1990-01-01"
+                },
+                "bmrFormula": {
+                    "type": "string",
+                    "enum": [
+                        "HARRIS_BENEDICT",
+                        "MIFFLIN_ST_JEOR",
+                        "KATCH_MCARDLE"
+                    ],
+                    "example": "MIFFLIN_ST_JEOR"
+                },
+                "bodyFatPercent": {
+                    "type": "number",
+                    "example": 18.5
+                },
+                "customMacros": {
+                    "$ref": "#/definitions/models.MacroSplit"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "MALE",
+                        "FEMALE",
+                        "OTHER"
+                    ],
+                    "example": "MALE"
+                },
+                "goal": {
+                    "type": "string",
+                    "enum": [
+                        "LOSE",
+                        "GAIN"
+                    ],
+                    "example": "LOSE"
+                },
+                "height": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 180
+                },
+                "macroProfile": {
+                    "type": "string",
+                    "enum": [
+                        "BALANCED",
+                        "HIGH_PROTEIN_CUT",
+                        "LEAN_BULK",
+                        "KETO",
+                        "LOW_FAT",
+                        "CUSTOM"
+                    ],
+                    "example": "HIGH_PROTEIN_CUT"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 6,
+                    "example": "SecurePassword123"
+                },
+                "weight": {
+                    "type": "number",
+                    "maximum": 700,
+                    "example": 80
+                }
+            }
+        },
+        "handlers.weightEntryRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "weight"
+            ],
+            "properties": {
+                "bodyFatPercent": {
+                    "type": "number",
+                    "example": 21.5
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "measurements": {
+                    "$ref": "#/definitions/handlers.bodyMeasurementsRequest"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Morning, before breakfast"
+                },
+                "updateProfile": {
+                    "description": "Also set the profile weight and body fat if this is the latest entry",
+                    "type": "boolean",
+                    "example": true
+                },
+                "weight": {
+                    "type": "number",
+                    "maximum": 700,
+                    "example": 79.4
+                }
+            }
+        },
+        "handlers.weightEntryResponse": {
+            "type": "object",
+            "properties": {
+                "bodyFatPercent": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "Day the entry applies to; used for querying by date range",
+                    "type": "string"
+                },
+                "entryId": {
+                    "type": "string"
+                },
+                "measurements": {
+                    "$ref": "#/definitions/models.BodyMeasurements"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "profileUpdated": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "description": "When the entry was logged",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "Kilograms",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.workoutEntryRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "durationMinutes",
+                "intensityMultiplier",
+                "packageId"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "durationMinutes": {
+                    "type": "integer",
+                    "maximum": 180,
+                    "minimum": 5,
+                    "example": 30
+                },
+                "intensityMultiplier": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0.5,
+                    "example": 1
+                },
+                "packageId": {
+                    "type": "string",
+                    "example": "workout1"
+                },
+                "shareToken": {
+                    "description": "Needed for packages shared by link",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityLevel": {
+            "type": "string",
+            "enum": [
+                "SEDENTARY",
+                "LOW",
+                "MODERATE",
+                "HIGH",
+                "VERY_HIGH"
+            ],
+            "x-enum-varnames": [
+                "ActivitySedentary",
+                "ActivityLow",
+                "ActivityModerate",
+                "ActivityHigh",
+                "ActivityVeryHigh"
+            ]
+        },
+        "models.BMICategory": {
+            "type": "string",
+            "enum": [
+                "UNDERWEIGHT",
+                "NORMAL",
+                "OVERWEIGHT",
+                "OBESE_CLASS_I",
+                "OBESE_CLASS_II",
+                "OBESE_CLASS_III"
+            ],
+            "x-enum-comments": {
+                "BMINormal": "18.5 to 24.9",
+                "BMIObeseClass1": "30 to 34.9",
+                "BMIObeseClass2": "35 to 39.9",
+                "BMIObeseClass3": "40 and above",
+                "BMIOverweight": "25 to 29.9",
+                "BMIUnderweight": "Below 18.5"
+            },
+            "x-enum-varnames": [
+                "BMIUnderweight",
+                "BMINormal",
+                "BMIOverweight",
+                "BMIObeseClass1",
+                "BMIObeseClass2",
+                "BMIObeseClass3"
+            ]
+        },
+        "models.BMRFormula": {
+            "type": "string",
+            "enum": [
+                "HARRIS_BENEDICT",
+                "MIFFLIN_ST_JEOR",
+                "KATCH_MCARDLE"
+            ],
+            "x-enum-varnames": [
+                "FormulaHarrisBenedict",
+                "FormulaMifflinStJeor",
+                "FormulaKatchMcArdle"
+            ]
+        },
+        "models.BodyMeasurements": {
+            "type": "object",
+            "properties": {
+                "arm": {
+                    "type": "number"
+                },
+                "chest": {
+                    "type": "number"
+                },
+                "hips": {
+                    "type": "number"
+                },
+                "neck": {
+                    "type": "number"
+                },
+                "thigh": {
+                    "type": "number"
+                },
+                "waist": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CalorieBalance": {
+            "type": "object",
+            "properties": {
+                "burned": {
+                    "type": "integer"
+                },
+                "consumed": {
+                    "type": "integer"
+                },
+                "net": {
+                    "description": "Consumed minus burned",
+                    "type": "integer"
+                },
+                "percentOfGoal": {
+                    "description": "Net as a percentage of target",
+                    "type": "number"
+                },
+                "remaining": {
+                    "description": "Target minus net; negative when over target",
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DailySummary": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "$ref": "#/definitions/models.CalorieBalance"
+                },
+                "carbs": {
+                    "$ref": "#/definitions/models.MacroBalance"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "fat": {
+                    "$ref": "#/definitions/models.MacroBalance"
+                },
+                "goalType": {
+                    "$ref": "#/definitions/models.GoalType"
+                },
+                "mealCount": {
+                    "type": "integer"
+                },
+                "protein": {
+                    "$ref": "#/definitions/models.MacroBalance"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "workoutCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Food": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "description": "Normalized GTIN, see package gtin",
+                    "type": "string",
+                    "example": "0036000291452"
+                },
+                "brand": {
+                    "type": "string",
+                    "example": "Acme"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "foodId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Chicken breast, cooked"
+                },
+                "per100g": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
+                "servingGrams": {
+                    "description": "Labelled serving size, when known",
+                    "type": "number",
+                    "example": 30
+                },
+                "source": {
+                    "description": "Empty for foods added through the API",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FoodSource"
+                        }
+                    ]
+                },
+                "sourceId": {
+                    "description": "The food's ID in its source",
+                    "type": "string",
+                    "example": "171077"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FoodPage": {
+            "type": "object",
+            "properties": {
+                "foods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Food"
+                    }
+                },
+                "nextCursor": {
+                    "description": "Pass as cursor to get the next page; omitted on the last page",
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSJ9"
+                },
+                "total": {
+                    "description": "Foods matching the search, across all pages",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.FoodServing": {
+            "type": "object",
+            "properties": {
+                "food": {
+                    "$ref": "#/definitions/models.Food"
+                },
+                "perServing": {
+                    "$ref": "#/definitions/models.Nutrients"
+                },
+                "servingGrams": {
+                    "description": "The labelled serving, or 100 g when the food has none",
+                    "type": "number",
+                    "example": 30
+                }
+            }
+        },
+        "models.FoodSource": {
+            "type": "string",
+            "enum": [
+                "OPEN_FOOD_FACTS",
+                "USDA_FDC"
+            ],
+            "x-enum-varnames": [
+                "FoodSourceOpenFoodFacts",
+                "FoodSourceUSDA"
+            ]
+        },
+        "models.Gender": {
+            "type": "string",
+            "enum": [
+                "MALE",
+                "FEMALE",
+                "OTHER"
+            ],
+            "x-enum-varnames": [
+                "GenderMale",
+                "GenderFemale",
+                "GenderOther"
+            ]
+        },
+        "models.GoalInfo": {
+            "type": "object",
+            "properties": {
+                "adaptiveTdee": {
+                    "description": "TDEE comes from the adaptive estimate rather than the formula",
+                    "type": "boolean"
+                },
+                "bmr": {
+                    "type": "integer"
+                },
+                "formula": {
+                    "description": "Formula that produced the AUTO targets",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BMRFormula"
+                        }
+                    ]
+                },
+                "macroProfile": {
+                    "description": "Macro profile that produced the AUTO targets",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MacroProfile"
+                        }
+                    ]
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "startWeight": {
+                    "type": "number"
+                },
+                "targetCalories": {
+                    "type": "integer"
+                },
+                "targetCarbs": {
+                    "type": "integer"
+                },
+                "targetFat": {
+                    "type": "integer"
+                },
+                "targetMode": {
+                    "description": "Empty is treated as AUTO",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TargetMode"
+                        }
+                    ]
+                },
+                "targetProtein": {
+                    "type": "integer"
+                },
+                "targetWeight": {
+                    "type": "number"
+                },
+                "tdee": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.GoalType"
+                }
+            }
+        },
+        "models.GoalProjection": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ProjectionBasis"
+                        }
+                    ],
+                    "example": "LOGGED"
+                },
+                "currentWeight": {
+                    "type": "number",
+                    "example": 80
+                },
+                "curve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProjectionPoint"
+                    }
+                },
+                "dailyBalance": {
+                    "description": "kcal per day the projection uses; negative is a deficit",
+                    "type": "integer",
+                    "example": -450
+                },
+                "energyDensity": {
+                    "description": "kcal per kg of weight change",
+                    "type": "integer",
+                    "example": 7700
+                },
+                "loggedDays": {
+                    "description": "Days with logged meals in the window",
+                    "type": "integer",
+                    "example": 21
+                },
+                "plannedDailyBalance": {
+                    "description": "Calorie target minus TDEE",
+                    "type": "integer",
+                    "example": -500
+                },
+                "projectedDate": {
+                    "type": "string",
+                    "example": "2023-08-01"
+                },
+                "reachable": {
+                    "description": "Whether the target is reached within the projection horizon",
+                    "type": "boolean"
+                },
+                "targetWeight": {
+                    "type": "number",
+                    "example": 72
+                },
+                "tdee": {
+                    "description": "Maintenance calories the balance is measured against",
+                    "type": "integer",
+                    "example": 2600
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProjectionWarning"
+                    }
+                },
+                "weeklyChange": {
+                    "description": "kg per week",
+                    "type": "number",
+                    "example": -0.41
+                },
+                "weeklyChangePercent": {
+                    "description": "Percent of current weight per week",
+                    "type": "number",
+                    "example": -0.51
+                },
+                "weeksToTarget": {
+                    "type": "number",
+                    "example": 19.5
+                },
+                "windowDays": {
+                    "description": "Days of logs the balance is averaged over",
+                    "type": "integer",
+                    "example": 28
+                }
+            }
+        },
+        "models.GoalType": {
+            "type": "string",
+            "enum": [
+                "LOSE",
+                "GAIN",
+                "BOTH",
+                ""
+            ],
+            "x-enum-comments": {
+                "GoalTypeAll": "Used for filtering all goal types",
+                "GoalTypeBoth": "Packages only: suits either goal"
+            },
+            "x-enum-varnames": [
+                "GoalTypeLose",
+                "GoalTypeGain",
+                "GoalTypeBoth",
+                "GoalTypeAll"
+            ]
+        },
+        "models.MacroBalance": {
+            "type": "object",
+            "properties": {
+                "consumed": {
+                    "type": "integer"
+                },
+                "percentOfGoal": {
+                    "type": "number"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MacroProfile": {
+            "type": "string",
+            "enum": [
+                "BALANCED",
+                "HIGH_PROTEIN_CUT",
+                "LEAN_BULK",
+                "KETO",
+                "LOW_FAT",
+                "CUSTOM"
+            ],
+            "x-enum-comments": {
+                "MacroCustom": "User-supplied percentages"
+            },
+            "x-enum-varnames": [
+                "MacroBalanced",
+                "MacroHighProteinCut",
+                "MacroLeanBulk",
+                "MacroKeto",
+                "MacroLowFat",
+                "MacroCustom"
+            ]
+        },
+        "models.MacroSplit": {
+            "type": "object",
+            "properties": {
+                "carbsPercent": {
+                    "type": "number",
+                    "example": 40
+                },
+                "fatPercent": {
+                    "type": "number",
+                    "example": 30
+                },
+                "proteinPercent": {
+                    "type": "number",
+                    "example": 30
+                }
+            }
+        },
+        "models.MacroTargets": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "integer",
+                    "example": 2200
+                },
+                "carbs": {
+                    "description": "Grams",
+                    "type": "integer",
+                    "example": 198
+                },
+                "fat": {
+                    "description": "Grams",
+                    "type": "integer",
+                    "example": 61
+                },
+                "profile": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MacroProfile"
+                        }
+                    ],
+                    "example": "HIGH_PROTEIN_CUT"
+                },
+                "protein": {
+                    "description": "Grams",
+                    "type": "integer",
+                    "example": 176
+                },
+                "split": {
+                    "description": "Resulting share of calories",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MacroSplit"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.MealEntry": {
+            "type": "object",
+            "properties": {
+                "calories": {
+                    "type": "integer"
+                },
+                "carbs": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "custom": {
+                    "description": "Entered manually rather than from a package",
+                    "type": "boolean"
+                },
+                "date": {
+                    "description": "Day the entry applies to; used for querying by date range",
+                    "type": "string"
+                },
+                "entryId": {
+                    "type": "string"
+                },
+                "fat": {
+                    "type": "integer"
+                },
+                "foodId": {
+                    "description": "Food a food entry was logged from, e.g. by barcode",
+                    "type": "string"
+                },
+                "mealType": {
+                    "$ref": "#/definitions/models.MealType"
+                },
+                "name": {
+                    "description": "Name of a custom or food entry",
+                    "type": "string"
+                },
+                "packageId": {
+                    "description": "Empty for custom and food entries",
+                    "type": "string"
+                },
+                "packageVersion": {
+                    "description": "Package version the nutrition values come from; 0 for entries logged before versioning",
+                    "type": "integer"
+                },
+                "perServing": {
+                    "description": "Nutrition of one serving of a food entry, as the food was when logged",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Nutrients"
+                        }
+                    ]
+                },
+                "portionMultiplier": {
+                    "description": "Number of servings for food entries",
+                    "type": "number"
+                },
+                "protein": {
+                    "type": "integer"
+                },
+                "servingGrams": {
+                    "description": "Grams in one serving of a food entry",
+                    "type": "number"
+                },
+                "timestamp": {
+                    "description": "When the entry was logged",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MealPackage": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Archived packages are hidden from lists and cannot be logged",
+                    "type": "boolean"
+                },
+                "archivedAt": {
+                    "type": "string"
+                },
+                "baseCalories": {
+                    "type": "integer"
+                },
+                "baseCarbs": {
+                    "type": "integer"
+                },
+                "baseFat": {
+                    "type": "integer"
+                },
+                "baseProtein": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "goalType": {
+                    "description": "BOTH suits either goal",
                     "enum": [
                         "LOSE",
-                        "GAIN"
+                        "GAIN",
+                        "BOTH"
                     ],
-                    "example": "LOSE"
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GoalType"
+                        }
+                    ]
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "ingredients": {
+                    "description": "Derived from the recipe when there is one",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mealType": {
+                    "$ref": "#/definitions/models.MealType"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "description": "User who owns the package; empty for catalog packages",
+                    "type": "string"
+                },
+                "packageId": {
+                    "type": "string"
+                },
+                "preparationSteps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recipe": {
+                    "description": "When set, calories and macros are computed from it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeItem"
+                    }
+                },
+                "shareToken": {
+                    "description": "Grants access to LINK packages",
+                    "type": "string"
+                },
+                "suitsGoal": {
+                    "description": "Whether the package fits the requesting user's current goal; set per request",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update; entries record the version they were logged with",
+                    "type": "integer",
+                    "example": 1
                 },
-                "height": {
+                "visibility": {
+                    "description": "Set for user-owned packages",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PackageVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.MealPackagePage": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "description": "Pass as cursor to get the next page; omitted on the last page",
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSJ9"
+                },
+                "packages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MealPackage"
+                    }
+                },
+                "total": {
+                    "description": "Packages matching the filters, across all pages",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.MealType": {
+            "type": "string",
+            "enum": [
+                "BREAKFAST",
+                "LUNCH",
+                "DINNER",
+                "SNACK"
+            ],
+            "x-enum-varnames": [
+                "MealTypeBreakfast",
+                "MealTypeLunch",
+                "MealTypeDinner",
+                "MealTypeSnack"
+            ]
+        },
+        "models.Nutrients": {
+            "type": "object",
+            "properties": {
+                "calories": {
                     "type": "number",
-                    "example": 180
+                    "example": 165
                 },
-                "name": {
-                    "type": "string",
-                    "example": "John Doe"
+                "carbs": {
+                    "description": "Grams",
+                    "type": "number",
+                    "example": 0
                 },
-                "password": {
+                "fat": {
+                    "description": "Grams",
+                    "type": "number",
+                    "example": 3.6
+                },
+                "fiber": {
+                    "description": "Grams",
+                    "type": "number",
+                    "example": 0
+                },
+                "protein": {
+                    "description": "Grams",
+                    "type": "number",
+                    "example": 31
+                },
+                "sodium": {
+                    "description": "Milligrams",
+                    "type": "number",
+                    "example": 74
+                },
+                "sugar": {
+                    "description": "Grams",
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "models.PackageVisibility": {
+            "type": "string",
+            "enum": [
+                "PRIVATE",
+                "LINK",
+                "PUBLIC"
+            ],
+            "x-enum-comments": {
+                "VisibilityLink": "Anyone with the package's share token",
+                "VisibilityPrivate": "Only the owner",
+                "VisibilityPublic": "Everyone; listed alongside the catalog"
+            },
+            "x-enum-varnames": [
+                "VisibilityPrivate",
+                "VisibilityLink",
+                "VisibilityPublic"
+            ]
+        },
+        "models.ProjectionBasis": {
+            "type": "string",
+            "enum": [
+                "LOGGED",
+                "PLANNED"
+            ],
+            "x-enum-comments": {
+                "ProjectionLogged": "Average balance of recently logged days",
+                "ProjectionPlanned": "Calorie target minus TDEE, used when too few days are logged"
+            },
+            "x-enum-varnames": [
+                "ProjectionLogged",
+                "ProjectionPlanned"
+            ]
+        },
+        "models.ProjectionPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "SecurePassword123"
+                    "example": "2023-03-25"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
                 },
                 "weight": {
                     "type": "number",
-                    "example": 80
+                    "example": 79.4
                 }
             }
         },
-        "handlers.workoutEntryRequest": {
+        "models.ProjectionWarning": {
             "type": "object",
-            "required": [
-                "date",
-                "durationMinutes",
-                "intensityMultiplier",
-                "packageId",
-                "userId"
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "RATE_TOO_FAST"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Losing 1.2% of body weight per week exceeds the safe rate of 1%"
+                }
+            }
+        },
+        "models.RecipeItem": {
+            "type": "object",
+            "properties": {
+                "foodId": {
+                    "type": "string",
+                    "example": "chicken-breast"
+                },
+                "grams": {
+                    "type": "number",
+                    "example": 150
+                }
+            }
+        },
+        "models.Role": {
+            "type": "string",
+            "enum": [
+                "USER",
+                "ADMIN"
+            ],
+            "x-enum-varnames": [
+                "RoleUser",
+                "RoleAdmin"
+            ]
+        },
+        "models.TDEEEstimate": {
+            "type": "object",
+            "properties": {
+                "averageIntake": {
+                    "description": "kcal per logged day",
+                    "type": "integer",
+                    "example": 2150
+                },
+                "computedAt": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "description": "0 (none) to 1 (high)",
+                    "type": "number",
+                    "example": 0.72
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2023-03-17"
+                },
+                "intakeDays": {
+                    "description": "Days with logged meals",
+                    "type": "integer",
+                    "example": 24
+                },
+                "reason": {
+                    "description": "Why the data is insufficient",
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2023-02-18"
+                },
+                "sufficient": {
+                    "description": "Whether there was enough data for an estimate",
+                    "type": "boolean"
+                },
+                "tdee": {
+                    "description": "kcal per day; 0 when the data is insufficient",
+                    "type": "integer",
+                    "example": 2650
+                },
+                "weighIns": {
+                    "description": "Weight entries in the window",
+                    "type": "integer",
+                    "example": 12
+                },
+                "weightChangePerWeek": {
+                    "description": "kg per week from the weight trend",
+                    "type": "number",
+                    "example": -0.45
+                },
+                "windowDays": {
+                    "type": "integer",
+                    "example": 28
+                }
+            }
+        },
+        "models.TargetMode": {
+            "type": "string",
+            "enum": [
+                "AUTO",
+                "MANUAL"
             ],
+            "x-enum-comments": {
+                "TargetModeAuto": "Targets are recalculated when body metrics or the goal change",
+                "TargetModeManual": "Targets are pinned by the user and never recalculated"
+            },
+            "x-enum-varnames": [
+                "TargetModeAuto",
+                "TargetModeManual"
+            ]
+        },
+        "models.TrendDay": {
+            "type": "object",
             "properties": {
+                "balance": {
+                    "description": "Net minus target: positive is a surplus, negative a deficit",
+                    "type": "integer"
+                },
+                "burned": {
+                    "type": "integer"
+                },
+                "carbs": {
+                    "type": "integer"
+                },
                 "date": {
                     "type": "string",
                     "example": "2023-03-18"
                 },
-                "durationMinutes": {
-                    "type": "integer",
-                    "maximum": 180,
-                    "minimum": 5,
-                    "example": 30
+                "fat": {
+                    "type": "integer"
+                },
+                "hasLogs": {
+                    "type": "boolean"
+                },
+                "intake": {
+                    "type": "integer"
+                },
+                "net": {
+                    "description": "Intake minus burned",
+                    "type": "integer"
+                },
+                "protein": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TrendReport": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrendDay"
+                    }
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "macroSplit": {
+                    "$ref": "#/definitions/models.MacroSplit"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2023-03-12"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "weeks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrendWeek"
+                    }
+                }
+            }
+        },
+        "models.TrendWeek": {
+            "type": "object",
+            "properties": {
+                "averageBalance": {
+                    "description": "Average daily surplus (positive) or deficit (negative)",
+                    "type": "number"
+                },
+                "averageBurned": {
+                    "type": "number"
+                },
+                "averageIntake": {
+                    "type": "number"
+                },
+                "averageNet": {
+                    "type": "number"
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2023-03-18"
+                },
+                "loggedDays": {
+                    "type": "integer"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2023-03-12"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "activityLevel": {
+                    "$ref": "#/definitions/models.ActivityLevel"
+                },
+                "adaptiveTargets": {
+                    "description": "Base AUTO targets on the adaptive TDEE estimate when it is confident",
+                    "type": "boolean"
+                },
+                "adaptiveTdee": {
+                    "description": "Latest estimate from the background job",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TDEEEstimate"
+                        }
+                    ]
+                },
+                "birthDate": {
+                    "type": "string"
+                },
+                "bmrFormula": {
+                    "description": "Empty selects the default formula",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BMRFormula"
+                        }
+                    ]
+                },
+                "bodyFatPercent": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customMacros": {
+                    "description": "Percentages for the CUSTOM profile",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MacroSplit"
+                        }
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/models.Gender"
+                },
+                "goal": {
+                    "$ref": "#/definitions/models.GoalInfo"
+                },
+                "height": {
+                    "type": "number"
+                },
+                "lastLoginAt": {
+                    "type": "string"
+                },
+                "macroProfile": {
+                    "description": "Empty selects a profile for the goal type",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MacroProfile"
+                        }
+                    ]
                 },
-                "intensityMultiplier": {
-                    "type": "number",
-                    "maximum": 2,
-                    "minimum": 0.5,
-                    "example": 1
+                "name": {
+                    "type": "string"
                 },
-                "packageId": {
-                    "type": "string",
-                    "example": "workout1"
+                "role": {
+                    "$ref": "#/definitions/models.Role"
                 },
                 "userId": {
-                    "type": "string",
-                    "example": "usr1"
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "number"
                 }
             }
         },
-        "models.ActivityLevel": {
-            "type": "string",
-            "enum": [
-                "LOW",
-                "MODERATE",
-                "HIGH"
-            ],
-            "x-enum-varnames": [
-                "ActivityLow",
-                "ActivityModerate",
-                "ActivityHigh"
-            ]
-        },
-        "models.Gender": {
-            "type": "string",
-            "enum": [
-                "MALE",
-                "FEMALE",
-                "OTHER"
-            ],
-            "x-enum-varnames": [
-                "GenderMale",
-                "GenderFemale",
-                "GenderOther"
-            ]
-        },
-        "models.GoalInfo": {
+        "models.UserInfo": {
             "type": "object",
             "properties": {
-                "startDate": {
+                "activityLevel": {
+                    "$ref": "#/definitions/models.ActivityLevel"
+                },
+                "bmiCategory": {
+                    "$ref": "#/definitions/models.BMICategory"
+                },
+                "bmiRate": {
+                    "type": "number"
+                },
+                "bodyFatPercent": {
+                    "type": "number"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "startWeight": {
+                "formula": {
+                    "$ref": "#/definitions/models.BMRFormula"
+                },
+                "goal": {
+                    "$ref": "#/definitions/models.GoalInfo"
+                },
+                "height": {
                     "type": "number"
                 },
-                "targetCalories": {
-                    "type": "integer"
+                "idealWeightMax": {
+                    "type": "number"
                 },
-                "targetCarbs": {
+                "idealWeightMin": {
+                    "description": "Weight range for a normal BMI, in kg",
+                    "type": "number"
+                },
+                "metabolicRate": {
+                    "description": "BMR in kcal per day",
+                    "type": "number"
+                },
+                "recommendedCarbs": {
                     "type": "integer"
                 },
-                "targetFat": {
+                "recommendedFat": {
                     "type": "integer"
                 },
-                "targetProtein": {
+                "recommendedProtein": {
+                    "description": "Grams per day for the current goal",
                     "type": "integer"
                 },
-                "targetWeight": {
+                "snapshotId": {
+                    "description": "Empty for metrics computed on request",
+                    "type": "string"
+                },
+                "tdee": {
+                    "description": "Total daily energy expenditure in kcal",
                     "type": "number"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.GoalType"
+                "userId": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "number"
                 }
             }
         },
-        "models.GoalType": {
-            "type": "string",
-            "enum": [
-                "LOSE",
-                "GAIN",
-                ""
-            ],
-            "x-enum-comments": {
-                "GoalTypeAll": "Used for filtering all goal types"
-            },
-            "x-enum-varnames": [
-                "GoalTypeLose",
-                "GoalTypeGain",
-                "GoalTypeAll"
-            ]
-        },
-        "models.MealEntry": {
+        "models.WeightEntry": {
             "type": "object",
             "properties": {
-                "calories": {
-                    "type": "integer"
-                },
-                "carbs": {
-                    "type": "integer"
+                "bodyFatPercent": {
+                    "type": "number"
                 },
                 "createdAt": {
                     "type": "string"
                 },
                 "date": {
+                    "description": "Day the entry applies to; used for querying by date range",
                     "type": "string"
                 },
                 "entryId": {
                     "type": "string"
                 },
-                "fat": {
-                    "type": "integer"
-                },
-                "mealType": {
-                    "$ref": "#/definitions/models.MealType"
+                "measurements": {
+                    "$ref": "#/definitions/models.BodyMeasurements"
                 },
-                "packageId": {
+                "note": {
                     "type": "string"
                 },
-                "portionMultiplier": {
-                    "type": "number"
-                },
-                "protein": {
-                    "type": "integer"
-                },
                 "timestamp": {
-                    "description": "Used for querying by time range",
+                    "description": "When the entry was logged",
                     "type": "string"
                 },
                 "userId": {
                     "type": "string"
+                },
+                "weight": {
+                    "description": "Kilograms",
+                    "type": "number"
                 }
             }
         },
-        "models.MealPackage": {
+        "models.WeightHistory": {
             "type": "object",
             "properties": {
-                "baseCalories": {
-                    "type": "integer"
-                },
-                "baseCarbs": {
-                    "type": "integer"
-                },
-                "baseFat": {
-                    "type": "integer"
-                },
-                "baseProtein": {
-                    "type": "integer"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "goalType": {
-                    "description": "LOSE, GAIN, or BOTH",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.GoalType"
-                        }
-                    ]
-                },
-                "imageUrl": {
-                    "type": "string"
+                "endDate": {
+                    "type": "string",
+                    "example": "2023-03-18"
                 },
-                "ingredients": {
+                "entries": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.WeightHistoryEntry"
                     }
                 },
-                "mealType": {
-                    "$ref": "#/definitions/models.MealType"
+                "progress": {
+                    "$ref": "#/definitions/models.WeightProgress"
                 },
-                "name": {
-                    "type": "string"
+                "startDate": {
+                    "type": "string",
+                    "example": "2023-03-01"
                 },
-                "packageId": {
+                "userId": {
                     "type": "string"
                 },
-                "preparationSteps": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "windowDays": {
+                    "description": "Moving average window",
+                    "type": "integer",
+                    "example": 7
                 }
             }
         },
-        "models.MealType": {
-            "type": "string",
-            "enum": [
-                "BREAKFAST",
-                "LUNCH",
-                "DINNER",
-                "SNACK"
-            ],
-            "x-enum-varnames": [
-                "MealTypeBreakfast",
-                "MealTypeLunch",
-                "MealTypeDinner",
-                "MealTypeSnack"
-            ]
-        },
-        "models.User": {
+        "models.WeightHistoryEntry": {
             "type": "object",
             "properties": {
-                "activityLevel": {
-                    "$ref": "#/definitions/models.ActivityLevel"
-                },
-                "birthDate": {
-                    "type": "string"
+                "bodyFatPercent": {
+                    "type": "number"
                 },
                 "createdAt": {
                     "type": "string"
                 },
-                "email": {
+                "date": {
+                    "description": "Day the entry applies to; used for querying by date range",
                     "type": "string"
                 },
-                "gender": {
-                    "$ref": "#/definitions/models.Gender"
+                "entryId": {
+                    "type": "string"
                 },
-                "goal": {
-                    "$ref": "#/definitions/models.GoalInfo"
+                "measurements": {
+                    "$ref": "#/definitions/models.BodyMeasurements"
                 },
-                "height": {
+                "movingAverage": {
                     "type": "number"
                 },
-                "lastLoginAt": {
+                "note": {
                     "type": "string"
                 },
-                "name": {
+                "timestamp": {
+                    "description": "When the entry was logged",
                     "type": "string"
                 },
                 "userId": {
                     "type": "string"
                 },
                 "weight": {
+                    "description": "Kilograms",
+                    "type": "number"
+                }
+            }
+        },
+        "models.WeightProgress": {
+            "type": "object",
+            "properties": {
+                "changeSinceStart": {
+                    "description": "Negative when weight was lost",
+                    "type": "number"
+                },
+                "currentWeight": {
+                    "type": "number"
+                },
+                "percentToTarget": {
+                    "description": "Omitted when no target weight is set",
+                    "type": "number"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "startWeight": {
+                    "type": "number"
+                },
+                "targetWeight": {
                     "type": "number"
                 }
             }
@@ -832,6 +6662,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "date": {
+                    "description": "Day the entry applies to; used for querying by date range",
                     "type": "string"
                 },
                 "durationMinutes": {
@@ -846,8 +6677,12 @@ const docTemplate = `{
                 "packageId": {
                     "type": "string"
                 },
+                "packageVersion": {
+                    "description": "Package version the burn values come from; 0 for entries logged before versioning",
+                    "type": "integer"
+                },
                 "timestamp": {
-                    "description": "Used for querying by time range",
+                    "description": "When the entry was logged",
                     "type": "string"
                 },
                 "userId": {
@@ -858,6 +6693,13 @@ const docTemplate = `{
         "models.WorkoutPackage": {
             "type": "object",
             "properties": {
+                "archived": {
+                    "description": "Archived packages are hidden from lists and cannot be logged",
+                    "type": "boolean"
+                },
+                "archivedAt": {
+                    "type": "string"
+                },
                 "baseCaloriesBurn": {
                     "type": "integer"
                 },
@@ -867,11 +6709,19 @@ const docTemplate = `{
                 "caloriesBurnFormula": {
                     "type": "string"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "goalType": {
-                    "description": "LOSE, GAIN, or BOTH",
+                    "description": "BOTH suits either goal",
+                    "enum": [
+                        "LOSE",
+                        "GAIN",
+                        "BOTH"
+                    ],
                     "allOf": [
                         {
                             "$ref": "#/definitions/models.GoalType"
@@ -890,17 +6740,67 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "ownerId": {
+                    "description": "User who owns the package; empty for catalog packages",
+                    "type": "string"
+                },
                 "packageId": {
                     "type": "string"
                 },
+                "shareToken": {
+                    "description": "Grants access to LINK packages",
+                    "type": "string"
+                },
+                "suitsGoal": {
+                    "description": "Whether the package fits the requesting user's current goal; set per request",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update; entries record the version they were logged with",
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "description": "Set for user-owned packages",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PackageVisibility"
+                        }
+                    ]
+                },
                 "workoutType": {
                     "type": "string"
                 }
             }
+        },
+        "models.WorkoutPackagePage": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "description": "Pass as cursor to get the next page; omitted on the last page",
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSJ9"
+                },
+                "packages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WorkoutPackage"
+                    }
+                },
+                "total": {
+                    "description": "Packages matching the filters, across all pages",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
         }
     },
     "securityDefinitions": {
         "ApiKeyAuth": {
+            "description": "Access token from /auth/login, sent as \"Bearer \u003ctoken\u003e\"",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
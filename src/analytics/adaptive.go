@@ -0,0 +1,131 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// Adaptive TDEE estimation windows, in days
+const (
+	DefaultTDEEWindowDays = 28
+	MinTDEEWindowDays     = 14
+	MaxTDEEWindowDays     = 28
+)
+
+// Minimum data needed before an adaptive TDEE estimate is reported
+const (
+	minIntakeDays = 7
+	minWeighIns   = 3
+	minWeighSpan  = 7 // Days between the first and last weigh-in
+)
+
+// Estimates outside this range indicate incomplete logging rather than a real TDEE
+const (
+	minPlausibleTDEE = 1000
+	maxPlausibleTDEE = 6000
+)
+
+// trendErrorScale is the standard error of the energy imbalance, in kcal per
+// day, at which the precision component of the confidence score halves
+const trendErrorScale = 250
+
+// EstimateTDEE infers a user's actual energy expenditure over [startDate, endDate]
+// by energy balance: average logged intake minus the energy stored or released,
+// taken from a least-squares fit of the weight entries against time.
+// Days without logged meals are left out of the intake average rather than counted as zero.
+//
+// The confidence score multiplies the share of days with logged meals, the
+// weigh-in frequency relative to one every three days, and the precision of the
+// weight trend, so sparse or noisy logs give a low score.
+func EstimateTDEE(startDate, endDate time.Time, totals []models.DailyTotals, weights []models.WeightEntry, now time.Time) models.TDEEEstimate {
+	windowDays := int(endDate.Sub(startDate).Hours()/24) + 1
+
+	estimate := models.TDEEEstimate{
+		WindowDays: windowDays,
+		StartDate:  startDate.Format(DateLayout),
+		EndDate:    endDate.Format(DateLayout),
+		ComputedAt: now,
+	}
+
+	var intake int
+	for _, day := range totals {
+		if day.MealCount > 0 {
+			intake += day.Calories
+			estimate.IntakeDays++
+		}
+	}
+	estimate.WeighIns = len(weights)
+
+	if estimate.IntakeDays > 0 {
+		estimate.AverageIntake = int(math.Round(float64(intake) / float64(estimate.IntakeDays)))
+	}
+
+	switch {
+	case estimate.IntakeDays < minIntakeDays:
+		estimate.Reason = "Log meals on at least 7 days of the window"
+		return estimate
+	case estimate.WeighIns < minWeighIns:
+		estimate.Reason = "Log your weight at least 3 times in the window"
+		return estimate
+	case weights[len(weights)-1].Date.Sub(weights[0].Date) < minWeighSpan*24*time.Hour:
+		estimate.Reason = "Weigh-ins must span at least 7 days"
+		return estimate
+	}
+
+	slope, slopeErr := weightTrend(weights)
+	estimate.WeightChangePerWeek = math.Round(slope*7*100) / 100
+
+	// A falling weight means intake was below expenditure by the energy released
+	tdee := float64(estimate.AverageIntake) - slope*nutrition.EnergyPerKg
+	if tdee < minPlausibleTDEE || tdee > maxPlausibleTDEE {
+		estimate.Reason = "Estimate is outside the plausible range; check that all meals are logged"
+		return estimate
+	}
+
+	intakeCoverage := float64(estimate.IntakeDays) / float64(windowDays)
+	weighInCoverage := math.Min(1, float64(estimate.WeighIns)/(float64(windowDays)/3))
+	precision := 1 / (1 + slopeErr*nutrition.EnergyPerKg/trendErrorScale)
+
+	estimate.TDEE = int(math.Round(tdee))
+	estimate.Confidence = math.Round(intakeCoverage*weighInCoverage*precision*100) / 100
+	estimate.Sufficient = true
+	return estimate
+}
+
+// weightTrend fits weight against time by ordinary least squares and returns
+// the slope in kg per day together with its standard error.
+// Entries must be sorted by date and span more than one day.
+func weightTrend(weights []models.WeightEntry) (slope, slopeErr float64) {
+	n := float64(len(weights))
+	origin := weights[0].Date
+
+	var sumX, sumY float64
+	xs := make([]float64, len(weights))
+	for i, entry := range weights {
+		xs[i] = entry.Date.Sub(origin).Hours() / 24
+		sumX += xs[i]
+		sumY += entry.Weight
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy float64
+	for i, entry := range weights {
+		dx := xs[i] - meanX
+		sxx += dx * dx
+		sxy += dx * (entry.Weight - meanY)
+	}
+	slope = sxy / sxx
+
+	if len(weights) > 2 {
+		var ssr float64
+		for i, entry := range weights {
+			residual := entry.Weight - (meanY + slope*(xs[i]-meanX))
+			ssr += residual * residual
+		}
+		slopeErr = math.Sqrt(ssr / (n - 2) / sxx)
+	}
+	return slope, slopeErr
+}
@@ -0,0 +1,206 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// Projection windows, in days of logs averaged for the daily balance
+const (
+	DefaultProjectionWindowDays = 28
+	MinProjectionWindowDays     = 7
+	MaxProjectionWindowDays     = 90
+)
+
+// minProjectionLogDays is the number of logged days needed before the
+// projection uses logged intake instead of the planned calorie target
+const minProjectionLogDays = 7
+
+// Safe rates of weight change, in percent of body weight per week
+const (
+	safeLossPercent = 1.0
+	safeGainPercent = 0.5
+)
+
+// maxProjectionWeeks is the horizon of the projection; targets further out are reported as unreachable
+const maxProjectionWeeks = 104
+
+// Projection warning codes
+const (
+	WarningRateTooFast        = "RATE_TOO_FAST"
+	WarningPlannedTooFast     = "PLANNED_RATE_TOO_FAST"
+	WarningWrongDirection     = "WRONG_DIRECTION"
+	WarningBeyondHorizon      = "TARGET_BEYOND_HORIZON"
+	WarningFewLogs            = "FEW_LOGGED_DAYS"
+	WarningTargetBelowHealthy = "TARGET_BELOW_HEALTHY_WEIGHT"
+)
+
+// Project estimates when a user reaches their target weight from the
+// current weight and a daily calorie balance against their TDEE.
+// The balance is the average intake over the logged days in totals, or the
+// planned deficit or surplus when fewer than minProjectionLogDays are logged.
+// Workout burn is not subtracted from intake: the TDEE already covers exercise
+// through the activity factor, or the weight trend for an adaptive TDEE, so
+// subtracting it as well would count exercise twice.
+// The curve is linear: one point per week until the target or the horizon.
+// Rates relative to body weight are left at zero when the current weight is unknown.
+func Project(user models.User, currentWeight float64, windowDays int, totals []models.DailyTotals, energyDensity int, today time.Time) models.GoalProjection {
+	tdee := nutrition.CalculateTargets(user, today).TDEE
+
+	projection := models.GoalProjection{
+		UserID:              user.ID,
+		CurrentWeight:       currentWeight,
+		TargetWeight:        user.Goal.TargetWeight,
+		Basis:               models.ProjectionPlanned,
+		TDEE:                tdee,
+		PlannedDailyBalance: user.Goal.TargetCalories - tdee,
+		WindowDays:          windowDays,
+		EnergyDensity:       energyDensity,
+		Curve:               make([]models.ProjectionPoint, 0),
+		Warnings:            make([]models.ProjectionWarning, 0),
+	}
+
+	var intake int
+	for _, day := range totals {
+		if day.MealCount > 0 {
+			intake += day.Calories
+			projection.LoggedDays++
+		}
+	}
+
+	projection.DailyBalance = projection.PlannedDailyBalance
+	if projection.LoggedDays >= minProjectionLogDays {
+		projection.Basis = models.ProjectionLogged
+		projection.DailyBalance = int(math.Round(float64(intake)/float64(projection.LoggedDays))) - tdee
+	} else {
+		projection.Warnings = append(projection.Warnings, models.ProjectionWarning{
+			Code:    WarningFewLogs,
+			Message: fmt.Sprintf("Only %d days with meals logged in the last %d days; projecting from the planned calorie target", projection.LoggedDays, windowDays),
+		})
+	}
+
+	weeklyChange := weeklyRate(projection.DailyBalance, energyDensity)
+	projection.WeeklyChange = round2(weeklyChange)
+	if currentWeight > 0 {
+		projection.WeeklyChangePercent = round2(weeklyChange / currentWeight * 100)
+	}
+
+	projection.Warnings = append(projection.Warnings, rateWarnings(projection, weeklyChange)...)
+
+	if idealMin, _ := nutrition.IdealWeightRange(user.Height); user.Goal.TargetWeight < idealMin {
+		projection.Warnings = append(projection.Warnings, models.ProjectionWarning{
+			Code:    WarningTargetBelowHealthy,
+			Message: fmt.Sprintf("The target weight is below %.1f kg, the lowest weight with a healthy BMI at your height", idealMin),
+		})
+	}
+
+	remaining := user.Goal.TargetWeight - currentWeight
+	switch {
+	case remaining == 0:
+		projection.Reachable = true
+		projection.ProjectedDate = today.Format(DateLayout)
+		return projection
+
+	case weeklyChange == 0 || math.Signbit(weeklyChange) != math.Signbit(remaining):
+		projection.Warnings = append(projection.Warnings, models.ProjectionWarning{
+			Code:    WarningWrongDirection,
+			Message: "At the current calorie balance your weight is not moving toward the target",
+		})
+
+	default:
+		weeks := remaining / weeklyChange
+		if weeks <= maxProjectionWeeks {
+			projection.Reachable = true
+			projection.WeeksToTarget = round1(weeks)
+			projection.ProjectedDate = today.AddDate(0, 0, int(math.Ceil(weeks*7))).Format(DateLayout)
+		} else {
+			projection.Warnings = append(projection.Warnings, models.ProjectionWarning{
+				Code:    WarningBeyondHorizon,
+				Message: fmt.Sprintf("At the current rate the target is more than %d weeks away", maxProjectionWeeks),
+			})
+		}
+	}
+
+	projection.Curve = curve(currentWeight, user.Goal.TargetWeight, weeklyChange, projection.Reachable, today)
+	return projection
+}
+
+// weeklyRate converts a daily calorie balance into kg of weight change per week
+func weeklyRate(dailyBalance, energyDensity int) float64 {
+	return float64(dailyBalance) * 7 / float64(energyDensity)
+}
+
+// rateWarnings flags projected and planned rates of change above the safe rates
+func rateWarnings(projection models.GoalProjection, weeklyChange float64) []models.ProjectionWarning {
+	var warnings []models.ProjectionWarning
+
+	if message, unsafe := unsafeRate(weeklyChange, projection.CurrentWeight); unsafe {
+		code := WarningRateTooFast
+		if projection.Basis == models.ProjectionPlanned {
+			code = WarningPlannedTooFast
+		}
+		warnings = append(warnings, models.ProjectionWarning{Code: code, Message: message})
+	}
+
+	// The plan can be unsafe even when the logs are not, e.g. when meals are under-logged
+	if projection.Basis == models.ProjectionLogged {
+		planned := weeklyRate(projection.PlannedDailyBalance, projection.EnergyDensity)
+		if message, unsafe := unsafeRate(planned, projection.CurrentWeight); unsafe {
+			warnings = append(warnings, models.ProjectionWarning{Code: WarningPlannedTooFast, Message: "Planned: " + message})
+		}
+	}
+
+	return warnings
+}
+
+// unsafeRate reports whether a weekly change exceeds the safe rate for its direction.
+// Without a known weight the rate cannot be judged.
+func unsafeRate(weeklyChange, weight float64) (string, bool) {
+	if weight <= 0 {
+		return "", false
+	}
+	percent := math.Abs(weeklyChange) / weight * 100
+
+	if weeklyChange < 0 && percent > safeLossPercent {
+		return fmt.Sprintf("Losing %.1f%% of body weight per week exceeds the safe rate of %.0f%%", percent, safeLossPercent), true
+	}
+	if weeklyChange > 0 && percent > safeGainPercent {
+		return fmt.Sprintf("Gaining %.1f%% of body weight per week exceeds the safe rate of %.1f%%", percent, safeGainPercent), true
+	}
+	return "", false
+}
+
+// curve projects the weight at the end of each week, stopping at the target when
+// it is reached and at the horizon otherwise
+func curve(current, target, weeklyChange float64, reachable bool, today time.Time) []models.ProjectionPoint {
+	points := make([]models.ProjectionPoint, 0)
+
+	for week := 1; week <= maxProjectionWeeks; week++ {
+		weight := current + weeklyChange*float64(week)
+		done := reachable && (weeklyChange < 0 && weight <= target || weeklyChange > 0 && weight >= target)
+		if done {
+			weight = target
+		}
+
+		points = append(points, models.ProjectionPoint{
+			Week:   week,
+			Date:   today.AddDate(0, 0, week*7).Format(DateLayout),
+			Weight: round1(weight),
+		})
+
+		if done {
+			break
+		}
+	}
+
+	return points
+}
+
+// round2 rounds to two decimal places
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
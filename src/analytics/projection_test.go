@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// projectionDay is the day the test projections are made on
+var projectionDay = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// projectionUser has a TDEE of 2759 kcal (1780 kcal BMR * 1.55) and plans a 500 kcal deficit
+var projectionUser = models.User{
+	ID:            "u1",
+	Gender:        models.GenderMale,
+	BirthDate:     time.Date(1994, 1, 15, 0, 0, 0, 0, time.UTC),
+	Weight:        80,
+	Height:        180,
+	ActivityLevel: models.ActivityModerate,
+	Goal:          models.GoalInfo{Type: models.GoalTypeLose, TargetWeight: 75, TargetCalories: 2259},
+}
+
+// loggedDays returns totals for days days with the given intake and workout burn
+func loggedDays(days, calories, burned int) []models.DailyTotals {
+	totals := make([]models.DailyTotals, days)
+	for i := range totals {
+		totals[i] = models.DailyTotals{Calories: calories, Burned: burned, MealCount: 3}
+	}
+	return totals
+}
+
+func TestProjectLoggedBalanceIgnoresWorkoutBurn(t *testing.T) {
+	got := Project(projectionUser, 80, 28, loggedDays(14, 2259, 400), 7700, projectionDay)
+
+	if got.Basis != models.ProjectionLogged || got.LoggedDays != 14 {
+		t.Fatalf("basis %s over %d days, want LOGGED over 14", got.Basis, got.LoggedDays)
+	}
+	// The TDEE already counts activity, so eating to target is the planned deficit
+	if got.TDEE != 2759 || got.DailyBalance != -500 {
+		t.Errorf("TDEE %d, balance %d kcal; want TDEE 2759, balance -500 kcal", got.TDEE, got.DailyBalance)
+	}
+	if got.DailyBalance != got.PlannedDailyBalance {
+		t.Errorf("balance %d kcal differs from the planned %d kcal", got.DailyBalance, got.PlannedDailyBalance)
+	}
+}
+
+func TestProjectWithoutWeight(t *testing.T) {
+	got := Project(projectionUser, 0, 28, loggedDays(14, 1200, 0), 7700, projectionDay)
+
+	if got.WeeklyChangePercent != 0 {
+		t.Errorf("WeeklyChangePercent = %v, want 0 without a weight", got.WeeklyChangePercent)
+	}
+	for _, warning := range got.Warnings {
+		if warning.Code == WarningRateTooFast || warning.Code == WarningPlannedTooFast {
+			t.Errorf("rate warning %s without a weight", warning.Code)
+		}
+	}
+	if _, err := json.Marshal(got); err != nil {
+		t.Errorf("projection does not encode: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package analytics computes calorie balance summaries and trends from logged entries
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// DateLayout is the format used for dates in analytics responses
+const DateLayout = "2006-01-02"
+
+// DailySummary computes the dashboard for one day from the user's goal targets
+// and the meal and workout entries logged for that day
+func DailySummary(user models.User, date time.Time, meals []models.MealEntry, workouts []models.WorkoutEntry) models.DailySummary {
+	var consumed, protein, carbs, fat, burned int
+	for _, meal := range meals {
+		consumed += meal.Calories
+		protein += meal.Protein
+		carbs += meal.Carbs
+		fat += meal.Fat
+	}
+	for _, workout := range workouts {
+		burned += workout.CaloriesBurned
+	}
+
+	goal := user.Goal
+	net := consumed - burned
+
+	return models.DailySummary{
+		UserID:   user.ID,
+		Date:     date.Format(DateLayout),
+		GoalType: goal.Type,
+		Calories: models.CalorieBalance{
+			Target:        goal.TargetCalories,
+			Consumed:      consumed,
+			Burned:        burned,
+			Net:           net,
+			Remaining:     goal.TargetCalories - net,
+			PercentOfGoal: percent(net, goal.TargetCalories),
+		},
+		Protein:      macroBalance(goal.TargetProtein, protein),
+		Carbs:        macroBalance(goal.TargetCarbs, carbs),
+		Fat:          macroBalance(goal.TargetFat, fat),
+		MealCount:    len(meals),
+		WorkoutCount: len(workouts),
+	}
+}
+
+// macroBalance compares grams consumed with the target
+func macroBalance(target, consumed int) models.MacroBalance {
+	return models.MacroBalance{
+		Target:        target,
+		Consumed:      consumed,
+		Remaining:     target - consumed,
+		PercentOfGoal: percent(consumed, target),
+	}
+}
+
+// percent returns value as a percentage of total, rounded to one decimal place.
+// A zero total yields zero rather than dividing by zero.
+func percent(value, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(value)/float64(total)*1000) / 10
+}
@@ -0,0 +1,114 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// Trend builds a per-day series for [startDate, endDate] from daily totals,
+// filling days without logs with zeros, and aggregates it into seven-day
+// weeks and an overall macro split
+func Trend(user models.User, startDate, endDate time.Time, totals []models.DailyTotals) models.TrendReport {
+	byDate := make(map[string]models.DailyTotals, len(totals))
+	for _, t := range totals {
+		byDate[t.Date] = t
+	}
+
+	target := user.Goal.TargetCalories
+	days := make([]models.TrendDay, 0)
+	var protein, carbs, fat int
+
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DateLayout)
+		t, hasLogs := byDate[date]
+		net := t.Calories - t.Burned
+
+		days = append(days, models.TrendDay{
+			Date:    date,
+			Intake:  t.Calories,
+			Burned:  t.Burned,
+			Net:     net,
+			Balance: net - target,
+			Protein: t.Protein,
+			Carbs:   t.Carbs,
+			Fat:     t.Fat,
+			HasLogs: hasLogs,
+			Target:  target,
+		})
+
+		protein += t.Protein
+		carbs += t.Carbs
+		fat += t.Fat
+	}
+
+	return models.TrendReport{
+		UserID:     user.ID,
+		StartDate:  startDate.Format(DateLayout),
+		EndDate:    endDate.Format(DateLayout),
+		Days:       days,
+		Weeks:      weeks(days),
+		MacroSplit: macroSplit(protein, carbs, fat),
+	}
+}
+
+// weeks groups days into consecutive seven-day blocks starting at the first day.
+// The last block may be shorter.
+func weeks(days []models.TrendDay) []models.TrendWeek {
+	result := make([]models.TrendWeek, 0, (len(days)+6)/7)
+
+	for start := 0; start < len(days); start += 7 {
+		end := min(start+7, len(days))
+		block := days[start:end]
+
+		week := models.TrendWeek{
+			StartDate: block[0].Date,
+			EndDate:   block[len(block)-1].Date,
+		}
+
+		var intake, burned, net, balance int
+		for _, day := range block {
+			if !day.HasLogs {
+				continue
+			}
+			week.LoggedDays++
+			intake += day.Intake
+			burned += day.Burned
+			net += day.Net
+			balance += day.Balance
+		}
+
+		if week.LoggedDays > 0 {
+			n := float64(week.LoggedDays)
+			week.AverageIntake = round1(float64(intake) / n)
+			week.AverageBurned = round1(float64(burned) / n)
+			week.AverageNet = round1(float64(net) / n)
+			week.AverageBalance = round1(float64(balance) / n)
+		}
+
+		result = append(result, week)
+	}
+
+	return result
+}
+
+// macroSplit returns the percentage of calories contributed by each macronutrient
+func macroSplit(protein, carbs, fat int) models.MacroSplit {
+	proteinCalories := protein * nutrition.CaloriesPerGramProtein
+	carbsCalories := carbs * nutrition.CaloriesPerGramCarbs
+	fatCalories := fat * nutrition.CaloriesPerGramFat
+	total := proteinCalories + carbsCalories + fatCalories
+
+	return models.MacroSplit{
+		ProteinPercent: percent(proteinCalories, total),
+		CarbsPercent:   percent(carbsCalories, total),
+		FatPercent:     percent(fatCalories, total),
+	}
+}
+
+// round1 rounds to one decimal place
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
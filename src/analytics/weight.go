@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// DefaultWeightWindowDays is the default moving average window for weight history
+const DefaultWeightWindowDays = 7
+
+// WeightHistory builds a user's weight history for [startDate, endDate].
+// Entries must be sorted by date and may start up to windowDays-1 days before
+// startDate; those earlier entries only contribute to the moving averages.
+// latest is the user's most recent weight entry overall, or nil if there is none.
+func WeightHistory(user models.User, startDate, endDate time.Time, windowDays int, entries []models.WeightEntry, latest *models.WeightEntry) models.WeightHistory {
+	history := make([]models.WeightHistoryEntry, 0, len(entries))
+
+	for i, entry := range entries {
+		if entry.Date.Before(startDate) {
+			continue
+		}
+
+		// Trailing window: entries dated within the windowDays days ending at this entry
+		windowStart := entry.Date.AddDate(0, 0, -(windowDays - 1))
+		var sum float64
+		var count int
+		for j := i; j >= 0 && !entries[j].Date.Before(windowStart); j-- {
+			sum += entries[j].Weight
+			count++
+		}
+
+		history = append(history, models.WeightHistoryEntry{
+			WeightEntry:   entry,
+			MovingAverage: round1(sum / float64(count)),
+		})
+	}
+
+	return models.WeightHistory{
+		UserID:     user.ID,
+		StartDate:  startDate.Format(DateLayout),
+		EndDate:    endDate.Format(DateLayout),
+		WindowDays: windowDays,
+		Entries:    history,
+		Progress:   WeightProgress(user, latest),
+	}
+}
+
+// WeightProgress compares the user's current weight with their goal.
+// The current weight is the latest logged entry, falling back to the profile weight.
+func WeightProgress(user models.User, latest *models.WeightEntry) models.WeightProgress {
+	startWeight := user.Goal.StartWeight
+	if startWeight == 0 {
+		startWeight = user.Weight
+	}
+
+	currentWeight := user.Weight
+	if latest != nil {
+		currentWeight = latest.Weight
+	}
+
+	progress := models.WeightProgress{
+		StartDate:        user.Goal.StartDate,
+		StartWeight:      startWeight,
+		CurrentWeight:    currentWeight,
+		TargetWeight:     user.Goal.TargetWeight,
+		ChangeSinceStart: round1(currentWeight - startWeight),
+	}
+
+	// Progress is the share of the start-to-target distance covered so far;
+	// it is negative when moving away from the target
+	if target := user.Goal.TargetWeight; target > 0 && target != startWeight {
+		percent := round1((startWeight - currentWeight) / (startWeight - target) * 100)
+		progress.PercentToTarget = &percent
+	}
+
+	return progress
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordMismatch is returned when a password does not match its hash
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// HashPassword hashes a password with bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %v", err)
+	}
+	return string(hash), nil
+}
+
+// dummyHash is a bcrypt hash at bcrypt.DefaultCost that no user has, compared
+// against when there is no usable hash so that the check takes as long as a real one
+const dummyHash = "$2a$10$RlG4V37CuUtIxrzI1RvsQuc4tm2JYjARM4dFBaLWaDIbd5af1jCFK"
+
+// CheckPassword compares a password with a bcrypt hash. A stored value that is
+// not a bcrypt hash, such as legacy plaintext not yet migrated, never matches.
+func CheckPassword(hash, password string) error {
+	if !IsPasswordHashed(hash) {
+		CheckNoPassword(password)
+		return ErrPasswordMismatch
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return fmt.Errorf("failed to verify password: %v", err)
+	}
+	return nil
+}
+
+// CheckNoPassword takes as long as CheckPassword without checking anything. Logins
+// for unknown accounts call it so that response times do not reveal which exist.
+func CheckNoPassword(password string) {
+	bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password))
+}
+
+// IsPasswordHashed reports whether a stored password is already a bcrypt hash
+// rather than legacy plaintext
+func IsPasswordHashed(stored string) bool {
+	if !strings.HasPrefix(stored, "$2a$") && !strings.HasPrefix(stored, "$2b$") && !strings.HasPrefix(stored, "$2y$") {
+		return false
+	}
+	_, err := bcrypt.Cost([]byte(stored))
+	return err == nil
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// tokenIssuer is the issuer claim written to and required on every token
+const tokenIssuer = "balancelife"
+
+// TokenType distinguishes short-lived access tokens from refresh tokens
+type TokenType string
+
+// Constants for TokenType
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// ErrInvalidToken is returned when a token is malformed, expired, wrongly signed or of the wrong type
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the JWT claims issued by the API. The subject is the user ID and
+// Version the user's token version when the token was issued.
+type Claims struct {
+	Role    models.Role `json:"role"`
+	Type    TokenType   `json:"typ"`
+	Version int         `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Current reports whether the token was issued for the user's current token
+// version, that is, not before their last password change
+func (c *Claims) Current(user models.User) bool {
+	return c.Version == user.TokenVersion
+}
+
+// TokenPair is returned to clients after login or refresh
+type TokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType" example:"Bearer"`
+	ExpiresIn    int    `json:"expiresIn" example:"900"` // Access token lifetime in seconds
+}
+
+// TokenManager issues and validates HMAC-signed JWTs
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager creates a token manager. The secret must not be empty.
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) (*TokenManager, error) {
+	if secret == "" {
+		return nil, errors.New("JWT secret is not configured")
+	}
+	if accessTTL <= 0 || refreshTTL <= 0 {
+		return nil, errors.New("token lifetimes must be positive")
+	}
+
+	return &TokenManager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}, nil
+}
+
+// IssuePair creates a new access and refresh token for the user
+func (m *TokenManager) IssuePair(user models.User) (TokenPair, error) {
+	now := time.Now()
+
+	accessToken, err := m.sign(user, TokenTypeAccess, now, m.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refreshToken, err := m.sign(user, TokenTypeRefresh, now, m.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(m.accessTTL.Seconds()),
+	}, nil
+}
+
+// Parse validates a token's signature, expiry, issuer and type and returns its claims
+func (m *TokenManager) Parse(tokenString string, expected TokenType) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.Type != expected {
+		return nil, fmt.Errorf("%w: expected %s token", ErrInvalidToken, expected)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// sign creates a signed token of the given type for the user
+func (m *TokenManager) sign(user models.User, tokenType TokenType, now time.Time, ttl time.Duration) (string, error) {
+	claims := Claims{
+		Role:    user.Role,
+		Type:    tokenType,
+		Version: user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %v", tokenType, err)
+	}
+
+	return signed, nil
+}
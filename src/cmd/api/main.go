@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
+	"github.com/zhenyili/BalanceLife/src/auth"
 	"github.com/zhenyili/BalanceLife/src/config"
 	"github.com/zhenyili/BalanceLife/src/db"
 	"github.com/zhenyili/BalanceLife/src/handlers"
+	"github.com/zhenyili/BalanceLife/src/jobs"
 
 	// Import the docs package
 	_ "github.com/zhenyili/BalanceLife/docs"
@@ -33,6 +37,7 @@ import (
 // @securityDefinitions.apiKey ApiKeyAuth
 // @in header
 // @name Authorization
+// @description Access token from /auth/login, sent as "Bearer <token>"
 
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
@@ -49,32 +54,49 @@ func main() {
 		log.Printf("Warning: Error loading config: %v, using defaults", err)
 	}
 
-	// Initialize data store with MongoDB
-	mongoStore, err := db.NewMongodbStore(cfg)
+	// Initialize JWT token manager
+	tokens, err := auth.NewTokenManager(
+		cfg.Security.JWTSecret,
+		time.Duration(cfg.Security.AccessTokenMinutes)*time.Minute,
+		time.Duration(cfg.Security.RefreshTokenHours)*time.Hour,
+	)
 	if err != nil {
-		log.Fatalf("Failed to initialize MongoDB: %v. MongoDB is required to run the application.", err)
+		log.Fatalf("Failed to initialize authentication: %v. Set JWT_SECRET to a long random string.", err)
 	}
 
-	log.Println("Successfully connected to MongoDB")
-	store := mongoStore
+	// Initialize data store
+	store, closeStore := initStore(cfg)
 
 	// Ensure we close database connections when the application exits
 	defer func() {
 		log.Println("Closing database connections...")
-		if err := mongoStore.Close(); err != nil {
+		if err := closeStore(); err != nil {
 			log.Printf("Error closing database connections: %v", err)
 		}
 	}()
 
+	// Refresh adaptive TDEE estimates in the background until the server exits
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if !cfg.Jobs.AdaptiveTDEEDisabled {
+		interval := time.Duration(cfg.Jobs.AdaptiveTDEEIntervalMinutes) * time.Minute
+		go jobs.NewAdaptiveTDEEJob(store, interval).Run(jobCtx)
+		log.Printf("Adaptive TDEE job runs every %s", interval)
+	}
+
 	// Initialize router
 	router := gin.Default()
 
+	// Tag every request with an ID and render handler errors as a uniform envelope
+	router.Use(handlers.RequestID(), handlers.ErrorHandler())
+
 	// Setup CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -95,28 +117,48 @@ func main() {
 	// @Success      200  {object}  map[string]interface{}
 	// @Router       /health [get]
 	router.GET("/health", func(c *gin.Context) {
-		usingMongoDB := mongoStore.HasMongoDB()
-
 		c.JSON(200, gin.H{
 			"status":        "ok",
 			"message":       "BalanceLife API is running",
-			"store_type":    "mongodb",
-			"using_mongodb": usingMongoDB,
+			"store_type":    cfg.Store.Type,
+			"using_mongodb": cfg.Store.Type == config.StoreTypeMongoDB,
 		})
 	})
 
 	// API routes
 	api := router.Group("/api")
+	api.Use(handlers.RequestTimeout(time.Duration(cfg.Server.RequestTimeout) * time.Second))
+
+	// Public routes: login, token refresh and registration
+	authHandler := handlers.NewAuthHandler(store, tokens)
+	authHandler.RegisterPublicRoutes(api)
 
-	// Initialize handlers and register routes
 	userHandler := handlers.NewUserHandler(store)
-	userHandler.RegisterRoutes(api)
+	userHandler.RegisterPublicRoutes(api)
+
+	// Everything else requires a valid access token
+	protected := api.Group("", handlers.RequireAuth(tokens, store))
+
+	authHandler.RegisterRoutes(protected)
+	userHandler.RegisterRoutes(protected)
 
 	mealHandler := handlers.NewMealHandler(store)
-	mealHandler.RegisterRoutes(api)
+	mealHandler.RegisterRoutes(protected)
 
 	workoutHandler := handlers.NewWorkoutHandler(store)
-	workoutHandler.RegisterRoutes(api)
+	workoutHandler.RegisterRoutes(protected)
+
+	foodHandler := handlers.NewFoodHandler(store)
+	foodHandler.RegisterRoutes(protected)
+
+	summaryHandler := handlers.NewSummaryHandler(store)
+	summaryHandler.RegisterRoutes(protected)
+
+	weightHandler := handlers.NewWeightHandler(store)
+	weightHandler.RegisterRoutes(protected)
+
+	metricsHandler := handlers.NewMetricsHandler(store)
+	metricsHandler.RegisterRoutes(protected)
 
 	// Get port from config or use default
 	port := cfg.Server.Port
@@ -131,3 +173,36 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// initStore creates the data store selected by the configuration and
+// returns it together with a function that releases its resources
+func initStore(cfg *config.AppConfig) (db.Store, func() error) {
+	switch cfg.Store.Type {
+	case config.StoreTypeMemory:
+		log.Println("Using in-memory store; data will not be persisted")
+		memoryStore := db.NewMemoryStore()
+		return memoryStore, memoryStore.Close
+
+	case config.StoreTypeMongoDB:
+		mongoStore, err := db.NewMongoStore(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize MongoDB: %v. Set STORE_TYPE=memory to run without MongoDB.", err)
+		}
+		log.Println("Successfully connected to MongoDB")
+
+		// Redis is optional; without it the cached store forwards straight to MongoDB
+		redisClient, err := db.NewRedisClient(cfg)
+		if err != nil {
+			log.Printf("Warning: Redis cache disabled: %v", err)
+		} else {
+			log.Println("Successfully connected to Redis")
+		}
+
+		cachedStore := db.NewCachedStore(mongoStore, redisClient)
+		return cachedStore, cachedStore.Close
+
+	default:
+		log.Fatalf("Unknown store type %q, expected %q or %q", cfg.Store.Type, config.StoreTypeMongoDB, config.StoreTypeMemory)
+		return nil, nil
+	}
+}
@@ -0,0 +1,147 @@
+// Command importfoods imports foods from a local Open Food Facts or USDA FoodData
+// Central dump into the food database of the store configured for the API.
+//
+// Usage:
+//
+//	go run ./src/cmd/importfoods [-format off-jsonl|off-csv|fdc] [-batch 1000] [-limit n] [-dry-run] PATH
+//
+// PATH is an Open Food Facts JSONL or CSV export, optionally gzipped, or an FDC CSV
+// download as a directory or zip file. The format is inferred from PATH unless set.
+// The dump is streamed, so files of any size can be imported, and nothing is
+// downloaded. Foods are keyed by barcode, or by brand and name, so running the
+// command again updates the foods it imported instead of duplicating them.
+// With -dry-run the dump is read and counted without writing anything.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/zhenyili/BalanceLife/src/config"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/foodimport"
+)
+
+func main() {
+	format := flag.String("format", "", "dump format: off-jsonl, off-csv or fdc (inferred from the path when empty)")
+	batchSize := flag.Int("batch", foodimport.DefaultBatchSize, "foods written per bulk upsert")
+	limit := flag.Int64("limit", 0, "stop after this many records (0 reads the whole dump)")
+	dryRun := flag.Bool("dry-run", false, "read the dump and report counts without writing to the store")
+	interval := flag.Duration("progress", 10*time.Second, "how often to report progress")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] PATH\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	opts := foodimport.Options{
+		Format:    foodimport.Format(*format),
+		BatchSize: *batchSize,
+		Limit:     *limit,
+		DryRun:    *dryRun,
+		Progress:  progressReporter(*interval),
+	}
+
+	// A dry run does not use the store, so it needs no database
+	var store db.Store
+	if !*dryRun {
+		// Load environment variables from .env file if it exists
+		if err := godotenv.Load("config/.env"); err != nil {
+			log.Printf("Warning: Could not load .env file: %v", err)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			log.Printf("Warning: Error loading config: %v, using defaults", err)
+		}
+
+		cachedStore, err := openStore(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize store: %v", err)
+		}
+		defer cachedStore.Close()
+		store = cachedStore
+	}
+
+	// An interrupted import can be run again; batches already written are updated in place
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	started := time.Now()
+	stats, err := foodimport.Import(ctx, store, path, opts)
+	if err != nil {
+		log.Fatalf("Import failed after %s: %v", time.Since(started).Round(time.Second), err)
+	}
+
+	if *dryRun {
+		log.Printf("Dry run: %d records read, %d foods would be written (%d malformed, %d skipped, %d duplicates)",
+			stats.Read, stats.Read-stats.Malformed-stats.Skipped-stats.Duplicates, stats.Malformed, stats.Skipped, stats.Duplicates)
+		return
+	}
+	log.Printf("Imported %s in %s: %d created, %d updated, %d meal packages recomputed (%d records read, %d malformed, %d skipped, %d duplicates)",
+		path, time.Since(started).Round(time.Second), stats.Created, stats.Updated, stats.Recomputed,
+		stats.Read, stats.Malformed, stats.Skipped, stats.Duplicates)
+}
+
+// openStore connects to MongoDB, through the Redis cache when one is configured
+// so that updated foods are dropped from the API's cache
+func openStore(cfg *config.AppConfig) (*db.CachedStore, error) {
+	mongoStore, err := db.NewMongoStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := db.NewRedisClient(cfg)
+	if err != nil {
+		log.Printf("Warning: Redis cache disabled: %v", err)
+	}
+	return db.NewCachedStore(mongoStore, redisClient), nil
+}
+
+// progressReporter returns a progress callback that logs at most once per interval
+func progressReporter(interval time.Duration) func(foodimport.Stats) {
+	started := time.Now()
+	last := started
+	return func(stats foodimport.Stats) {
+		now := time.Now()
+		if now.Sub(last) < interval {
+			return
+		}
+		last = now
+
+		rate := float64(stats.Read) / now.Sub(started).Seconds()
+		done := ""
+		if stats.BytesTotal > 0 {
+			done = fmt.Sprintf("%.1f%% of %s, ", 100*float64(stats.BytesRead)/float64(stats.BytesTotal), formatBytes(stats.BytesTotal))
+		}
+		log.Printf("Progress: %s%d records read (%.0f/s), %d created, %d updated, %d skipped",
+			done, stats.Read, rate, stats.Created, stats.Updated, stats.Malformed+stats.Skipped)
+	}
+}
+
+// formatBytes renders a size in bytes with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exp])
+}
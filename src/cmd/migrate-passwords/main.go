@@ -0,0 +1,89 @@
+// Command migrate-passwords re-hashes plaintext passwords left in the users
+// collection by versions of the API that stored them unhashed.
+//
+// Usage:
+//
+//	go run ./src/cmd/migrate-passwords [-dry-run]
+//
+// Users whose password is already a bcrypt hash are skipped, so the command is
+// safe to run more than once.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/zhenyili/BalanceLife/src/auth"
+	"github.com/zhenyili/BalanceLife/src/config"
+	"github.com/zhenyili/BalanceLife/src/db"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the users that would be migrated without changing them")
+	flag.Parse()
+
+	// Load environment variables from .env file if it exists
+	if err := godotenv.Load("config/.env"); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Printf("Warning: Error loading config: %v, using defaults", err)
+	}
+
+	store, err := db.NewMongoStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	users, err := store.GetUsers(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load users: %v", err)
+	}
+
+	var migrated, skipped, failed int
+	for _, user := range users {
+		if auth.IsPasswordHashed(user.Password) {
+			skipped++
+			continue
+		}
+
+		if *dryRun {
+			log.Printf("Would re-hash password for user %s (%s)", user.ID, user.Email)
+			migrated++
+			continue
+		}
+
+		hash, err := auth.HashPassword(user.Password)
+		if err != nil {
+			log.Printf("Failed to hash password for user %s: %v", user.ID, err)
+			failed++
+			continue
+		}
+
+		if _, err := store.ChangePassword(ctx, user.ID, hash); err != nil {
+			log.Printf("Failed to update user %s: %v", user.ID, err)
+			failed++
+			continue
+		}
+		migrated++
+	}
+
+	if *dryRun {
+		log.Printf("Dry run: %d users need migration, %d already hashed", migrated, skipped)
+		return
+	}
+
+	log.Printf("Migrated %d users, %d already hashed, %d failed", migrated, skipped, failed)
+	if failed > 0 {
+		log.Fatalf("Password migration incomplete")
+	}
+}
@@ -0,0 +1,125 @@
+// Command seed loads the meal and workout package catalog from fixture files
+// and upserts it into the store configured for the API.
+//
+// Usage:
+//
+//	go run ./src/cmd/seed [-dir fixtures] [-env development] [-dry-run]
+//
+// Fixtures in dir/base are loaded for every environment, followed by those in
+// dir/<env>, which replace base packages with the same ID. Packages that already
+// match their fixture are left alone, so the command is safe to run more than once.
+// With -dry-run it prints the differences without changing anything.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/zhenyili/BalanceLife/src/config"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/seed"
+)
+
+func main() {
+	dir := flag.String("dir", "fixtures", "directory containing the base and per-environment fixture directories")
+	env := flag.String("env", "development", "fixture environment to load on top of base")
+	dryRun := flag.Bool("dry-run", false, "print the changes that would be made without making them")
+	flag.Parse()
+
+	set, err := seed.Load(*dir, *env)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	// Load environment variables from .env file if it exists
+	if err := godotenv.Load("config/.env"); err != nil {
+		log.Printf("Warning: Could not load .env file: %v", err)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Printf("Warning: Error loading config: %v, using defaults", err)
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize store: %v", err)
+	}
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	changes, err := seed.Plan(ctx, store, set)
+	if err != nil {
+		log.Fatalf("Failed to compare fixtures with the store: %v", err)
+	}
+
+	counts := printChanges(os.Stdout, changes)
+	summary := fmt.Sprintf("%d to create, %d to update, %d unchanged",
+		counts[seed.ActionCreate], counts[seed.ActionUpdate], counts[seed.ActionUnchanged])
+
+	if *dryRun {
+		log.Printf("Dry run (%s): %s", *env, summary)
+		return
+	}
+
+	if err := seed.Apply(ctx, store, changes); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+	log.Printf("Seeded %s fixtures: %s", *env, summary)
+}
+
+// openStore connects to MongoDB, through the Redis cache when one is configured
+// so that package writes invalidate the API's cached package lists
+func openStore(cfg *config.AppConfig) (*db.CachedStore, error) {
+	mongoStore, err := db.NewMongoStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := db.NewRedisClient(cfg)
+	if err != nil {
+		log.Printf("Warning: Redis cache disabled: %v", err)
+	}
+	return db.NewCachedStore(mongoStore, redisClient), nil
+}
+
+// printChanges writes a diff of the changes, one package per line with the changed
+// fields of updates below it, and returns the number of changes of each action
+func printChanges(w io.Writer, changes []seed.Change) map[seed.Action]int {
+	counts := make(map[seed.Action]int)
+	for _, change := range changes {
+		counts[change.Action]++
+
+		switch change.Action {
+		case seed.ActionCreate:
+			fmt.Fprintf(w, "+ %s package %s %q\n", change.Kind, change.ID, change.Name)
+		case seed.ActionUpdate:
+			fmt.Fprintf(w, "~ %s package %s %q\n", change.Kind, change.ID, change.Name)
+			for _, field := range change.Fields {
+				fmt.Fprintf(w, "    %s: %s -> %s\n", field.Field, formatValue(field.Old), formatValue(field.New))
+			}
+		}
+	}
+	return counts
+}
+
+// formatValue renders a field value of a diff as JSON
+func formatValue(value any) string {
+	if value == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
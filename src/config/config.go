@@ -14,13 +14,16 @@ type AppConfig struct {
 	MongoDB  MongoDBConfig  `json:"mongodb"`
 	Redis    RedisConfig    `json:"redis"`
 	Security SecurityConfig `json:"security"`
+	Store    StoreConfig    `json:"store"`
+	Jobs     JobsConfig     `json:"jobs"`
 }
 
 // ServerConfig represents the HTTP server configuration
 type ServerConfig struct {
-	Port         string `json:"port"`
-	ReadTimeout  int    `json:"readTimeout"`
-	WriteTimeout int    `json:"writeTimeout"`
+	Port           string `json:"port"`
+	ReadTimeout    int    `json:"readTimeout"`
+	WriteTimeout   int    `json:"writeTimeout"`
+	RequestTimeout int    `json:"requestTimeout"` // Deadline in seconds for handling a single API request
 }
 
 // MongoDBConfig represents the MongoDB connection configuration
@@ -38,9 +41,28 @@ type RedisConfig struct {
 	DB       int    `json:"db,omitempty"`
 }
 
+// Supported store types
+const (
+	StoreTypeMongoDB = "mongodb"
+	StoreTypeMemory  = "memory"
+)
+
+// StoreConfig selects the data store backing the API
+type StoreConfig struct {
+	Type string `json:"type"` // mongodb (default) or memory
+}
+
 // SecurityConfig represents security-related configurations
 type SecurityConfig struct {
-	JWTSecret string `json:"jwtSecret"`
+	JWTSecret          string `json:"jwtSecret"`
+	AccessTokenMinutes int    `json:"accessTokenMinutes"` // Lifetime of access tokens
+	RefreshTokenHours  int    `json:"refreshTokenHours"`  // Lifetime of refresh tokens
+}
+
+// JobsConfig controls the background jobs run by the API server
+type JobsConfig struct {
+	AdaptiveTDEEIntervalMinutes int  `json:"adaptiveTdeeIntervalMinutes"` // How often adaptive TDEE estimates are refreshed
+	AdaptiveTDEEDisabled        bool `json:"adaptiveTdeeDisabled"`        // Turns the adaptive TDEE job off
 }
 
 var (
@@ -99,6 +121,7 @@ func setDefaults(cfg *AppConfig) {
 	cfg.Server.Port = "8080"
 	cfg.Server.ReadTimeout = 10
 	cfg.Server.WriteTimeout = 10
+	cfg.Server.RequestTimeout = 5
 
 	// MongoDB defaults - empty, require explicit configuration
 	cfg.MongoDB.URI = ""
@@ -113,6 +136,15 @@ func setDefaults(cfg *AppConfig) {
 
 	// Security defaults - empty, require explicit configuration
 	cfg.Security.JWTSecret = ""
+	cfg.Security.AccessTokenMinutes = 15
+	cfg.Security.RefreshTokenHours = 24 * 7
+
+	// Store defaults
+	cfg.Store.Type = StoreTypeMongoDB
+
+	// Job defaults
+	cfg.Jobs.AdaptiveTDEEIntervalMinutes = 360
+	cfg.Jobs.AdaptiveTDEEDisabled = false
 }
 
 // overrideWithEnv overrides config with environment variables
@@ -121,6 +153,12 @@ func overrideWithEnv(cfg *AppConfig) {
 	if val := os.Getenv("SERVER_PORT"); val != "" {
 		cfg.Server.Port = val
 	}
+	if val := os.Getenv("SERVER_REQUEST_TIMEOUT"); val != "" {
+		var timeout int
+		if _, err := fmt.Sscanf(val, "%d", &timeout); err == nil {
+			cfg.Server.RequestTimeout = timeout
+		}
+	}
 
 	// MongoDB settings
 	if val := os.Getenv("MONGODB_URI"); val != "" {
@@ -154,4 +192,44 @@ func overrideWithEnv(cfg *AppConfig) {
 	if val := os.Getenv("JWT_SECRET"); val != "" {
 		cfg.Security.JWTSecret = val
 	}
+	if val := os.Getenv("JWT_ACCESS_TOKEN_MINUTES"); val != "" {
+		var minutes int
+		if _, err := fmt.Sscanf(val, "%d", &minutes); err == nil {
+			cfg.Security.AccessTokenMinutes = minutes
+		}
+	}
+	if val := os.Getenv("JWT_REFRESH_TOKEN_HOURS"); val != "" {
+		var hours int
+		if _, err := fmt.Sscanf(val, "%d", &hours); err == nil {
+			cfg.Security.RefreshTokenHours = hours
+		}
+	}
+	if cfg.Security.AccessTokenMinutes <= 0 {
+		cfg.Security.AccessTokenMinutes = 15
+	}
+	if cfg.Security.RefreshTokenHours <= 0 {
+		cfg.Security.RefreshTokenHours = 24 * 7
+	}
+
+	// Store settings
+	if val := os.Getenv("STORE_TYPE"); val != "" {
+		cfg.Store.Type = val
+	}
+	if cfg.Store.Type == "" {
+		cfg.Store.Type = StoreTypeMongoDB
+	}
+
+	// Job settings
+	if val := os.Getenv("ADAPTIVE_TDEE_INTERVAL_MINUTES"); val != "" {
+		var minutes int
+		if _, err := fmt.Sscanf(val, "%d", &minutes); err == nil {
+			cfg.Jobs.AdaptiveTDEEIntervalMinutes = minutes
+		}
+	}
+	if val := os.Getenv("ADAPTIVE_TDEE_DISABLED"); val != "" {
+		cfg.Jobs.AdaptiveTDEEDisabled = val == "true" || val == "1"
+	}
+	if cfg.Jobs.AdaptiveTDEEIntervalMinutes <= 0 {
+		cfg.Jobs.AdaptiveTDEEIntervalMinutes = 360
+	}
 }
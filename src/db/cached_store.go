@@ -1,121 +1,734 @@
 package db
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log"
 	"time"
 
-	"github.com/zhenyili/BalanceLife/src/config"
+	"github.com/go-redis/redis/v8"
 	"github.com/zhenyili/BalanceLife/src/models"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
-// MongodbStore implements the Store interface using MongoDB for persistence
-// It provides direct access to the MongoDB database
-type MongodbStore struct {
-	db *MongoStore
+// Cache TTLs per kind of data
+const (
+	userCacheTTL    = 15 * time.Minute
+	packageCacheTTL = 1 * time.Hour
+	entryCacheTTL   = 5 * time.Minute
+)
+
+// CachedStore implements the Store interface as a cache-aside decorator.
+// Reads are served from Redis when possible and fall back to the wrapped
+// store; writes go to the wrapped store and invalidate affected cache keys.
+// Any Redis failure is logged and treated as a cache miss.
+type CachedStore struct {
+	store Store
+	cache *redis.Client
+}
+
+// NewCachedStore wraps store with a Redis cache.
+// A nil cache client disables caching and forwards every call to store.
+func NewCachedStore(store Store, cache *redis.Client) *CachedStore {
+	return &CachedStore{
+		store: store,
+		cache: cache,
+	}
+}
+
+// Close closes the Redis client and the wrapped store
+func (s *CachedStore) Close() error {
+	var errs []error
+	if s.cache != nil {
+		if err := s.cache.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if closer, ok := s.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// NewMongodbStore creates a new MongoDB-based store
-// It requires a valid MongoDB connection
-func NewMongodbStore(cfg *config.AppConfig) (*MongodbStore, error) {
-	// Initialize MongoDB connection
-	db, err := NewMongoStore(cfg)
+// HasCache returns true if a Redis client is configured
+func (s *CachedStore) HasCache() bool {
+	return s.cache != nil
+}
+
+// cacheEnvelope wraps cached values so that slices can be BSON-encoded as documents.
+// BSON is used rather than JSON so that fields hidden from the API (like passwords) survive.
+type cacheEnvelope[T any] struct {
+	Value T `bson:"value"`
+}
+
+// getCached loads key from Redis into a value of type T
+func getCached[T any](ctx context.Context, s *CachedStore, key string) (T, bool) {
+	var zero T
+	if s.cache == nil {
+		return zero, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisOpTimeout)
+	defer cancel()
+
+	data, err := s.cache.Get(ctx, key).Bytes()
 	if err != nil {
-		return nil, err
+		if err != redis.Nil {
+			log.Printf("Cache read failed for %s: %v", key, err)
+		}
+		return zero, false
 	}
 
-	log.Println("Connected to MongoDB successfully")
-	return &MongodbStore{
-		db: db,
-	}, nil
+	var envelope cacheEnvelope[T]
+	if err := bson.Unmarshal(data, &envelope); err != nil {
+		log.Printf("Cache decode failed for %s: %v", key, err)
+		return zero, false
+	}
+
+	return envelope.Value, true
 }
 
-// Close closes all connections
-func (s *MongodbStore) Close() error {
-	if s.db != nil {
-		return s.db.Close()
+// setCached stores value in Redis under key with the given TTL
+func setCached[T any](ctx context.Context, s *CachedStore, key string, value T, ttl time.Duration) {
+	if s.cache == nil {
+		return
+	}
+
+	data, err := bson.Marshal(cacheEnvelope[T]{Value: value})
+	if err != nil {
+		log.Printf("Cache encode failed for %s: %v", key, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisOpTimeout)
+	defer cancel()
+
+	if err := s.cache.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.Printf("Cache write failed for %s: %v", key, err)
 	}
-	return nil
 }
 
-// HasMongoDB returns true if MongoDB is available
-func (s *MongodbStore) HasMongoDB() bool {
-	return s.db != nil
+// invalidate deletes the given keys from Redis
+func (s *CachedStore) invalidate(ctx context.Context, keys ...string) {
+	if s.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisOpTimeout)
+	defer cancel()
+
+	if err := s.cache.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Cache invalidation failed for %v: %v", keys, err)
+	}
+}
+
+// entriesVersion returns the current value of a per-user entry or package list version counter.
+// The second return value is false when Redis is unavailable.
+func (s *CachedStore) entriesVersion(ctx context.Context, key string) (int64, bool) {
+	if s.cache == nil {
+		return 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisOpTimeout)
+	defer cancel()
+
+	version, err := s.cache.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, true
+	}
+	if err != nil {
+		log.Printf("Cache read failed for %s: %v", key, err)
+		return 0, false
+	}
+
+	return version, true
+}
+
+// bumpEntriesVersion increments a per-user entry or package list version counter,
+// which orphans every cached date range for that user or every cached list page
+func (s *CachedStore) bumpEntriesVersion(ctx context.Context, keys ...string) {
+	if s.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisOpTimeout)
+	defer cancel()
+
+	pipe := s.cache.TxPipeline()
+	for _, key := range keys {
+		pipe.Incr(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Cache invalidation failed for %v: %v", keys, err)
+	}
 }
 
 // User-related methods
 
 // GetUsers returns all users
-func (s *MongodbStore) GetUsers() []models.User {
-	return s.db.GetUsers()
+func (s *CachedStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	return s.store.GetUsers(ctx)
 }
 
 // GetUser returns a user by ID
-func (s *MongodbStore) GetUser(id string) (models.User, error) {
-	// Get from database
-	user, err := s.db.GetUser(id)
+func (s *CachedStore) GetUser(ctx context.Context, id string) (models.User, error) {
+	key := userCacheKey(id)
+	if user, ok := getCached[models.User](ctx, s, key); ok {
+		return user, nil
+	}
+
+	user, err := s.store.GetUser(ctx, id)
 	if err != nil {
 		return models.User{}, err
 	}
 
+	setCached(ctx, s, key, user, userCacheTTL)
 	return user, nil
 }
 
+// GetUserByEmail returns a user by email address.
+// It is only used for logins and is not cached.
+func (s *CachedStore) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	return s.store.GetUserByEmail(ctx, email)
+}
+
 // CreateUser creates a new user
-func (s *MongodbStore) CreateUser(user models.User) (models.User, error) {
-	// Create in database
-	return s.db.CreateUser(user)
+func (s *CachedStore) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	return s.store.CreateUser(ctx, user)
 }
 
-// DeleteUser deletes a user by ID
-func (s *MongodbStore) DeleteUser(id string) (models.User, error) {
-	// Delete from database
-	return s.db.DeleteUser(id)
+// UpdateUser updates a user and drops their cached profile
+func (s *CachedStore) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	updated, err := s.store.UpdateUser(ctx, user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.invalidate(ctx, userCacheKey(user.ID))
+	return updated, nil
+}
+
+// RecordLogin sets a user's last login time and drops their cached profile
+func (s *CachedStore) RecordLogin(ctx context.Context, id string, at time.Time) (models.User, error) {
+	updated, err := s.store.RecordLogin(ctx, id, at)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.invalidate(ctx, userCacheKey(id))
+	return updated, nil
+}
+
+// ChangePassword sets a user's password hash and drops their cached profile, so the
+// new token version applies from the next request
+func (s *CachedStore) ChangePassword(ctx context.Context, id, hash string) (models.User, error) {
+	updated, err := s.store.ChangePassword(ctx, id, hash)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.invalidate(ctx, userCacheKey(id))
+	return updated, nil
+}
+
+// UpdateAdaptiveTDEE stores a user's adaptive TDEE estimate and drops their cached profile
+func (s *CachedStore) UpdateAdaptiveTDEE(ctx context.Context, id string, estimate models.TDEEEstimate, targets *models.GoalInfo) (models.User, error) {
+	updated, err := s.store.UpdateAdaptiveTDEE(ctx, id, estimate, targets)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.invalidate(ctx, userCacheKey(id))
+	return updated, nil
+}
+
+// DeleteUser deletes a user by ID and drops everything cached for them
+func (s *CachedStore) DeleteUser(ctx context.Context, id string) (models.User, error) {
+	user, err := s.store.DeleteUser(ctx, id)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.invalidate(ctx, userCacheKey(id))
+	s.bumpEntriesVersion(ctx, mealEntriesVersionKey(id), workoutEntriesVersionKey(id), weightEntriesVersionKey(id))
+	return user, nil
 }
 
 // MealPackage-related methods
 
-// GetMealPackages returns all meal packages, optionally filtered by goal type
-func (s *MongodbStore) GetMealPackages(goalType models.GoalType) []models.MealPackage {
-	return s.db.GetMealPackages(goalType)
+// GetMealPackages returns one page of the meal packages matching the query
+func (s *CachedStore) GetMealPackages(ctx context.Context, query MealPackageQuery) (models.MealPackagePage, error) {
+	version, ok := s.entriesVersion(ctx, mealPackagesVersionKey())
+	if !ok {
+		return s.store.GetMealPackages(ctx, query)
+	}
+
+	key := mealPackagesCacheKey(version, query.cacheKey())
+	if page, ok := getCached[models.MealPackagePage](ctx, s, key); ok {
+		return page, nil
+	}
+
+	page, err := s.store.GetMealPackages(ctx, query)
+	if err != nil {
+		return models.MealPackagePage{}, err
+	}
+
+	setCached(ctx, s, key, page, packageCacheTTL)
+	return page, nil
 }
 
 // GetMealPackage returns a meal package by ID
-func (s *MongodbStore) GetMealPackage(id string) (models.MealPackage, error) {
-	return s.db.GetMealPackage(id)
+func (s *CachedStore) GetMealPackage(ctx context.Context, id string) (models.MealPackage, error) {
+	key := mealPackageCacheKey(id)
+	if pkg, ok := getCached[models.MealPackage](ctx, s, key); ok {
+		return pkg, nil
+	}
+
+	pkg, err := s.store.GetMealPackage(ctx, id)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+
+	setCached(ctx, s, key, pkg, packageCacheTTL)
+	return pkg, nil
+}
+
+// GetMealPackageVersion returns one version of a meal package
+func (s *CachedStore) GetMealPackageVersion(ctx context.Context, id string, version int) (models.MealPackage, error) {
+	key := mealPackageVersionCacheKey(id, version)
+	if pkg, ok := getCached[models.MealPackage](ctx, s, key); ok {
+		return pkg, nil
+	}
+
+	pkg, err := s.store.GetMealPackageVersion(ctx, id, version)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+
+	setCached(ctx, s, key, pkg, packageCacheTTL)
+	return pkg, nil
+}
+
+// CreateMealPackage creates a meal package and invalidates the cached package lists
+func (s *CachedStore) CreateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	created, err := s.store.CreateMealPackage(ctx, pkg)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, mealPackagesVersionKey())
+	return created, nil
+}
+
+// UpdateMealPackage updates a meal package and drops it and the cached package lists
+func (s *CachedStore) UpdateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	updated, err := s.store.UpdateMealPackage(ctx, pkg)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+
+	s.invalidate(ctx, mealPackageCacheKey(updated.ID))
+	s.bumpEntriesVersion(ctx, mealPackagesVersionKey())
+	return updated, nil
+}
+
+// ArchiveMealPackage archives or restores a meal package and drops it and the cached package lists
+func (s *CachedStore) ArchiveMealPackage(ctx context.Context, id string, archived bool) (models.MealPackage, error) {
+	pkg, err := s.store.ArchiveMealPackage(ctx, id, archived)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+
+	s.invalidate(ctx, mealPackageCacheKey(id))
+	s.bumpEntriesVersion(ctx, mealPackagesVersionKey())
+	return pkg, nil
+}
+
+// GetMealPackagesByFoods passes through; it is only used when foods change
+func (s *CachedStore) GetMealPackagesByFoods(ctx context.Context, foodIDs []string) ([]models.MealPackage, error) {
+	return s.store.GetMealPackagesByFoods(ctx, foodIDs)
 }
 
 // WorkoutPackage-related methods
 
-// GetWorkoutPackages returns all workout packages, optionally filtered by goal type
-func (s *MongodbStore) GetWorkoutPackages(goalType models.GoalType) []models.WorkoutPackage {
-	return s.db.GetWorkoutPackages(goalType)
+// GetWorkoutPackages returns one page of the workout packages matching the query
+func (s *CachedStore) GetWorkoutPackages(ctx context.Context, query WorkoutPackageQuery) (models.WorkoutPackagePage, error) {
+	version, ok := s.entriesVersion(ctx, workoutPackagesVersionKey())
+	if !ok {
+		return s.store.GetWorkoutPackages(ctx, query)
+	}
+
+	key := workoutPackagesCacheKey(version, query.cacheKey())
+	if page, ok := getCached[models.WorkoutPackagePage](ctx, s, key); ok {
+		return page, nil
+	}
+
+	page, err := s.store.GetWorkoutPackages(ctx, query)
+	if err != nil {
+		return models.WorkoutPackagePage{}, err
+	}
+
+	setCached(ctx, s, key, page, packageCacheTTL)
+	return page, nil
 }
 
 // GetWorkoutPackage returns a workout package by ID
-func (s *MongodbStore) GetWorkoutPackage(id string) (models.WorkoutPackage, error) {
-	return s.db.GetWorkoutPackage(id)
+func (s *CachedStore) GetWorkoutPackage(ctx context.Context, id string) (models.WorkoutPackage, error) {
+	key := workoutPackageCacheKey(id)
+	if pkg, ok := getCached[models.WorkoutPackage](ctx, s, key); ok {
+		return pkg, nil
+	}
+
+	pkg, err := s.store.GetWorkoutPackage(ctx, id)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	setCached(ctx, s, key, pkg, packageCacheTTL)
+	return pkg, nil
+}
+
+// GetWorkoutPackageVersion returns one version of a workout package
+func (s *CachedStore) GetWorkoutPackageVersion(ctx context.Context, id string, version int) (models.WorkoutPackage, error) {
+	key := workoutPackageVersionCacheKey(id, version)
+	if pkg, ok := getCached[models.WorkoutPackage](ctx, s, key); ok {
+		return pkg, nil
+	}
+
+	pkg, err := s.store.GetWorkoutPackageVersion(ctx, id, version)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	setCached(ctx, s, key, pkg, packageCacheTTL)
+	return pkg, nil
+}
+
+// CreateWorkoutPackage creates a workout package and invalidates the cached package lists
+func (s *CachedStore) CreateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	created, err := s.store.CreateWorkoutPackage(ctx, pkg)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, workoutPackagesVersionKey())
+	return created, nil
+}
+
+// UpdateWorkoutPackage updates a workout package and drops it and the cached package lists
+func (s *CachedStore) UpdateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	updated, err := s.store.UpdateWorkoutPackage(ctx, pkg)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	s.invalidate(ctx, workoutPackageCacheKey(updated.ID))
+	s.bumpEntriesVersion(ctx, workoutPackagesVersionKey())
+	return updated, nil
+}
+
+// ArchiveWorkoutPackage archives or restores a workout package and drops it and the cached package lists
+func (s *CachedStore) ArchiveWorkoutPackage(ctx context.Context, id string, archived bool) (models.WorkoutPackage, error) {
+	pkg, err := s.store.ArchiveWorkoutPackage(ctx, id, archived)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	s.invalidate(ctx, workoutPackageCacheKey(id))
+	s.bumpEntriesVersion(ctx, workoutPackagesVersionKey())
+	return pkg, nil
+}
+
+// Food-related methods
+
+// GetFoods passes through; food searches vary too much to be worth caching
+func (s *CachedStore) GetFoods(ctx context.Context, query FoodQuery) (models.FoodPage, error) {
+	return s.store.GetFoods(ctx, query)
+}
+
+// GetFood returns a food by ID
+func (s *CachedStore) GetFood(ctx context.Context, id string) (models.Food, error) {
+	key := foodCacheKey(id)
+	if food, ok := getCached[models.Food](ctx, s, key); ok {
+		return food, nil
+	}
+
+	food, err := s.store.GetFood(ctx, id)
+	if err != nil {
+		return models.Food{}, err
+	}
+
+	setCached(ctx, s, key, food, packageCacheTTL)
+	return food, nil
+}
+
+// GetFoodByBarcode returns the food with a barcode
+func (s *CachedStore) GetFoodByBarcode(ctx context.Context, barcode string) (models.Food, error) {
+	key := foodBarcodeCacheKey(barcode)
+	if food, ok := getCached[models.Food](ctx, s, key); ok {
+		return food, nil
+	}
+
+	food, err := s.store.GetFoodByBarcode(ctx, barcode)
+	if err != nil {
+		return models.Food{}, err
+	}
+
+	setCached(ctx, s, key, food, packageCacheTTL)
+	return food, nil
+}
+
+// CreateFood creates a food
+func (s *CachedStore) CreateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	return s.store.CreateFood(ctx, food)
+}
+
+// UpdateFood updates a food and drops it from the cache
+func (s *CachedStore) UpdateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	updated, err := s.store.UpdateFood(ctx, food)
+	if err != nil {
+		return models.Food{}, err
+	}
+
+	s.invalidate(ctx, foodCacheKeys(updated)...)
+	return updated, nil
+}
+
+// UpsertFoods imports foods and drops the updated ones from the cache
+func (s *CachedStore) UpsertFoods(ctx context.Context, foods []models.Food) (FoodUpsertResult, error) {
+	result, err := s.store.UpsertFoods(ctx, foods)
+	if err != nil {
+		return FoodUpsertResult{}, err
+	}
+
+	if result.Updated > 0 {
+		keys := make([]string, 0, 2*len(foods))
+		for _, food := range foods {
+			keys = append(keys, foodCacheKeys(food)...)
+		}
+		s.invalidate(ctx, keys...)
+	}
+	return result, nil
+}
+
+// foodCacheKeys returns the keys a food is cached under
+func foodCacheKeys(food models.Food) []string {
+	keys := []string{foodCacheKey(food.ID)}
+	if food.Barcode != "" {
+		keys = append(keys, foodBarcodeCacheKey(food.Barcode))
+	}
+	return keys
 }
 
 // MealEntry-related methods
 
-// CreateMealEntry adds a new meal entry
-func (s *MongodbStore) CreateMealEntry(entry models.MealEntry) (models.MealEntry, error) {
-	return s.db.CreateMealEntry(entry)
+// CreateMealEntry adds a new meal entry and invalidates the user's cached meal ranges
+func (s *CachedStore) CreateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
+	created, err := s.store.CreateMealEntry(ctx, entry)
+	if err != nil {
+		return models.MealEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, mealEntriesVersionKey(created.UserID))
+	return created, nil
 }
 
 // GetMealEntriesByUserAndDateRange returns meal entries for a user within a date range
-func (s *MongodbStore) GetMealEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.MealEntry {
-	return s.db.GetMealEntriesByUserAndDateRange(userID, startDate, endDate)
+func (s *CachedStore) GetMealEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.MealEntry, error) {
+	version, ok := s.entriesVersion(ctx, mealEntriesVersionKey(userID))
+	if !ok {
+		return s.store.GetMealEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	}
+
+	key := mealEntriesCacheKey(userID, version, startDate, endDate)
+	if entries, ok := getCached[[]models.MealEntry](ctx, s, key); ok {
+		return entries, nil
+	}
+
+	entries, err := s.store.GetMealEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(ctx, s, key, entries, entryCacheTTL)
+	return entries, nil
+}
+
+// GetMealEntry retrieves a meal entry by ID. Single entries are not cached.
+func (s *CachedStore) GetMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	return s.store.GetMealEntry(ctx, id)
+}
+
+// UpdateMealEntry replaces a meal entry and invalidates the user's cached meal ranges
+func (s *CachedStore) UpdateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
+	updated, err := s.store.UpdateMealEntry(ctx, entry)
+	if err != nil {
+		return models.MealEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, mealEntriesVersionKey(updated.UserID))
+	return updated, nil
+}
+
+// DeleteMealEntry removes a meal entry and invalidates the user's cached meal ranges
+func (s *CachedStore) DeleteMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	deleted, err := s.store.DeleteMealEntry(ctx, id)
+	if err != nil {
+		return models.MealEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, mealEntriesVersionKey(deleted.UserID))
+	return deleted, nil
 }
 
 // WorkoutEntry-related methods
 
-// CreateWorkoutEntry adds a new workout entry
-func (s *MongodbStore) CreateWorkoutEntry(entry models.WorkoutEntry) (models.WorkoutEntry, error) {
-	return s.db.CreateWorkoutEntry(entry)
+// CreateWorkoutEntry adds a new workout entry and invalidates the user's cached workout ranges
+func (s *CachedStore) CreateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+	created, err := s.store.CreateWorkoutEntry(ctx, entry)
+	if err != nil {
+		return models.WorkoutEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, workoutEntriesVersionKey(created.UserID))
+	return created, nil
 }
 
 // GetWorkoutEntriesByUserAndDateRange returns workout entries for a user within a date range
-func (s *MongodbStore) GetWorkoutEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.WorkoutEntry {
-	return s.db.GetWorkoutEntriesByUserAndDateRange(userID, startDate, endDate)
+func (s *CachedStore) GetWorkoutEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WorkoutEntry, error) {
+	version, ok := s.entriesVersion(ctx, workoutEntriesVersionKey(userID))
+	if !ok {
+		return s.store.GetWorkoutEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	}
+
+	key := workoutEntriesCacheKey(userID, version, startDate, endDate)
+	if entries, ok := getCached[[]models.WorkoutEntry](ctx, s, key); ok {
+		return entries, nil
+	}
+
+	entries, err := s.store.GetWorkoutEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(ctx, s, key, entries, entryCacheTTL)
+	return entries, nil
+}
+
+// GetWorkoutEntry retrieves a workout entry by ID. Single entries are not cached.
+func (s *CachedStore) GetWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	return s.store.GetWorkoutEntry(ctx, id)
+}
+
+// UpdateWorkoutEntry replaces a workout entry and invalidates the user's cached workout ranges
+func (s *CachedStore) UpdateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+	updated, err := s.store.UpdateWorkoutEntry(ctx, entry)
+	if err != nil {
+		return models.WorkoutEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, workoutEntriesVersionKey(updated.UserID))
+	return updated, nil
+}
+
+// DeleteWorkoutEntry removes a workout entry and invalidates the user's cached workout ranges
+func (s *CachedStore) DeleteWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	deleted, err := s.store.DeleteWorkoutEntry(ctx, id)
+	if err != nil {
+		return models.WorkoutEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, workoutEntriesVersionKey(deleted.UserID))
+	return deleted, nil
+}
+
+// CreateWeightEntry adds a new weight entry and invalidates the user's cached weight ranges
+func (s *CachedStore) CreateWeightEntry(ctx context.Context, entry models.WeightEntry) (models.WeightEntry, error) {
+	created, err := s.store.CreateWeightEntry(ctx, entry)
+	if err != nil {
+		return models.WeightEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, weightEntriesVersionKey(created.UserID))
+	return created, nil
+}
+
+// GetWeightEntriesByUserAndDateRange returns weight entries for a user within a date range
+func (s *CachedStore) GetWeightEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WeightEntry, error) {
+	version, ok := s.entriesVersion(ctx, weightEntriesVersionKey(userID))
+	if !ok {
+		return s.store.GetWeightEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	}
+
+	key := weightEntriesCacheKey(userID, version, startDate, endDate)
+	if entries, ok := getCached[[]models.WeightEntry](ctx, s, key); ok {
+		return entries, nil
+	}
+
+	entries, err := s.store.GetWeightEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(ctx, s, key, entries, entryCacheTTL)
+	return entries, nil
+}
+
+// GetLatestWeightEntry returns the user's most recently dated weight entry. It is not cached.
+func (s *CachedStore) GetLatestWeightEntry(ctx context.Context, userID string) (models.WeightEntry, error) {
+	return s.store.GetLatestWeightEntry(ctx, userID)
+}
+
+// GetWeightEntry retrieves a weight entry by ID. Single entries are not cached.
+func (s *CachedStore) GetWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	return s.store.GetWeightEntry(ctx, id)
+}
+
+// DeleteWeightEntry removes a weight entry and invalidates the user's cached weight ranges
+func (s *CachedStore) DeleteWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	deleted, err := s.store.DeleteWeightEntry(ctx, id)
+	if err != nil {
+		return models.WeightEntry{}, err
+	}
+
+	s.bumpEntriesVersion(ctx, weightEntriesVersionKey(deleted.UserID))
+	return deleted, nil
+}
+
+// CreateUserInfo stores a health metrics snapshot. Snapshots are not cached.
+func (s *CachedStore) CreateUserInfo(ctx context.Context, info models.UserInfo) (models.UserInfo, error) {
+	return s.store.CreateUserInfo(ctx, info)
+}
+
+// GetUserInfoHistory returns a user's health metrics snapshots taken within a time range
+func (s *CachedStore) GetUserInfoHistory(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.UserInfo, error) {
+	return s.store.GetUserInfoHistory(ctx, userID, startDate, endDate)
+}
+
+// GetDailyTotals returns per-day entry totals for a user within a date range
+func (s *CachedStore) GetDailyTotals(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyTotals, error) {
+	mealVersion, ok := s.entriesVersion(ctx, mealEntriesVersionKey(userID))
+	if !ok {
+		return s.store.GetDailyTotals(ctx, userID, startDate, endDate)
+	}
+	workoutVersion, ok := s.entriesVersion(ctx, workoutEntriesVersionKey(userID))
+	if !ok {
+		return s.store.GetDailyTotals(ctx, userID, startDate, endDate)
+	}
+
+	key := dailyTotalsCacheKey(userID, mealVersion, workoutVersion, startDate, endDate)
+	if totals, ok := getCached[[]models.DailyTotals](ctx, s, key); ok {
+		return totals, nil
+	}
+
+	totals, err := s.store.GetDailyTotals(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(ctx, s, key, totals, entryCacheTTL)
+	return totals, nil
 }
@@ -0,0 +1,328 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// countingStore records how many reads reach the wrapped store, so tests can
+// tell a cache hit from a miss
+type countingStore struct {
+	*MemoryStore
+	calls map[string]int
+}
+
+func (s *countingStore) GetUser(ctx context.Context, id string) (models.User, error) {
+	s.calls["GetUser"]++
+	return s.MemoryStore.GetUser(ctx, id)
+}
+
+func (s *countingStore) GetMealPackages(ctx context.Context, query MealPackageQuery) (models.MealPackagePage, error) {
+	s.calls["GetMealPackages"]++
+	return s.MemoryStore.GetMealPackages(ctx, query)
+}
+
+func (s *countingStore) GetFood(ctx context.Context, id string) (models.Food, error) {
+	s.calls["GetFood"]++
+	return s.MemoryStore.GetFood(ctx, id)
+}
+
+func (s *countingStore) GetFoodByBarcode(ctx context.Context, barcode string) (models.Food, error) {
+	s.calls["GetFoodByBarcode"]++
+	return s.MemoryStore.GetFoodByBarcode(ctx, barcode)
+}
+
+func (s *countingStore) GetMealEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.MealEntry, error) {
+	s.calls["GetMealEntries"]++
+	return s.MemoryStore.GetMealEntriesByUserAndDateRange(ctx, userID, startDate, endDate)
+}
+
+// newTestCachedStore returns a cached store over a counting memory store, backed by miniredis
+func newTestCachedStore(t *testing.T) (*CachedStore, *countingStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:        mr.Addr(),
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+
+	inner := &countingStore{MemoryStore: NewMemoryStore(), calls: make(map[string]int)}
+	return NewCachedStore(inner, client), inner, mr
+}
+
+// testDay is the date the test entries are logged for
+var testDay = time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+
+// mealEntriesOfDay reads the user's meal entries of testDay through the cache
+func mealEntriesOfDay(t *testing.T, s *CachedStore, userID string) []models.MealEntry {
+	t.Helper()
+	entries, err := s.GetMealEntriesByUserAndDateRange(context.Background(), userID, testDay, testDay.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatalf("GetMealEntriesByUserAndDateRange: %v", err)
+	}
+	return entries
+}
+
+func TestCachedStoreGetUserMissThenHit(t *testing.T) {
+	ctx := context.Background()
+	s, inner, mr := newTestCachedStore(t)
+	if _, err := inner.CreateUser(ctx, models.User{ID: "u1", Name: "Ann", Email: "ann@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		user, err := s.GetUser(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUser: %v", err)
+		}
+		if user.Name != "Ann" {
+			t.Fatalf("GetUser returned %q, want Ann", user.Name)
+		}
+	}
+	if got := inner.calls["GetUser"]; got != 1 {
+		t.Errorf("store read %d times, want 1 (miss, then hit)", got)
+	}
+	if !mr.Exists(userCacheKey("u1")) {
+		t.Errorf("%s is not cached", userCacheKey("u1"))
+	}
+}
+
+func TestCachedStoreMealEntryWritesInvalidateRanges(t *testing.T) {
+	ctx := context.Background()
+	s, inner, _ := newTestCachedStore(t)
+
+	if got := len(mealEntriesOfDay(t, s, "u1")); got != 0 {
+		t.Fatalf("got %d entries, want 0", got)
+	}
+
+	created, err := s.CreateMealEntry(ctx, models.MealEntry{UserID: "u1", Name: "Oats", Calories: 300, Date: testDay})
+	if err != nil {
+		t.Fatalf("CreateMealEntry: %v", err)
+	}
+	if got := len(mealEntriesOfDay(t, s, "u1")); got != 1 {
+		t.Fatalf("after create got %d entries, want 1", got)
+	}
+
+	created.Calories = 450
+	if _, err := s.UpdateMealEntry(ctx, created); err != nil {
+		t.Fatalf("UpdateMealEntry: %v", err)
+	}
+	if got := mealEntriesOfDay(t, s, "u1"); len(got) != 1 || got[0].Calories != 450 {
+		t.Fatalf("after update got %+v, want one entry with 450 kcal", got)
+	}
+
+	if _, err := s.DeleteMealEntry(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteMealEntry: %v", err)
+	}
+	if got := len(mealEntriesOfDay(t, s, "u1")); got != 0 {
+		t.Fatalf("after delete got %d entries, want 0", got)
+	}
+
+	// Every read after a write was a miss; a repeated read is a hit
+	before := inner.calls["GetMealEntries"]
+	mealEntriesOfDay(t, s, "u1")
+	if got := inner.calls["GetMealEntries"]; got != before {
+		t.Errorf("unchanged range read the store again (%d reads, want %d)", got, before)
+	}
+	if before != 4 {
+		t.Errorf("store read %d times, want 4 (one per write plus the first)", before)
+	}
+}
+
+func TestCachedStoreDeleteUserInvalidates(t *testing.T) {
+	ctx := context.Background()
+	s, inner, mr := newTestCachedStore(t)
+	if _, err := inner.CreateUser(ctx, models.User{ID: "u1", Email: "ann@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetUser(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	mealEntriesOfDay(t, s, "u1")
+	if !mr.Exists(userCacheKey("u1")) {
+		t.Fatalf("%s is not cached", userCacheKey("u1"))
+	}
+
+	if _, err := s.DeleteUser(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if mr.Exists(userCacheKey("u1")) {
+		t.Errorf("%s is still cached after DeleteUser", userCacheKey("u1"))
+	}
+	if _, err := s.GetUser(ctx, "u1"); err == nil {
+		t.Errorf("GetUser found a deleted user")
+	}
+
+	for _, key := range []string{mealEntriesVersionKey("u1"), workoutEntriesVersionKey("u1"), weightEntriesVersionKey("u1")} {
+		if version, err := mr.Get(key); err != nil || version != "1" {
+			t.Errorf("%s = %q, %v; want 1", key, version, err)
+		}
+	}
+	mealEntriesOfDay(t, s, "u1")
+	if got := inner.calls["GetMealEntries"]; got != 2 {
+		t.Errorf("store read %d times, want 2: the cached range must be orphaned", got)
+	}
+}
+
+func TestCachedStoreChangePasswordInvalidates(t *testing.T) {
+	ctx := context.Background()
+	s, _, mr := newTestCachedStore(t)
+	if _, err := s.CreateUser(ctx, models.User{ID: "u1", Email: "ann@example.com", Password: "old-hash"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetUser(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ChangePassword(ctx, "u1", "new-hash"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if mr.Exists(userCacheKey("u1")) {
+		t.Errorf("%s is still cached after ChangePassword", userCacheKey("u1"))
+	}
+	user, err := s.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.TokenVersion != 1 {
+		t.Errorf("token version = %d after ChangePassword, want 1", user.TokenVersion)
+	}
+}
+
+func TestCachedStoreUpsertFoodsInvalidates(t *testing.T) {
+	ctx := context.Background()
+	s, inner, mr := newTestCachedStore(t)
+	food := models.Food{ID: "gtin-0012345678905", Name: "Granola", Barcode: "0012345678905", Per100g: models.Nutrients{Calories: 470}}
+	if _, err := s.UpsertFoods(ctx, []models.Food{food}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetFood(ctx, food.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetFoodByBarcode(ctx, food.Barcode); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range foodCacheKeys(food) {
+		if !mr.Exists(key) {
+			t.Fatalf("%s is not cached", key)
+		}
+	}
+
+	food.Per100g.Calories = 450
+	result, err := s.UpsertFoods(ctx, []models.Food{food})
+	if err != nil {
+		t.Fatalf("UpsertFoods: %v", err)
+	}
+	if result.Updated != 1 {
+		t.Fatalf("UpsertFoods updated %d foods, want 1", result.Updated)
+	}
+	for _, key := range foodCacheKeys(food) {
+		if mr.Exists(key) {
+			t.Errorf("%s is still cached after UpsertFoods", key)
+		}
+	}
+
+	got, err := s.GetFoodByBarcode(ctx, food.Barcode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Per100g.Calories != 450 {
+		t.Errorf("GetFoodByBarcode returned %v kcal, want 450", got.Per100g.Calories)
+	}
+	if calls := inner.calls["GetFoodByBarcode"]; calls != 2 {
+		t.Errorf("store read %d times, want 2", calls)
+	}
+}
+
+func TestCachedStorePackageListVersion(t *testing.T) {
+	ctx := context.Background()
+	s, inner, mr := newTestCachedStore(t)
+	query := MealPackageQuery{Limit: 2}
+
+	first, err := s.GetMealPackages(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("sample packages fit on one page; the test needs a second one")
+	}
+	next := query
+	next.Cursor = first.NextCursor
+	if _, err := s.GetMealPackages(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetMealPackages(ctx, query); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetMealPackages(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	if got := inner.calls["GetMealPackages"]; got != 2 {
+		t.Fatalf("store read %d times, want 2 (each page once)", got)
+	}
+	if !mr.Exists(mealPackagesCacheKey(0, next.cacheKey())) {
+		t.Fatalf("second page is not cached under version 0")
+	}
+
+	if _, err := s.CreateMealPackage(ctx, models.MealPackage{ID: "new", Name: "AAA first by name", BaseCalories: 100}); err != nil {
+		t.Fatalf("CreateMealPackage: %v", err)
+	}
+	if version, _ := mr.Get(mealPackagesVersionKey()); version != "1" {
+		t.Fatalf("%s = %q, want 1", mealPackagesVersionKey(), version)
+	}
+
+	page, err := s.GetMealPackages(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Packages) == 0 || page.Packages[0].ID != "new" {
+		t.Errorf("first page after create does not start with the new package: %+v", page.Packages)
+	}
+	if _, err := s.GetMealPackages(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+	if got := inner.calls["GetMealPackages"]; got != 4 {
+		t.Errorf("store read %d times, want 4: both pages must be read again", got)
+	}
+}
+
+func TestCachedStoreRedisDown(t *testing.T) {
+	ctx := context.Background()
+	s, inner, mr := newTestCachedStore(t)
+	if _, err := inner.CreateUser(ctx, models.User{ID: "u1", Name: "Ann", Email: "ann@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	mr.Close()
+
+	for i := 0; i < 2; i++ {
+		user, err := s.GetUser(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUser with Redis down: %v", err)
+		}
+		if user.Name != "Ann" {
+			t.Fatalf("GetUser returned %q, want Ann", user.Name)
+		}
+	}
+	if got := inner.calls["GetUser"]; got != 2 {
+		t.Errorf("store read %d times, want 2: every read goes to the store", got)
+	}
+
+	if _, err := s.CreateMealEntry(ctx, models.MealEntry{UserID: "u1", Calories: 300, Date: testDay}); err != nil {
+		t.Fatalf("CreateMealEntry with Redis down: %v", err)
+	}
+	if got := len(mealEntriesOfDay(t, s, "u1")); got != 1 {
+		t.Errorf("got %d entries with Redis down, want 1", got)
+	}
+	if _, err := s.GetMealPackages(ctx, MealPackageQuery{}); err != nil {
+		t.Errorf("GetMealPackages with Redis down: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sentinel errors returned by Store implementations.
+// Callers should test for them with errors.Is, since they are usually wrapped with context.
+var (
+	// ErrNotFound indicates that the requested document does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates a uniqueness violation, such as a duplicate email, or an update based on a stale version
+	ErrConflict = errors.New("conflict")
+	// ErrValidation indicates that the input was rejected
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable indicates that the backing store could not be reached
+	ErrUnavailable = errors.New("store unavailable")
+)
+
+// FieldError describes why a single input field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports one or more invalid fields. It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError for a single field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) true for validation errors
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// notFound builds an ErrNotFound error for the given kind of document
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, ErrNotFound)
+}
+
+// wrapMongoError classifies a MongoDB driver error into one of the sentinel errors.
+// The action describes what was attempted and prefixes the message.
+func wrapMongoError(action string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// Leave the caller's cancellation or deadline visible as-is
+		return fmt.Errorf("%s: %w", action, err)
+	case mongo.IsDuplicateKeyError(err):
+		return fmt.Errorf("%s: %w", action, ErrConflict)
+	case mongo.IsNetworkError(err), mongo.IsTimeout(err), errors.Is(err, mongo.ErrClientDisconnected):
+		return fmt.Errorf("%s: %w: %v", action, ErrUnavailable, err)
+	default:
+		return fmt.Errorf("%s: %w", action, err)
+	}
+}
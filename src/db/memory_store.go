@@ -0,0 +1,966 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/utils"
+)
+
+// MemoryStore implements the Store interface with in-memory maps.
+// It is intended for development, demos and tests; data is lost on restart.
+type MemoryStore struct {
+	mu                     sync.RWMutex
+	users                  map[string]models.User
+	mealPackages           map[string]models.MealPackage
+	workoutPackages        map[string]models.WorkoutPackage
+	mealPackageVersions    map[string]models.MealPackage // Keyed by packageVersionID
+	workoutPackageVersions map[string]models.WorkoutPackage
+	foods                  map[string]models.Food
+	mealEntries            map[string]models.MealEntry
+	workoutEntries         map[string]models.WorkoutEntry
+	weightEntries          map[string]models.WeightEntry
+	userInfo               map[string]models.UserInfo
+}
+
+// NewMemoryStore creates a new in-memory store seeded with the sample packages
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		users:                  make(map[string]models.User),
+		mealPackages:           make(map[string]models.MealPackage),
+		workoutPackages:        make(map[string]models.WorkoutPackage),
+		mealPackageVersions:    make(map[string]models.MealPackage),
+		workoutPackageVersions: make(map[string]models.WorkoutPackage),
+		foods:                  make(map[string]models.Food),
+		mealEntries:            make(map[string]models.MealEntry),
+		workoutEntries:         make(map[string]models.WorkoutEntry),
+		weightEntries:          make(map[string]models.WeightEntry),
+		userInfo:               make(map[string]models.UserInfo),
+	}
+
+	for _, pkg := range sampleMealPackages() {
+		pkg.Version = 1
+		s.mealPackages[pkg.ID] = pkg
+		s.mealPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	}
+	for _, pkg := range sampleWorkoutPackages() {
+		pkg.Version = 1
+		s.workoutPackages[pkg.ID] = pkg
+		s.workoutPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	}
+
+	return s
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// GetUsers returns all users ordered by creation time
+func (s *MemoryStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]models.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+// GetUser returns a specific user by ID
+func (s *MemoryStore) GetUser(ctx context.Context, id string) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, notFound("user", id)
+	}
+
+	return user, nil
+}
+
+// GetUserByEmail returns a specific user by email address
+func (s *MemoryStore) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return models.User{}, notFound("user with email", email)
+}
+
+// CreateUser creates a new user, enforcing unique email addresses
+func (s *MemoryStore) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = utils.GenerateID()
+	}
+	if _, exists := s.users[user.ID]; exists {
+		return models.User{}, fmt.Errorf("user %s already exists: %w", user.ID, ErrConflict)
+	}
+	for _, existing := range s.users {
+		if existing.Email == user.Email {
+			return models.User{}, fmt.Errorf("email %s is already registered: %w", user.Email, ErrConflict)
+		}
+	}
+
+	s.users[user.ID] = user
+	return user, nil
+}
+
+// UpdateUser replaces an existing user, enforcing unique email addresses. The stored
+// password, token version and last login time are kept, so a stale copy cannot undo
+// a password change or login made meanwhile.
+func (s *MemoryStore) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.users[user.ID]
+	if !ok {
+		return models.User{}, notFound("user", user.ID)
+	}
+	for id, other := range s.users {
+		if id != user.ID && other.Email == user.Email {
+			return models.User{}, fmt.Errorf("email %s is already registered: %w", user.Email, ErrConflict)
+		}
+	}
+
+	user.Password = existing.Password
+	user.TokenVersion = existing.TokenVersion
+	user.LastLoginAt = existing.LastLoginAt
+
+	s.users[user.ID] = user
+	return user, nil
+}
+
+// RecordLogin sets a user's last login time
+func (s *MemoryStore) RecordLogin(ctx context.Context, id string, at time.Time) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, notFound("user", id)
+	}
+
+	user.LastLoginAt = at
+	s.users[id] = user
+	return user, nil
+}
+
+// ChangePassword sets a user's password hash and increments their token version
+func (s *MemoryStore) ChangePassword(ctx context.Context, id, hash string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, notFound("user", id)
+	}
+
+	user.Password = hash
+	user.TokenVersion++
+	s.users[id] = user
+	return user, nil
+}
+
+// UpdateAdaptiveTDEE stores a user's adaptive TDEE estimate and, unless targets is
+// nil, the goal targets calculated from it (see models.GoalInfo.WithTargets). Only
+// those fields are written, so profile changes made meanwhile are kept, and the
+// targets are skipped when the user has switched to MANUAL targets meanwhile.
+func (s *MemoryStore) UpdateAdaptiveTDEE(ctx context.Context, id string, estimate models.TDEEEstimate, targets *models.GoalInfo) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, notFound("user", id)
+	}
+
+	user.AdaptiveTDEE = &estimate
+	if targets != nil && !user.Goal.IsManual() {
+		user.Goal = user.Goal.WithTargets(*targets)
+	}
+
+	s.users[id] = user
+	return user, nil
+}
+
+// DeleteUser deletes a user by ID and returns the deleted user
+func (s *MemoryStore) DeleteUser(ctx context.Context, id string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, notFound("user", id)
+	}
+	delete(s.users, id)
+
+	return user, nil
+}
+
+// GetMealPackages returns one page of the meal packages matching the query
+func (s *MemoryStore) GetMealPackages(ctx context.Context, query MealPackageQuery) (models.MealPackagePage, error) {
+	cursor, err := normalizeMealQuery(&query)
+	if err != nil {
+		return models.MealPackagePage{}, err
+	}
+	terms := searchTerms(query.Search)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]sortable[models.MealPackage], 0, len(s.mealPackages))
+	for _, pkg := range s.mealPackages {
+		if pkg.Archived && !query.IncludeArchived {
+			continue
+		}
+		if !matchesScope(pkg.OwnerID, pkg.Visibility, query.Scope, query.Viewer) {
+			continue
+		}
+		if !matchesGoal(pkg.GoalType, query.GoalType) {
+			continue
+		}
+		if query.MealType != "" && pkg.MealType != query.MealType {
+			continue
+		}
+		if !query.Calories.contains(pkg.BaseCalories) || !query.Protein.contains(pkg.BaseProtein) ||
+			!query.Carbs.contains(pkg.BaseCarbs) || !query.Fat.contains(pkg.BaseFat) {
+			continue
+		}
+		if !matchesSearch(terms, append([]string{pkg.Name, pkg.Description}, pkg.Ingredients...)...) {
+			continue
+		}
+		matches = append(matches, sortable[models.MealPackage]{item: pkg, id: pkg.ID, value: mealSortValue(pkg, query.Sort)})
+	}
+
+	packages, next := paginate(matches, query.Sort, query.Descending, cursor, query.Limit)
+	return models.MealPackagePage{Packages: packages, Total: int64(len(matches)), NextCursor: next}, nil
+}
+
+// GetMealPackage returns a specific meal package by ID
+func (s *MemoryStore) GetMealPackage(ctx context.Context, id string) (models.MealPackage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pkg, ok := s.mealPackages[id]
+	if !ok {
+		return models.MealPackage{}, notFound("meal package", id)
+	}
+
+	return pkg, nil
+}
+
+// GetMealPackageVersion returns a meal package as it was at the given version
+func (s *MemoryStore) GetMealPackageVersion(ctx context.Context, id string, version int) (models.MealPackage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pkg, ok := s.mealPackageVersions[packageVersionID(id, version)]
+	if !ok {
+		return models.MealPackage{}, notFound("meal package version", packageVersionID(id, version))
+	}
+
+	return pkg, nil
+}
+
+// CreateMealPackage creates a meal package at version 1
+func (s *MemoryStore) CreateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pkg.ID == "" {
+		pkg.ID = utils.GenerateID()
+	}
+	if _, exists := s.mealPackages[pkg.ID]; exists {
+		return models.MealPackage{}, fmt.Errorf("meal package %s already exists: %w", pkg.ID, ErrConflict)
+	}
+
+	now := time.Now()
+	pkg.Version = 1
+	pkg.CreatedAt = &now
+	pkg.UpdatedAt = &now
+
+	s.mealPackages[pkg.ID] = pkg
+	s.mealPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	return pkg, nil
+}
+
+// UpdateMealPackage replaces the version of a meal package given in pkg with the next version
+func (s *MemoryStore) UpdateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.mealPackages[pkg.ID]
+	if !ok {
+		return models.MealPackage{}, notFound("meal package", pkg.ID)
+	}
+	if current.Version != pkg.Version {
+		return models.MealPackage{}, versionConflict("meal package", pkg.ID, pkg.Version)
+	}
+
+	now := time.Now()
+	pkg.Version++
+	pkg.OwnerID = current.OwnerID
+	pkg.Archived = current.Archived
+	pkg.ArchivedAt = current.ArchivedAt
+	pkg.CreatedAt = current.CreatedAt
+	pkg.UpdatedAt = &now
+
+	s.mealPackages[pkg.ID] = pkg
+	s.mealPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	return pkg, nil
+}
+
+// ArchiveMealPackage archives or restores a meal package. The version is unchanged.
+func (s *MemoryStore) ArchiveMealPackage(ctx context.Context, id string, archived bool) (models.MealPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pkg, ok := s.mealPackages[id]
+	if !ok {
+		return models.MealPackage{}, notFound("meal package", id)
+	}
+
+	setArchived(&pkg.Archived, &pkg.ArchivedAt, archived)
+	s.mealPackages[id] = pkg
+	return pkg, nil
+}
+
+// GetMealPackagesByFoods returns the meal packages whose recipe uses any of the foods
+func (s *MemoryStore) GetMealPackagesByFoods(ctx context.Context, foodIDs []string) ([]models.MealPackage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	packages := make([]models.MealPackage, 0)
+	for _, pkg := range s.mealPackages {
+		if usesAnyFood(pkg.Recipe, foodIDs) {
+			packages = append(packages, pkg)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].ID < packages[j].ID
+	})
+
+	return packages, nil
+}
+
+// GetWorkoutPackages returns one page of the workout packages matching the query
+func (s *MemoryStore) GetWorkoutPackages(ctx context.Context, query WorkoutPackageQuery) (models.WorkoutPackagePage, error) {
+	cursor, err := normalizeWorkoutQuery(&query)
+	if err != nil {
+		return models.WorkoutPackagePage{}, err
+	}
+	terms := searchTerms(query.Search)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]sortable[models.WorkoutPackage], 0, len(s.workoutPackages))
+	for _, pkg := range s.workoutPackages {
+		if pkg.Archived && !query.IncludeArchived {
+			continue
+		}
+		if !matchesScope(pkg.OwnerID, pkg.Visibility, query.Scope, query.Viewer) {
+			continue
+		}
+		if !matchesGoal(pkg.GoalType, query.GoalType) {
+			continue
+		}
+		if query.WorkoutType != "" && pkg.WorkoutType != query.WorkoutType {
+			continue
+		}
+		if !query.Calories.contains(pkg.BaseCaloriesBurn) || !query.Duration.contains(pkg.BaseDurationMinutes) {
+			continue
+		}
+		if !matchesSearch(terms, append([]string{pkg.Name, pkg.Description}, pkg.Instructions...)...) {
+			continue
+		}
+		matches = append(matches, sortable[models.WorkoutPackage]{item: pkg, id: pkg.ID, value: workoutSortValue(pkg, query.Sort)})
+	}
+
+	packages, next := paginate(matches, query.Sort, query.Descending, cursor, query.Limit)
+	return models.WorkoutPackagePage{Packages: packages, Total: int64(len(matches)), NextCursor: next}, nil
+}
+
+// GetWorkoutPackage returns a specific workout package by ID
+func (s *MemoryStore) GetWorkoutPackage(ctx context.Context, id string) (models.WorkoutPackage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pkg, ok := s.workoutPackages[id]
+	if !ok {
+		return models.WorkoutPackage{}, notFound("workout package", id)
+	}
+
+	return pkg, nil
+}
+
+// GetWorkoutPackageVersion returns a workout package as it was at the given version
+func (s *MemoryStore) GetWorkoutPackageVersion(ctx context.Context, id string, version int) (models.WorkoutPackage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pkg, ok := s.workoutPackageVersions[packageVersionID(id, version)]
+	if !ok {
+		return models.WorkoutPackage{}, notFound("workout package version", packageVersionID(id, version))
+	}
+
+	return pkg, nil
+}
+
+// CreateWorkoutPackage creates a workout package at version 1
+func (s *MemoryStore) CreateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pkg.ID == "" {
+		pkg.ID = utils.GenerateID()
+	}
+	if _, exists := s.workoutPackages[pkg.ID]; exists {
+		return models.WorkoutPackage{}, fmt.Errorf("workout package %s already exists: %w", pkg.ID, ErrConflict)
+	}
+
+	now := time.Now()
+	pkg.Version = 1
+	pkg.CreatedAt = &now
+	pkg.UpdatedAt = &now
+
+	s.workoutPackages[pkg.ID] = pkg
+	s.workoutPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	return pkg, nil
+}
+
+// UpdateWorkoutPackage replaces the version of a workout package given in pkg with the next version
+func (s *MemoryStore) UpdateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.workoutPackages[pkg.ID]
+	if !ok {
+		return models.WorkoutPackage{}, notFound("workout package", pkg.ID)
+	}
+	if current.Version != pkg.Version {
+		return models.WorkoutPackage{}, versionConflict("workout package", pkg.ID, pkg.Version)
+	}
+
+	now := time.Now()
+	pkg.Version++
+	pkg.OwnerID = current.OwnerID
+	pkg.Archived = current.Archived
+	pkg.ArchivedAt = current.ArchivedAt
+	pkg.CreatedAt = current.CreatedAt
+	pkg.UpdatedAt = &now
+
+	s.workoutPackages[pkg.ID] = pkg
+	s.workoutPackageVersions[packageVersionID(pkg.ID, pkg.Version)] = pkg
+	return pkg, nil
+}
+
+// ArchiveWorkoutPackage archives or restores a workout package. The version is unchanged.
+func (s *MemoryStore) ArchiveWorkoutPackage(ctx context.Context, id string, archived bool) (models.WorkoutPackage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pkg, ok := s.workoutPackages[id]
+	if !ok {
+		return models.WorkoutPackage{}, notFound("workout package", id)
+	}
+
+	setArchived(&pkg.Archived, &pkg.ArchivedAt, archived)
+	s.workoutPackages[id] = pkg
+	return pkg, nil
+}
+
+// GetFoods returns one page of the foods matching the query, ordered by name
+func (s *MemoryStore) GetFoods(ctx context.Context, query FoodQuery) (models.FoodPage, error) {
+	cursor, err := normalizeFoodQuery(&query)
+	if err != nil {
+		return models.FoodPage{}, err
+	}
+	terms := searchTerms(query.Search)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]sortable[models.Food], 0, len(s.foods))
+	for _, food := range s.foods {
+		if !matchesSearch(terms, food.Name) {
+			continue
+		}
+		matches = append(matches, sortable[models.Food]{item: food, id: food.ID, value: food.Name})
+	}
+
+	foods, next := paginate(matches, SortByName, false, cursor, query.Limit)
+	return models.FoodPage{Foods: foods, Total: int64(len(matches)), NextCursor: next}, nil
+}
+
+// GetFood returns a specific food by ID
+func (s *MemoryStore) GetFood(ctx context.Context, id string) (models.Food, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	food, ok := s.foods[id]
+	if !ok {
+		return models.Food{}, notFound("food", id)
+	}
+
+	return food, nil
+}
+
+// GetFoodByBarcode returns the food with a barcode. Should several foods share
+// it, the one with the lowest ID is returned.
+func (s *MemoryStore) GetFoodByBarcode(ctx context.Context, barcode string) (models.Food, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var match *models.Food
+	for _, food := range s.foods {
+		if food.Barcode == barcode && (match == nil || food.ID < match.ID) {
+			match = &food
+		}
+	}
+	if match == nil {
+		return models.Food{}, notFound("food with barcode", barcode)
+	}
+
+	return *match, nil
+}
+
+// CreateFood creates a food
+func (s *MemoryStore) CreateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if food.ID == "" {
+		food.ID = utils.GenerateID()
+	}
+	if _, exists := s.foods[food.ID]; exists {
+		return models.Food{}, fmt.Errorf("food %s already exists: %w", food.ID, ErrConflict)
+	}
+
+	now := time.Now()
+	food.CreatedAt = &now
+	food.UpdatedAt = &now
+
+	s.foods[food.ID] = food
+	return food, nil
+}
+
+// UpdateFood replaces a food, keeping the barcode and source of an imported one
+func (s *MemoryStore) UpdateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.foods[food.ID]
+	if !ok {
+		return models.Food{}, notFound("food", food.ID)
+	}
+
+	now := time.Now()
+	food.Barcode = current.Barcode
+	food.Source = current.Source
+	food.SourceID = current.SourceID
+	food.CreatedAt = current.CreatedAt
+	food.UpdatedAt = &now
+
+	s.foods[food.ID] = food
+	return food, nil
+}
+
+// UpsertFoods creates the foods or merges them into the stored ones with the same ID
+func (s *MemoryStore) UpsertFoods(ctx context.Context, foods []models.Food) (FoodUpsertResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result FoodUpsertResult
+	now := time.Now()
+	for _, food := range foods {
+		current, exists := s.foods[food.ID]
+		if exists {
+			food = current.MergeImport(food)
+			result.Updated++
+		} else {
+			food.CreatedAt = &now
+			result.Created++
+		}
+		food.UpdatedAt = &now
+		s.foods[food.ID] = food
+	}
+
+	return result, nil
+}
+
+// CreateMealEntry creates a new meal entry
+func (s *MemoryStore) CreateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure the entry has an ID
+	if entry.ID == "" {
+		entry.ID = utils.GenerateID()
+	}
+	// Ensure the timestamp is set
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if _, exists := s.mealEntries[entry.ID]; exists {
+		return models.MealEntry{}, fmt.Errorf("meal entry %s already exists: %w", entry.ID, ErrConflict)
+	}
+
+	s.mealEntries[entry.ID] = entry
+	return entry, nil
+}
+
+// GetMealEntriesByUserAndDateRange returns meal entries for a user within a date range
+func (s *MemoryStore) GetMealEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.MealEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]models.MealEntry, 0)
+	for _, entry := range s.mealEntries {
+		if entry.UserID == userID && inRange(entry.Date, startDate, endDate) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// GetMealEntry retrieves a meal entry by ID
+func (s *MemoryStore) GetMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.mealEntries[id]
+	if !ok {
+		return models.MealEntry{}, notFound("meal entry", id)
+	}
+
+	return entry, nil
+}
+
+// UpdateMealEntry replaces an existing meal entry
+func (s *MemoryStore) UpdateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.mealEntries[entry.ID]; !ok {
+		return models.MealEntry{}, notFound("meal entry", entry.ID)
+	}
+
+	s.mealEntries[entry.ID] = entry
+	return entry, nil
+}
+
+// DeleteMealEntry removes a meal entry and returns it
+func (s *MemoryStore) DeleteMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.mealEntries[id]
+	if !ok {
+		return models.MealEntry{}, notFound("meal entry", id)
+	}
+	delete(s.mealEntries, id)
+
+	return entry, nil
+}
+
+// CreateWorkoutEntry creates a new workout entry
+func (s *MemoryStore) CreateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure the entry has an ID
+	if entry.ID == "" {
+		entry.ID = utils.GenerateID()
+	}
+	// Ensure the timestamp is set
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if _, exists := s.workoutEntries[entry.ID]; exists {
+		return models.WorkoutEntry{}, fmt.Errorf("workout entry %s already exists: %w", entry.ID, ErrConflict)
+	}
+
+	s.workoutEntries[entry.ID] = entry
+	return entry, nil
+}
+
+// GetWorkoutEntriesByUserAndDateRange returns workout entries for a user within a date range
+func (s *MemoryStore) GetWorkoutEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WorkoutEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]models.WorkoutEntry, 0)
+	for _, entry := range s.workoutEntries {
+		if entry.UserID == userID && inRange(entry.Date, startDate, endDate) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// GetWorkoutEntry retrieves a workout entry by ID
+func (s *MemoryStore) GetWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.workoutEntries[id]
+	if !ok {
+		return models.WorkoutEntry{}, notFound("workout entry", id)
+	}
+
+	return entry, nil
+}
+
+// UpdateWorkoutEntry replaces an existing workout entry
+func (s *MemoryStore) UpdateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workoutEntries[entry.ID]; !ok {
+		return models.WorkoutEntry{}, notFound("workout entry", entry.ID)
+	}
+
+	s.workoutEntries[entry.ID] = entry
+	return entry, nil
+}
+
+// DeleteWorkoutEntry removes a workout entry and returns it
+func (s *MemoryStore) DeleteWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.workoutEntries[id]
+	if !ok {
+		return models.WorkoutEntry{}, notFound("workout entry", id)
+	}
+	delete(s.workoutEntries, id)
+
+	return entry, nil
+}
+
+// CreateWeightEntry creates a new weight entry
+func (s *MemoryStore) CreateWeightEntry(ctx context.Context, entry models.WeightEntry) (models.WeightEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure the entry has an ID
+	if entry.ID == "" {
+		entry.ID = utils.GenerateID()
+	}
+	// Ensure the timestamp is set
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if _, exists := s.weightEntries[entry.ID]; exists {
+		return models.WeightEntry{}, fmt.Errorf("weight entry %s already exists: %w", entry.ID, ErrConflict)
+	}
+
+	s.weightEntries[entry.ID] = entry
+	return entry, nil
+}
+
+// GetWeightEntriesByUserAndDateRange returns weight entries for a user within a date range
+func (s *MemoryStore) GetWeightEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WeightEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]models.WeightEntry, 0)
+	for _, entry := range s.weightEntries {
+		if entry.UserID == userID && inRange(entry.Date, startDate, endDate) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return weightEntryBefore(entries[i], entries[j])
+	})
+
+	return entries, nil
+}
+
+// GetLatestWeightEntry returns the user's most recently dated weight entry
+func (s *MemoryStore) GetLatestWeightEntry(ctx context.Context, userID string) (models.WeightEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest models.WeightEntry
+	found := false
+	for _, entry := range s.weightEntries {
+		if entry.UserID == userID && (!found || weightEntryBefore(latest, entry)) {
+			latest = entry
+			found = true
+		}
+	}
+	if !found {
+		return models.WeightEntry{}, fmt.Errorf("weight entries for user %s: %w", userID, ErrNotFound)
+	}
+
+	return latest, nil
+}
+
+// GetWeightEntry retrieves a weight entry by ID
+func (s *MemoryStore) GetWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.weightEntries[id]
+	if !ok {
+		return models.WeightEntry{}, notFound("weight entry", id)
+	}
+
+	return entry, nil
+}
+
+// DeleteWeightEntry removes a weight entry and returns it
+func (s *MemoryStore) DeleteWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.weightEntries[id]
+	if !ok {
+		return models.WeightEntry{}, notFound("weight entry", id)
+	}
+	delete(s.weightEntries, id)
+
+	return entry, nil
+}
+
+// CreateUserInfo stores a health metrics snapshot
+func (s *MemoryStore) CreateUserInfo(ctx context.Context, info models.UserInfo) (models.UserInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if info.ID == "" {
+		info.ID = utils.GenerateID()
+	}
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = time.Now()
+	}
+	if _, exists := s.userInfo[info.ID]; exists {
+		return models.UserInfo{}, fmt.Errorf("user info %s already exists: %w", info.ID, ErrConflict)
+	}
+
+	s.userInfo[info.ID] = info
+	return info, nil
+}
+
+// GetUserInfoHistory returns a user's health metrics snapshots taken within a time range, oldest first
+func (s *MemoryStore) GetUserInfoHistory(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.UserInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := make([]models.UserInfo, 0)
+	for _, info := range s.userInfo {
+		if info.UserID == userID && inRange(info.CreatedAt, startDate, endDate) {
+			history = append(history, info)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].CreatedAt.Before(history[j].CreatedAt)
+	})
+
+	return history, nil
+}
+
+// weightEntryBefore orders weight entries by date, then by the time they were logged
+func weightEntryBefore(a, b models.WeightEntry) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.Before(b.Date)
+	}
+	return a.Timestamp.Before(b.Timestamp)
+}
+
+// GetDailyTotals returns per-day entry totals for a user within a date range.
+// Only days with at least one entry are returned, ordered by date.
+func (s *MemoryStore) GetDailyTotals(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyTotals, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byDate := make(map[string]*models.DailyTotals)
+	day := func(t time.Time) *models.DailyTotals {
+		date := t.UTC().Format("2006-01-02")
+		if byDate[date] == nil {
+			byDate[date] = &models.DailyTotals{Date: date}
+		}
+		return byDate[date]
+	}
+
+	for _, entry := range s.mealEntries {
+		if entry.UserID == userID && inRange(entry.Date, startDate, endDate) {
+			totals := day(entry.Date)
+			totals.Calories += entry.Calories
+			totals.Protein += entry.Protein
+			totals.Carbs += entry.Carbs
+			totals.Fat += entry.Fat
+			totals.MealCount++
+		}
+	}
+	for _, entry := range s.workoutEntries {
+		if entry.UserID == userID && inRange(entry.Date, startDate, endDate) {
+			totals := day(entry.Date)
+			totals.Burned += entry.CaloriesBurned
+			totals.WorkoutCount++
+		}
+	}
+
+	result := make([]models.DailyTotals, 0, len(byDate))
+	for _, totals := range byDate {
+		result = append(result, *totals)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+
+	return result, nil
+}
+
+// inRange reports whether t lies within [start, end], matching the $gte/$lte
+// semantics of the MongoDB queries
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+// setArchived sets or clears the archived flag and time of a package
+func setArchived(flag *bool, at **time.Time, archived bool) {
+	if *flag == archived {
+		return
+	}
+	*flag = archived
+	*at = nil
+	if archived {
+		now := time.Now()
+		*at = &now
+	}
+}
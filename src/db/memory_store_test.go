@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+func TestMemoryStoreUpdateUserKeepsCredentials(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	stale, err := s.CreateUser(ctx, models.User{ID: "u1", Email: "ann@example.com", Password: "old-hash", Weight: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loginAt := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	if _, err := s.ChangePassword(ctx, "u1", "new-hash"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := s.RecordLogin(ctx, "u1", loginAt); err != nil {
+		t.Fatalf("RecordLogin: %v", err)
+	}
+
+	// A profile update from a copy read before the password change
+	stale.Weight = 78
+	updated, err := s.UpdateUser(ctx, stale)
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	got, err := s.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []models.User{updated, got} {
+		if user.Password != "new-hash" || user.TokenVersion != 1 || !user.LastLoginAt.Equal(loginAt) {
+			t.Errorf("credentials reverted: password %q, token version %d, last login %v",
+				user.Password, user.TokenVersion, user.LastLoginAt)
+		}
+		if user.Weight != 78 {
+			t.Errorf("weight = %v, want 78", user.Weight)
+		}
+	}
+}
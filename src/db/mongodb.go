@@ -20,20 +20,28 @@ const (
 	usersCollection           = "users"
 	mealPackagesCollection    = "meal_packages"
 	workoutPackagesCollection = "workout_packages"
+	mealPackageVersions       = "meal_package_versions"
+	workoutPackageVersions    = "workout_package_versions"
+	foodsCollection           = "foods"
 	mealEntriesCollection     = "meal_entries"
 	workoutEntriesCollection  = "workout_entries"
+	weightEntriesCollection   = "weight_entries"
+	userInfoCollection        = "user_info"
 )
 
+// connectTimeout bounds connecting to, setting up and disconnecting from MongoDB
+const connectTimeout = 10 * time.Second
+
 // MongoStore implements the Store interface using MongoDB
 type MongoStore struct {
 	client *mongo.Client
 	db     *mongo.Database
-	ctx    context.Context
 }
 
 // NewMongoStore creates a new MongoDB-backed store
 func NewMongoStore(cfg *config.AppConfig) (*MongoStore, error) {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
 
 	// Configure MongoDB connection options
 	serverAPIOptions := options.ServerAPI(options.ServerAPIVersion1)
@@ -54,17 +62,17 @@ func NewMongoStore(cfg *config.AppConfig) (*MongoStore, error) {
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w: %v", ErrUnavailable, err)
 	}
 
 	// Ping the database to verify connection
 	err = client.Ping(ctx, nil)
 	if err != nil {
 		// Clean up if connection fails
-		if closeErr := client.Disconnect(ctx); closeErr != nil {
+		if closeErr := client.Disconnect(context.Background()); closeErr != nil {
 			log.Printf("Error disconnecting from MongoDB: %v", closeErr)
 		}
-		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to ping MongoDB: %w: %v", ErrUnavailable, err)
 	}
 
 	// Create MongoStore instance
@@ -72,11 +80,10 @@ func NewMongoStore(cfg *config.AppConfig) (*MongoStore, error) {
 	store := &MongoStore{
 		client: client,
 		db:     database,
-		ctx:    ctx,
 	}
 
 	// Create indexes
-	if err := store.createIndexes(); err != nil {
+	if err := store.createIndexes(ctx); err != nil {
 		log.Printf("Warning: Failed to create indexes: %v", err)
 	}
 
@@ -85,14 +92,17 @@ func NewMongoStore(cfg *config.AppConfig) (*MongoStore, error) {
 
 // Close closes the MongoDB connection
 func (s *MongoStore) Close() error {
-	return s.client.Disconnect(s.ctx)
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	return s.client.Disconnect(ctx)
 }
 
 // createIndexes creates indexes for the MongoDB collections
-func (s *MongoStore) createIndexes() error {
+func (s *MongoStore) createIndexes(ctx context.Context) error {
 	// Create indexes for users collection
 	_, err := s.db.Collection(usersCollection).Indexes().CreateOne(
-		s.ctx,
+		ctx,
 		mongo.IndexModel{
 			Keys:    bson.D{{Key: "email", Value: 1}},
 			Options: options.Index().SetUnique(true),
@@ -102,161 +112,835 @@ func (s *MongoStore) createIndexes() error {
 		return err
 	}
 
-	// More indexes could be added here as needed
+	// Entry range queries filter on user and date
+	for _, collection := range []string{mealEntriesCollection, workoutEntriesCollection, weightEntriesCollection} {
+		_, err = s.db.Collection(collection).Indexes().CreateOne(
+			ctx,
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: 1}},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Package lists support text search on their descriptive fields
+	textIndexes := map[string]bson.D{
+		mealPackagesCollection:    {{Key: "name", Value: "text"}, {Key: "description", Value: "text"}, {Key: "ingredients", Value: "text"}},
+		workoutPackagesCollection: {{Key: "name", Value: "text"}, {Key: "description", Value: "text"}, {Key: "instructions", Value: "text"}},
+		foodsCollection:           {{Key: "name", Value: "text"}},
+	}
+	for collection, keys := range textIndexes {
+		_, err = s.db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Users list their own packages by owner
+	for _, collection := range []string{mealPackagesCollection, workoutPackagesCollection} {
+		_, err = s.db.Collection(collection).Indexes().CreateOne(
+			ctx,
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "ownerId", Value: 1}},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Packaged foods are looked up by barcode; most foods have none
+	_, err = s.db.Collection(foodsCollection).Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "barcode", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Correcting or importing foods recomputes the packages whose recipe uses them
+	_, err = s.db.Collection(mealPackagesCollection).Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "recipe.foodId", Value: 1}},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Metrics history is queried by user and snapshot time
+	_, err = s.db.Collection(userInfoCollection).Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}},
+		},
+	)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 // GetUsers returns all users
-func (s *MongoStore) GetUsers() []models.User {
-	var users []models.User
-	cursor, err := s.db.Collection(usersCollection).Find(s.ctx, bson.D{})
+func (s *MongoStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	users := make([]models.User, 0)
+	cursor, err := s.db.Collection(usersCollection).Find(ctx, bson.D{})
 	if err != nil {
-		log.Printf("Error fetching users: %v", err)
-		return users
+		return nil, wrapMongoError("failed to fetch users", err)
 	}
-	defer cursor.Close(s.ctx)
+	defer cursor.Close(ctx)
 
-	if err := cursor.All(s.ctx, &users); err != nil {
-		log.Printf("Error decoding users: %v", err)
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, wrapMongoError("failed to decode users", err)
 	}
 
-	return users
+	return users, nil
 }
 
 // GetUser returns a specific user by ID
-func (s *MongoStore) GetUser(id string) (models.User, error) {
+func (s *MongoStore) GetUser(ctx context.Context, id string) (models.User, error) {
 	var user models.User
 
-	objID, err := primitive.ObjectIDFromHex(id)
+	err := s.db.Collection(usersCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&user)
 	if err != nil {
-		// If not a valid ObjectID, try finding by ID string directly
-		err = s.db.Collection(usersCollection).FindOne(s.ctx, bson.M{"id": id}).Decode(&user)
-	} else {
-		// Try finding by ObjectID
-		err = s.db.Collection(usersCollection).FindOne(s.ctx, bson.M{"_id": objID}).Decode(&user)
+		if err == mongo.ErrNoDocuments {
+			return models.User{}, notFound("user", id)
+		}
+		return models.User{}, wrapMongoError("failed to fetch user", err)
 	}
 
+	return user, nil
+}
+
+// GetUserByEmail returns a specific user by email address
+func (s *MongoStore) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+
+	err := s.db.Collection(usersCollection).FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return models.User{}, errors.New("user not found")
+			return models.User{}, notFound("user with email", email)
 		}
-		return models.User{}, err
+		return models.User{}, wrapMongoError("failed to fetch user", err)
 	}
 
 	return user, nil
 }
 
 // CreateUser creates a new user
-func (s *MongoStore) CreateUser(user models.User) (models.User, error) {
+func (s *MongoStore) CreateUser(ctx context.Context, user models.User) (models.User, error) {
 	// Ensure the user has an ID
 	if user.ID == "" {
 		user.ID = primitive.NewObjectID().Hex()
 	}
 
 	// Convert the model to BSON
-	_, err := s.db.Collection(usersCollection).InsertOne(s.ctx, user)
+	_, err := s.db.Collection(usersCollection).InsertOne(ctx, user)
 	if err != nil {
-		return models.User{}, err
+		if mongo.IsDuplicateKeyError(err) {
+			return models.User{}, fmt.Errorf("email %s is already registered: %w", user.Email, ErrConflict)
+		}
+		return models.User{}, wrapMongoError("failed to create user", err)
 	}
 
 	return user, nil
 }
 
-// GetMealPackages returns meal packages, optionally filtered by goal type
-func (s *MongoStore) GetMealPackages(goalType models.GoalType) []models.MealPackage {
-	var packages []models.MealPackage
+// UpdateUser replaces an existing user document, keeping its password, token version
+// and last login time, which only RecordLogin and ChangePassword write. The stored
+// values are merged back in the same update, so a stale copy cannot undo a password change.
+func (s *MongoStore) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	// $literal keeps values starting with $ from being read as field paths
+	kept := bson.D{
+		{Key: "password", Value: "$password"},
+		{Key: "tokenVersion", Value: "$tokenVersion"},
+		{Key: "lastLoginAt", Value: "$lastLoginAt"},
+	}
+	replacement := bson.D{{Key: "$mergeObjects", Value: bson.A{bson.D{{Key: "$literal", Value: user}}, kept}}}
+	pipeline := mongo.Pipeline{{{Key: "$replaceWith", Value: replacement}}}
 
-	filter := bson.D{}
-	if goalType != models.GoalTypeAll {
-		filter = bson.D{{Key: "goalType", Value: goalType}}
+	var updated models.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.db.Collection(usersCollection).FindOneAndUpdate(ctx, bson.M{"_id": user.ID}, pipeline, opts).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.User{}, notFound("user", user.ID)
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return models.User{}, fmt.Errorf("email %s is already registered: %w", user.Email, ErrConflict)
+		}
+		return models.User{}, wrapMongoError("failed to update user", err)
+	}
+
+	return updated, nil
+}
+
+// RecordLogin sets a user's last login time, leaving the rest of the document as it is
+func (s *MongoStore) RecordLogin(ctx context.Context, id string, at time.Time) (models.User, error) {
+	return s.updateUserFields(ctx, id, nil, bson.M{"$set": bson.M{"lastLoginAt": at}}, "failed to record login")
+}
+
+// ChangePassword sets a user's password hash and increments their token version in one update
+func (s *MongoStore) ChangePassword(ctx context.Context, id, hash string) (models.User, error) {
+	update := bson.M{"$set": bson.M{"password": hash}, "$inc": bson.M{"tokenVersion": 1}}
+	return s.updateUserFields(ctx, id, nil, update, "failed to change password")
+}
+
+// updateUserFields applies an update to one user document and returns the result.
+// Documents that do not also match the optional condition are reported as not found.
+func (s *MongoStore) updateUserFields(ctx context.Context, id string, condition bson.M, update bson.M, message string) (models.User, error) {
+	filter := bson.M{"_id": id}
+	for key, value := range condition {
+		filter[key] = value
+	}
+
+	var user models.User
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.db.Collection(usersCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.User{}, notFound("user", id)
+		}
+		return models.User{}, wrapMongoError(message, err)
+	}
+
+	return user, nil
+}
+
+// UpdateAdaptiveTDEE sets a user's adaptive TDEE estimate and, unless targets is
+// nil, the goal target fields (see models.GoalInfo.WithTargets), leaving the rest of
+// the document as it is. The targets only match goals that are not MANUAL, so a user
+// who switched to MANUAL targets meanwhile keeps them and only gets the estimate.
+func (s *MongoStore) UpdateAdaptiveTDEE(ctx context.Context, id string, estimate models.TDEEEstimate, targets *models.GoalInfo) (models.User, error) {
+	set := bson.M{"adaptiveTdee": estimate}
+	if targets != nil {
+		withTargets := bson.M{
+			"adaptiveTdee":        estimate,
+			"goal.targetCalories": targets.TargetCalories,
+			"goal.targetProtein":  targets.TargetProtein,
+			"goal.targetCarbs":    targets.TargetCarbs,
+			"goal.targetFat":      targets.TargetFat,
+			"goal.bmr":            targets.BMR,
+			"goal.tdee":           targets.TDEE,
+			"goal.adaptiveTdee":   targets.AdaptiveTDEE,
+		}
+		notManual := bson.M{"goal.targetMode": bson.M{"$ne": models.TargetModeManual}}
+		user, err := s.updateUserFields(ctx, id, notManual, bson.M{"$set": withTargets}, "failed to update adaptive TDEE")
+		if !errors.Is(err, ErrNotFound) {
+			return user, err
+		}
+		// The user is MANUAL or gone; writing the estimate alone tells which
 	}
 
-	cursor, err := s.db.Collection(mealPackagesCollection).Find(s.ctx, filter)
+	return s.updateUserFields(ctx, id, nil, bson.M{"$set": set}, "failed to update adaptive TDEE")
+}
+
+// DeleteUser deletes a user by ID and returns the deleted user
+func (s *MongoStore) DeleteUser(ctx context.Context, id string) (models.User, error) {
+	var user models.User
+	err := s.db.Collection(usersCollection).FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.User{}, notFound("user", id)
+		}
+		return models.User{}, wrapMongoError("failed to delete user", err)
+	}
+
+	return user, nil
+}
+
+// mealSortFields maps meal package sort keys to document fields
+var mealSortFields = map[PackageSort]string{
+	SortByName:           "name",
+	SortByCalories:       "baseCalories",
+	SortByProtein:        "baseProtein",
+	SortByProteinDensity: "proteinDensity",
+}
+
+// GetMealPackages returns one page of the meal packages matching the query
+func (s *MongoStore) GetMealPackages(ctx context.Context, query MealPackageQuery) (models.MealPackagePage, error) {
+	cursor, err := normalizeMealQuery(&query)
 	if err != nil {
-		log.Printf("Error fetching meal packages: %v", err)
-		return packages
+		return models.MealPackagePage{}, err
 	}
-	defer cursor.Close(s.ctx)
 
-	if err := cursor.All(s.ctx, &packages); err != nil {
-		log.Printf("Error decoding meal packages: %v", err)
+	filter := packageFilter(query.GoalType, query.Search, query.IncludeArchived, query.Scope, query.Viewer)
+	if query.MealType != "" {
+		filter = append(filter, bson.E{Key: "mealType", Value: query.MealType})
+	}
+	filter = rangeFilter(filter, "baseCalories", query.Calories)
+	filter = rangeFilter(filter, "baseProtein", query.Protein)
+	filter = rangeFilter(filter, "baseCarbs", query.Carbs)
+	filter = rangeFilter(filter, "baseFat", query.Fat)
+
+	// Protein density is derived, so it is computed before sorting on it
+	var computed bson.D
+	if query.Sort == SortByProteinDensity {
+		computed = bson.D{{Key: "proteinDensity", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$baseCalories", 0}}},
+			bson.D{{Key: "$divide", Value: bson.A{bson.D{{Key: "$multiply", Value: bson.A{"$baseProtein", 100}}}, "$baseCalories"}}},
+			0,
+		}}}}}
 	}
 
-	return packages
+	packages, total, err := findPage[models.MealPackage](ctx, s.db.Collection(mealPackagesCollection), filter, computed,
+		mealSortFields[query.Sort], query.Descending, cursor, query.Limit)
+	if err != nil {
+		return models.MealPackagePage{}, wrapMongoError("failed to fetch meal packages", err)
+	}
+
+	page := models.MealPackagePage{Packages: packages, Total: total}
+	if len(packages) > query.Limit {
+		page.Packages = packages[:query.Limit]
+		last := page.Packages[query.Limit-1]
+		page.NextCursor = encodeCursor(pageCursor{Sort: query.Sort, Value: mealSortValue(last, query.Sort), ID: last.ID})
+	}
+	return page, nil
 }
 
 // GetMealPackage returns a specific meal package by ID
-func (s *MongoStore) GetMealPackage(id string) (models.MealPackage, error) {
+func (s *MongoStore) GetMealPackage(ctx context.Context, id string) (models.MealPackage, error) {
 	var pkg models.MealPackage
 
-	objID, err := primitive.ObjectIDFromHex(id)
+	err := s.db.Collection(mealPackagesCollection).FindOne(ctx, idFilter(id)).Decode(&pkg)
 	if err != nil {
-		// If not a valid ObjectID, try finding by ID string directly
-		err = s.db.Collection(mealPackagesCollection).FindOne(s.ctx, bson.M{"id": id}).Decode(&pkg)
-	} else {
-		// Try finding by ObjectID
-		err = s.db.Collection(mealPackagesCollection).FindOne(s.ctx, bson.M{"_id": objID}).Decode(&pkg)
+		if err == mongo.ErrNoDocuments {
+			return models.MealPackage{}, notFound("meal package", id)
+		}
+		return models.MealPackage{}, wrapMongoError("failed to fetch meal package", err)
 	}
 
+	return pkg, nil
+}
+
+// GetMealPackageVersion returns a meal package as it was at the given version
+func (s *MongoStore) GetMealPackageVersion(ctx context.Context, id string, version int) (models.MealPackage, error) {
+	pkg, err := findPackageVersion[models.MealPackage](ctx, s.db.Collection(mealPackageVersions), id, version)
+	if err == mongo.ErrNoDocuments {
+		// Packages written before versioning have no stored versions, only the current one
+		current, err := s.GetMealPackage(ctx, id)
+		if err != nil || current.Version != version {
+			return models.MealPackage{}, notFound("meal package version", packageVersionID(id, version))
+		}
+		return current, nil
+	}
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return models.MealPackage{}, errors.New("meal package not found")
+		return models.MealPackage{}, wrapMongoError("failed to fetch meal package version", err)
+	}
+
+	return pkg, nil
+}
+
+// CreateMealPackage creates a meal package at version 1
+func (s *MongoStore) CreateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	if pkg.ID == "" {
+		pkg.ID = primitive.NewObjectID().Hex()
+	}
+
+	now := time.Now()
+	pkg.Version = 1
+	pkg.CreatedAt = &now
+	pkg.UpdatedAt = &now
+
+	_, err := s.db.Collection(mealPackagesCollection).InsertOne(ctx, pkg)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.MealPackage{}, fmt.Errorf("meal package %s already exists: %w", pkg.ID, ErrConflict)
 		}
+		return models.MealPackage{}, wrapMongoError("failed to create meal package", err)
+	}
+
+	if err := savePackageVersion(ctx, s.db.Collection(mealPackageVersions), pkg.ID, pkg.Version, pkg); err != nil {
+		return models.MealPackage{}, wrapMongoError("failed to store meal package version", err)
+	}
+
+	return pkg, nil
+}
+
+// UpdateMealPackage replaces the version of a meal package given in pkg with the next version
+func (s *MongoStore) UpdateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error) {
+	current, err := s.GetMealPackage(ctx, pkg.ID)
+	if err != nil {
 		return models.MealPackage{}, err
 	}
+	if current.Version != pkg.Version {
+		return models.MealPackage{}, versionConflict("meal package", pkg.ID, pkg.Version)
+	}
+
+	now := time.Now()
+	pkg.Version++
+	pkg.OwnerID = current.OwnerID
+	pkg.Archived = current.Archived
+	pkg.ArchivedAt = current.ArchivedAt
+	pkg.CreatedAt = current.CreatedAt
+	pkg.UpdatedAt = &now
+
+	// Matching on the version makes concurrent updates of the same version fail
+	result, err := s.db.Collection(mealPackagesCollection).ReplaceOne(ctx, versionFilter(pkg.ID, current.Version), pkg)
+	if err != nil {
+		return models.MealPackage{}, wrapMongoError("failed to update meal package", err)
+	}
+	if result.MatchedCount == 0 {
+		return models.MealPackage{}, versionConflict("meal package", pkg.ID, current.Version)
+	}
+
+	if err := savePackageVersion(ctx, s.db.Collection(mealPackageVersions), pkg.ID, pkg.Version, pkg); err != nil {
+		return models.MealPackage{}, wrapMongoError("failed to store meal package version", err)
+	}
 
 	return pkg, nil
 }
 
-// GetWorkoutPackages returns workout packages, optionally filtered by goal type
-func (s *MongoStore) GetWorkoutPackages(goalType models.GoalType) []models.WorkoutPackage {
-	var packages []models.WorkoutPackage
+// ArchiveMealPackage archives or restores a meal package. The version is unchanged.
+func (s *MongoStore) ArchiveMealPackage(ctx context.Context, id string, archived bool) (models.MealPackage, error) {
+	var pkg models.MealPackage
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.db.Collection(mealPackagesCollection).FindOneAndUpdate(ctx, idFilter(id), archiveUpdate(archived), opts).Decode(&pkg)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.MealPackage{}, notFound("meal package", id)
+		}
+		return models.MealPackage{}, wrapMongoError("failed to archive meal package", err)
+	}
+
+	return pkg, nil
+}
+
+// GetMealPackagesByFoods returns the meal packages whose recipe uses any of the foods
+func (s *MongoStore) GetMealPackagesByFoods(ctx context.Context, foodIDs []string) ([]models.MealPackage, error) {
+	packages := make([]models.MealPackage, 0)
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := s.db.Collection(mealPackagesCollection).Find(ctx, bson.D{{Key: "recipe.foodId", Value: bson.D{{Key: "$in", Value: foodIDs}}}}, opts)
+	if err != nil {
+		return nil, wrapMongoError("failed to fetch meal packages by food", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &packages); err != nil {
+		return nil, wrapMongoError("failed to decode meal packages", err)
+	}
+
+	return packages, nil
+}
+
+// workoutSortFields maps workout package sort keys to document fields
+var workoutSortFields = map[PackageSort]string{
+	SortByName:     "name",
+	SortByCalories: "baseCaloriesBurn",
+	SortByDuration: "baseDurationMinutes",
+	SortByBurnRate: "burnRate",
+}
+
+// GetWorkoutPackages returns one page of the workout packages matching the query
+func (s *MongoStore) GetWorkoutPackages(ctx context.Context, query WorkoutPackageQuery) (models.WorkoutPackagePage, error) {
+	cursor, err := normalizeWorkoutQuery(&query)
+	if err != nil {
+		return models.WorkoutPackagePage{}, err
+	}
+
+	filter := packageFilter(query.GoalType, query.Search, query.IncludeArchived, query.Scope, query.Viewer)
+	if query.WorkoutType != "" {
+		filter = append(filter, bson.E{Key: "workoutType", Value: query.WorkoutType})
+	}
+	filter = rangeFilter(filter, "baseCaloriesBurn", query.Calories)
+	filter = rangeFilter(filter, "baseDurationMinutes", query.Duration)
+
+	// Burn rate is derived, so it is computed before sorting on it
+	var computed bson.D
+	if query.Sort == SortByBurnRate {
+		computed = bson.D{{Key: "burnRate", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$baseDurationMinutes", 0}}},
+			bson.D{{Key: "$divide", Value: bson.A{"$baseCaloriesBurn", "$baseDurationMinutes"}}},
+			0,
+		}}}}}
+	}
+
+	packages, total, err := findPage[models.WorkoutPackage](ctx, s.db.Collection(workoutPackagesCollection), filter, computed,
+		workoutSortFields[query.Sort], query.Descending, cursor, query.Limit)
+	if err != nil {
+		return models.WorkoutPackagePage{}, wrapMongoError("failed to fetch workout packages", err)
+	}
 
+	page := models.WorkoutPackagePage{Packages: packages, Total: total}
+	if len(packages) > query.Limit {
+		page.Packages = packages[:query.Limit]
+		last := page.Packages[query.Limit-1]
+		page.NextCursor = encodeCursor(pageCursor{Sort: query.Sort, Value: workoutSortValue(last, query.Sort), ID: last.ID})
+	}
+	return page, nil
+}
+
+// packageFilter builds the goal type, text search, archive and scope conditions shared by package lists
+func packageFilter(goalType models.GoalType, search string, includeArchived bool, scope PackageScope, viewer string) bson.D {
 	filter := bson.D{}
-	if goalType != models.GoalTypeAll {
-		filter = bson.D{{Key: "goalType", Value: goalType}}
+	if !includeArchived {
+		filter = append(filter, bson.E{Key: "archived", Value: bson.D{{Key: "$ne", Value: true}}})
+	}
+
+	// Catalog packages have no ownerId field; $in with nil matches a missing field
+	catalog := bson.E{Key: "ownerId", Value: bson.D{{Key: "$in", Value: bson.A{"", nil}}}}
+	switch scope {
+	case ScopeCatalog:
+		filter = append(filter, catalog)
+	case ScopeMine:
+		if viewer == "" {
+			// Without a viewer there is nothing of their own; ownerId "" would match the catalog
+			filter = append(filter, bson.E{Key: "ownerId", Value: bson.D{{Key: "$in", Value: bson.A{}}}})
+			break
+		}
+		filter = append(filter, bson.E{Key: "ownerId", Value: viewer})
+	default:
+		visible := bson.A{bson.D{catalog}, bson.D{{Key: "visibility", Value: models.VisibilityPublic}}}
+		if viewer != "" {
+			visible = append(visible, bson.D{{Key: "ownerId", Value: viewer}})
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: visible})
 	}
 
-	cursor, err := s.db.Collection(workoutPackagesCollection).Find(s.ctx, filter)
+	switch goalType {
+	case models.GoalTypeAll:
+	case models.GoalTypeLose, models.GoalTypeGain:
+		// Packages for BOTH goals suit either one
+		filter = append(filter, bson.E{Key: "goalType", Value: bson.D{{Key: "$in", Value: bson.A{goalType, models.GoalTypeBoth}}}})
+	default:
+		filter = append(filter, bson.E{Key: "goalType", Value: goalType})
+	}
+	if search != "" {
+		filter = append(filter, bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: search}}})
+	}
+	return filter
+}
+
+// rangeFilter adds the bounds of r on field to filter
+func rangeFilter(filter bson.D, field string, r Range) bson.D {
+	bounds := bson.D{}
+	if r.Min != nil {
+		bounds = append(bounds, bson.E{Key: "$gte", Value: *r.Min})
+	}
+	if r.Max != nil {
+		bounds = append(bounds, bson.E{Key: "$lte", Value: *r.Max})
+	}
+	if len(bounds) == 0 {
+		return filter
+	}
+	return append(filter, bson.E{Key: field, Value: bounds})
+}
+
+// findPage runs a keyset-paginated query: documents matching filter, with the
+// computed fields added, ordered by sortField and then _id, starting after cursor.
+// It returns up to limit+1 documents, so that callers can tell whether another
+// page follows, and the number of documents matching filter.
+func findPage[T any](ctx context.Context, collection *mongo.Collection, filter, computed bson.D, sortField string, descending bool, cursor *pageCursor, limit int) ([]T, int64, error) {
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// $text must be part of the first $match stage
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if len(computed) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: computed}})
+	}
+
+	direction, after := 1, "$gt"
+	if descending {
+		direction, after = -1, "$lt"
+	}
+
+	if cursor != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: sortField, Value: bson.D{{Key: after, Value: cursor.Value}}}},
+			bson.D{{Key: sortField, Value: cursor.Value}, {Key: "_id", Value: bson.D{{Key: after, Value: cursor.ID}}}},
+		}}}}})
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}}},
+		bson.D{{Key: "$limit", Value: limit + 1}},
+	)
+
+	results, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		log.Printf("Error fetching workout packages: %v", err)
-		return packages
+		return nil, 0, err
 	}
-	defer cursor.Close(s.ctx)
+	defer results.Close(ctx)
 
-	if err := cursor.All(s.ctx, &packages); err != nil {
-		log.Printf("Error decoding workout packages: %v", err)
+	items := make([]T, 0, limit+1)
+	if err := results.All(ctx, &items); err != nil {
+		return nil, 0, err
 	}
 
-	return packages
+	return items, total, nil
 }
 
 // GetWorkoutPackage returns a specific workout package by ID
-func (s *MongoStore) GetWorkoutPackage(id string) (models.WorkoutPackage, error) {
+func (s *MongoStore) GetWorkoutPackage(ctx context.Context, id string) (models.WorkoutPackage, error) {
 	var pkg models.WorkoutPackage
 
-	objID, err := primitive.ObjectIDFromHex(id)
+	err := s.db.Collection(workoutPackagesCollection).FindOne(ctx, idFilter(id)).Decode(&pkg)
 	if err != nil {
-		// If not a valid ObjectID, try finding by ID string directly
-		err = s.db.Collection(workoutPackagesCollection).FindOne(s.ctx, bson.M{"id": id}).Decode(&pkg)
-	} else {
-		// Try finding by ObjectID
-		err = s.db.Collection(workoutPackagesCollection).FindOne(s.ctx, bson.M{"_id": objID}).Decode(&pkg)
+		if err == mongo.ErrNoDocuments {
+			return models.WorkoutPackage{}, notFound("workout package", id)
+		}
+		return models.WorkoutPackage{}, wrapMongoError("failed to fetch workout package", err)
 	}
 
+	return pkg, nil
+}
+
+// GetWorkoutPackageVersion returns a workout package as it was at the given version
+func (s *MongoStore) GetWorkoutPackageVersion(ctx context.Context, id string, version int) (models.WorkoutPackage, error) {
+	pkg, err := findPackageVersion[models.WorkoutPackage](ctx, s.db.Collection(workoutPackageVersions), id, version)
+	if err == mongo.ErrNoDocuments {
+		// Packages written before versioning have no stored versions, only the current one
+		current, err := s.GetWorkoutPackage(ctx, id)
+		if err != nil || current.Version != version {
+			return models.WorkoutPackage{}, notFound("workout package version", packageVersionID(id, version))
+		}
+		return current, nil
+	}
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return models.WorkoutPackage{}, errors.New("workout package not found")
+		return models.WorkoutPackage{}, wrapMongoError("failed to fetch workout package version", err)
+	}
+
+	return pkg, nil
+}
+
+// CreateWorkoutPackage creates a workout package at version 1
+func (s *MongoStore) CreateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	if pkg.ID == "" {
+		pkg.ID = primitive.NewObjectID().Hex()
+	}
+
+	now := time.Now()
+	pkg.Version = 1
+	pkg.CreatedAt = &now
+	pkg.UpdatedAt = &now
+
+	_, err := s.db.Collection(workoutPackagesCollection).InsertOne(ctx, pkg)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.WorkoutPackage{}, fmt.Errorf("workout package %s already exists: %w", pkg.ID, ErrConflict)
 		}
+		return models.WorkoutPackage{}, wrapMongoError("failed to create workout package", err)
+	}
+
+	if err := savePackageVersion(ctx, s.db.Collection(workoutPackageVersions), pkg.ID, pkg.Version, pkg); err != nil {
+		return models.WorkoutPackage{}, wrapMongoError("failed to store workout package version", err)
+	}
+
+	return pkg, nil
+}
+
+// UpdateWorkoutPackage replaces the version of a workout package given in pkg with the next version
+func (s *MongoStore) UpdateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error) {
+	current, err := s.GetWorkoutPackage(ctx, pkg.ID)
+	if err != nil {
 		return models.WorkoutPackage{}, err
 	}
+	if current.Version != pkg.Version {
+		return models.WorkoutPackage{}, versionConflict("workout package", pkg.ID, pkg.Version)
+	}
+
+	now := time.Now()
+	pkg.Version++
+	pkg.OwnerID = current.OwnerID
+	pkg.Archived = current.Archived
+	pkg.ArchivedAt = current.ArchivedAt
+	pkg.CreatedAt = current.CreatedAt
+	pkg.UpdatedAt = &now
+
+	// Matching on the version makes concurrent updates of the same version fail
+	result, err := s.db.Collection(workoutPackagesCollection).ReplaceOne(ctx, versionFilter(pkg.ID, current.Version), pkg)
+	if err != nil {
+		return models.WorkoutPackage{}, wrapMongoError("failed to update workout package", err)
+	}
+	if result.MatchedCount == 0 {
+		return models.WorkoutPackage{}, versionConflict("workout package", pkg.ID, current.Version)
+	}
+
+	if err := savePackageVersion(ctx, s.db.Collection(workoutPackageVersions), pkg.ID, pkg.Version, pkg); err != nil {
+		return models.WorkoutPackage{}, wrapMongoError("failed to store workout package version", err)
+	}
+
+	return pkg, nil
+}
+
+// ArchiveWorkoutPackage archives or restores a workout package. The version is unchanged.
+func (s *MongoStore) ArchiveWorkoutPackage(ctx context.Context, id string, archived bool) (models.WorkoutPackage, error) {
+	var pkg models.WorkoutPackage
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := s.db.Collection(workoutPackagesCollection).FindOneAndUpdate(ctx, idFilter(id), archiveUpdate(archived), opts).Decode(&pkg)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WorkoutPackage{}, notFound("workout package", id)
+		}
+		return models.WorkoutPackage{}, wrapMongoError("failed to archive workout package", err)
+	}
 
 	return pkg, nil
 }
 
+// GetFoods returns one page of the foods matching the query, ordered by name
+func (s *MongoStore) GetFoods(ctx context.Context, query FoodQuery) (models.FoodPage, error) {
+	cursor, err := normalizeFoodQuery(&query)
+	if err != nil {
+		return models.FoodPage{}, err
+	}
+
+	filter := bson.D{}
+	if query.Search != "" {
+		filter = append(filter, bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: query.Search}}})
+	}
+
+	foods, total, err := findPage[models.Food](ctx, s.db.Collection(foodsCollection), filter, nil, "name", false, cursor, query.Limit)
+	if err != nil {
+		return models.FoodPage{}, wrapMongoError("failed to fetch foods", err)
+	}
+
+	page := models.FoodPage{Foods: foods, Total: total}
+	if len(foods) > query.Limit {
+		page.Foods = foods[:query.Limit]
+		last := page.Foods[query.Limit-1]
+		page.NextCursor = encodeCursor(pageCursor{Sort: SortByName, Value: last.Name, ID: last.ID})
+	}
+	return page, nil
+}
+
+// GetFood returns a specific food by ID
+func (s *MongoStore) GetFood(ctx context.Context, id string) (models.Food, error) {
+	var food models.Food
+
+	err := s.db.Collection(foodsCollection).FindOne(ctx, idFilter(id)).Decode(&food)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Food{}, notFound("food", id)
+		}
+		return models.Food{}, wrapMongoError("failed to fetch food", err)
+	}
+
+	return food, nil
+}
+
+// GetFoodByBarcode returns the food with a barcode. Should several foods share
+// it, the one with the lowest ID is returned.
+func (s *MongoStore) GetFoodByBarcode(ctx context.Context, barcode string) (models.Food, error) {
+	var food models.Food
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+	err := s.db.Collection(foodsCollection).FindOne(ctx, bson.D{{Key: "barcode", Value: barcode}}, opts).Decode(&food)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Food{}, notFound("food with barcode", barcode)
+		}
+		return models.Food{}, wrapMongoError("failed to fetch food by barcode", err)
+	}
+
+	return food, nil
+}
+
+// CreateFood creates a food
+func (s *MongoStore) CreateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	if food.ID == "" {
+		food.ID = primitive.NewObjectID().Hex()
+	}
+
+	now := time.Now()
+	food.CreatedAt = &now
+	food.UpdatedAt = &now
+
+	_, err := s.db.Collection(foodsCollection).InsertOne(ctx, food)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.Food{}, fmt.Errorf("food %s already exists: %w", food.ID, ErrConflict)
+		}
+		return models.Food{}, wrapMongoError("failed to create food", err)
+	}
+
+	return food, nil
+}
+
+// UpdateFood replaces a food, keeping the barcode and source of an imported one
+func (s *MongoStore) UpdateFood(ctx context.Context, food models.Food) (models.Food, error) {
+	current, err := s.GetFood(ctx, food.ID)
+	if err != nil {
+		return models.Food{}, err
+	}
+
+	now := time.Now()
+	food.Barcode = current.Barcode
+	food.Source = current.Source
+	food.SourceID = current.SourceID
+	food.CreatedAt = current.CreatedAt
+	food.UpdatedAt = &now
+
+	result, err := s.db.Collection(foodsCollection).ReplaceOne(ctx, idFilter(food.ID), food)
+	if err != nil {
+		return models.Food{}, wrapMongoError("failed to update food", err)
+	}
+	if result.MatchedCount == 0 {
+		return models.Food{}, notFound("food", food.ID)
+	}
+
+	return food, nil
+}
+
+// UpsertFoods creates the foods or merges them into the stored ones with the same ID,
+// in one unordered bulk write
+func (s *MongoStore) UpsertFoods(ctx context.Context, foods []models.Food) (FoodUpsertResult, error) {
+	if len(foods) == 0 {
+		return FoodUpsertResult{}, nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(foods))
+	for _, food := range foods {
+		// Merged as models.Food.MergeImport describes: fields the import does not have are left as stored
+		set := bson.D{
+			{Key: "name", Value: food.Name},
+			{Key: "per100g", Value: food.Per100g},
+			{Key: "updatedAt", Value: now},
+		}
+		if food.Brand != "" {
+			set = append(set, bson.E{Key: "brand", Value: food.Brand})
+		}
+		if food.Barcode != "" {
+			set = append(set, bson.E{Key: "barcode", Value: food.Barcode})
+		}
+		if food.ServingGrams > 0 {
+			set = append(set, bson.E{Key: "servingGrams", Value: food.ServingGrams})
+		}
+		if food.Source != "" {
+			set = append(set, bson.E{Key: "source", Value: food.Source}, bson.E{Key: "sourceId", Value: food.SourceID})
+		}
+
+		update := bson.D{
+			{Key: "$set", Value: set},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "createdAt", Value: now}}},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(idFilter(food.ID)).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, err := s.db.Collection(foodsCollection).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return FoodUpsertResult{}, wrapMongoError("failed to upsert foods", err)
+	}
+
+	return FoodUpsertResult{Created: result.UpsertedCount, Updated: result.MatchedCount}, nil
+}
+
 // CreateMealEntry creates a new meal entry
-func (s *MongoStore) CreateMealEntry(entry models.MealEntry) (models.MealEntry, error) {
+func (s *MongoStore) CreateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
 	// Ensure the entry has an ID
 	if entry.ID == "" {
 		entry.ID = primitive.NewObjectID().Hex()
@@ -267,43 +951,75 @@ func (s *MongoStore) CreateMealEntry(entry models.MealEntry) (models.MealEntry,
 	}
 
 	// Insert the entry
-	_, err := s.db.Collection(mealEntriesCollection).InsertOne(s.ctx, entry)
+	_, err := s.db.Collection(mealEntriesCollection).InsertOne(ctx, entry)
 	if err != nil {
-		return models.MealEntry{}, err
+		return models.MealEntry{}, wrapMongoError("failed to create meal entry", err)
 	}
 
 	return entry, nil
 }
 
 // GetMealEntriesByUserAndDateRange returns meal entries for a user within a date range
-func (s *MongoStore) GetMealEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.MealEntry {
-	var entries []models.MealEntry
+func (s *MongoStore) GetMealEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.MealEntry, error) {
+	entries := make([]models.MealEntry, 0)
 
-	// Create a date range filter
-	filter := bson.M{
-		"userID": userID,
-		"timestamp": bson.M{
-			"$gte": startDate,
-			"$lte": endDate,
-		},
+	cursor, err := s.db.Collection(mealEntriesCollection).Find(ctx, entryRangeFilter(userID, startDate, endDate), entrySortOptions())
+	if err != nil {
+		return nil, wrapMongoError("failed to fetch meal entries", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, wrapMongoError("failed to decode meal entries", err)
+	}
+
+	return entries, nil
+}
+
+// GetMealEntry retrieves a meal entry by ID
+func (s *MongoStore) GetMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	var entry models.MealEntry
+
+	err := s.db.Collection(mealEntriesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.MealEntry{}, notFound("meal entry", id)
+		}
+		return models.MealEntry{}, wrapMongoError("failed to fetch meal entry", err)
 	}
 
-	cursor, err := s.db.Collection(mealEntriesCollection).Find(s.ctx, filter)
+	return entry, nil
+}
+
+// UpdateMealEntry replaces an existing meal entry
+func (s *MongoStore) UpdateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error) {
+	result, err := s.db.Collection(mealEntriesCollection).ReplaceOne(ctx, bson.M{"_id": entry.ID}, entry)
 	if err != nil {
-		log.Printf("Error fetching meal entries: %v", err)
-		return entries
+		return models.MealEntry{}, wrapMongoError("failed to update meal entry", err)
+	}
+	if result.MatchedCount == 0 {
+		return models.MealEntry{}, notFound("meal entry", entry.ID)
 	}
-	defer cursor.Close(s.ctx)
 
-	if err := cursor.All(s.ctx, &entries); err != nil {
-		log.Printf("Error decoding meal entries: %v", err)
+	return entry, nil
+}
+
+// DeleteMealEntry removes a meal entry and returns it
+func (s *MongoStore) DeleteMealEntry(ctx context.Context, id string) (models.MealEntry, error) {
+	var entry models.MealEntry
+	err := s.db.Collection(mealEntriesCollection).FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.MealEntry{}, notFound("meal entry", id)
+		}
+		return models.MealEntry{}, wrapMongoError("failed to delete meal entry", err)
 	}
 
-	return entries
+	return entry, nil
 }
 
 // CreateWorkoutEntry creates a new workout entry
-func (s *MongoStore) CreateWorkoutEntry(entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+func (s *MongoStore) CreateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
 	// Ensure the entry has an ID
 	if entry.ID == "" {
 		entry.ID = primitive.NewObjectID().Hex()
@@ -314,62 +1030,323 @@ func (s *MongoStore) CreateWorkoutEntry(entry models.WorkoutEntry) (models.Worko
 	}
 
 	// Insert the entry
-	_, err := s.db.Collection(workoutEntriesCollection).InsertOne(s.ctx, entry)
+	_, err := s.db.Collection(workoutEntriesCollection).InsertOne(ctx, entry)
 	if err != nil {
-		return models.WorkoutEntry{}, err
+		return models.WorkoutEntry{}, wrapMongoError("failed to create workout entry", err)
 	}
 
 	return entry, nil
 }
 
 // GetWorkoutEntriesByUserAndDateRange returns workout entries for a user within a date range
-func (s *MongoStore) GetWorkoutEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.WorkoutEntry {
-	var entries []models.WorkoutEntry
+func (s *MongoStore) GetWorkoutEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WorkoutEntry, error) {
+	entries := make([]models.WorkoutEntry, 0)
+
+	cursor, err := s.db.Collection(workoutEntriesCollection).Find(ctx, entryRangeFilter(userID, startDate, endDate), entrySortOptions())
+	if err != nil {
+		return nil, wrapMongoError("failed to fetch workout entries", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, wrapMongoError("failed to decode workout entries", err)
+	}
+
+	return entries, nil
+}
+
+// GetWorkoutEntry retrieves a workout entry by ID
+func (s *MongoStore) GetWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	var entry models.WorkoutEntry
+
+	err := s.db.Collection(workoutEntriesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WorkoutEntry{}, notFound("workout entry", id)
+		}
+		return models.WorkoutEntry{}, wrapMongoError("failed to fetch workout entry", err)
+	}
+
+	return entry, nil
+}
+
+// UpdateWorkoutEntry replaces an existing workout entry
+func (s *MongoStore) UpdateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error) {
+	result, err := s.db.Collection(workoutEntriesCollection).ReplaceOne(ctx, bson.M{"_id": entry.ID}, entry)
+	if err != nil {
+		return models.WorkoutEntry{}, wrapMongoError("failed to update workout entry", err)
+	}
+	if result.MatchedCount == 0 {
+		return models.WorkoutEntry{}, notFound("workout entry", entry.ID)
+	}
+
+	return entry, nil
+}
+
+// DeleteWorkoutEntry removes a workout entry and returns it
+func (s *MongoStore) DeleteWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error) {
+	var entry models.WorkoutEntry
+	err := s.db.Collection(workoutEntriesCollection).FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WorkoutEntry{}, notFound("workout entry", id)
+		}
+		return models.WorkoutEntry{}, wrapMongoError("failed to delete workout entry", err)
+	}
+
+	return entry, nil
+}
+
+// CreateWeightEntry creates a new weight entry
+func (s *MongoStore) CreateWeightEntry(ctx context.Context, entry models.WeightEntry) (models.WeightEntry, error) {
+	// Ensure the entry has an ID
+	if entry.ID == "" {
+		entry.ID = primitive.NewObjectID().Hex()
+	}
+	// Ensure the timestamp is set
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	// Insert the entry
+	_, err := s.db.Collection(weightEntriesCollection).InsertOne(ctx, entry)
+	if err != nil {
+		return models.WeightEntry{}, wrapMongoError("failed to create weight entry", err)
+	}
+
+	return entry, nil
+}
+
+// GetWeightEntriesByUserAndDateRange returns weight entries for a user within a date range
+func (s *MongoStore) GetWeightEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WeightEntry, error) {
+	entries := make([]models.WeightEntry, 0)
+
+	cursor, err := s.db.Collection(weightEntriesCollection).Find(ctx, entryRangeFilter(userID, startDate, endDate), entrySortOptions())
+	if err != nil {
+		return nil, wrapMongoError("failed to fetch weight entries", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, wrapMongoError("failed to decode weight entries", err)
+	}
+
+	return entries, nil
+}
+
+// GetLatestWeightEntry returns the user's most recently dated weight entry
+func (s *MongoStore) GetLatestWeightEntry(ctx context.Context, userID string) (models.WeightEntry, error) {
+	var entry models.WeightEntry
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "timestamp", Value: -1}})
+	err := s.db.Collection(weightEntriesCollection).FindOne(ctx, bson.M{"userId": userID}, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WeightEntry{}, fmt.Errorf("weight entries for user %s: %w", userID, ErrNotFound)
+		}
+		return models.WeightEntry{}, wrapMongoError("failed to fetch latest weight entry", err)
+	}
+
+	return entry, nil
+}
+
+// GetWeightEntry retrieves a weight entry by ID
+func (s *MongoStore) GetWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	var entry models.WeightEntry
+
+	err := s.db.Collection(weightEntriesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WeightEntry{}, notFound("weight entry", id)
+		}
+		return models.WeightEntry{}, wrapMongoError("failed to fetch weight entry", err)
+	}
+
+	return entry, nil
+}
+
+// DeleteWeightEntry removes a weight entry and returns it
+func (s *MongoStore) DeleteWeightEntry(ctx context.Context, id string) (models.WeightEntry, error) {
+	var entry models.WeightEntry
+	err := s.db.Collection(weightEntriesCollection).FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.WeightEntry{}, notFound("weight entry", id)
+		}
+		return models.WeightEntry{}, wrapMongoError("failed to delete weight entry", err)
+	}
+
+	return entry, nil
+}
+
+// CreateUserInfo stores a health metrics snapshot
+func (s *MongoStore) CreateUserInfo(ctx context.Context, info models.UserInfo) (models.UserInfo, error) {
+	if info.ID == "" {
+		info.ID = primitive.NewObjectID().Hex()
+	}
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.Collection(userInfoCollection).InsertOne(ctx, info)
+	if err != nil {
+		return models.UserInfo{}, wrapMongoError("failed to create user info", err)
+	}
+
+	return info, nil
+}
+
+// GetUserInfoHistory returns a user's health metrics snapshots taken within a time range, oldest first
+func (s *MongoStore) GetUserInfoHistory(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.UserInfo, error) {
+	history := make([]models.UserInfo, 0)
 
-	// Create a date range filter
 	filter := bson.M{
-		"userID": userID,
-		"timestamp": bson.M{
+		"userId": userID,
+		"createdAt": bson.M{
 			"$gte": startDate,
 			"$lte": endDate,
 		},
 	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
 
-	cursor, err := s.db.Collection(workoutEntriesCollection).Find(s.ctx, filter)
+	cursor, err := s.db.Collection(userInfoCollection).Find(ctx, filter, opts)
 	if err != nil {
-		log.Printf("Error fetching workout entries: %v", err)
-		return entries
+		return nil, wrapMongoError("failed to fetch user info", err)
 	}
-	defer cursor.Close(s.ctx)
+	defer cursor.Close(ctx)
 
-	if err := cursor.All(s.ctx, &entries); err != nil {
-		log.Printf("Error decoding workout entries: %v", err)
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, wrapMongoError("failed to decode user info", err)
 	}
 
-	return entries
+	return history, nil
 }
 
-// DeleteUser deletes a user by ID and returns the deleted user
-func (s *MongoStore) DeleteUser(id string) (models.User, error) {
-	// Find the user first to return it
-	userCollection := s.db.Collection(usersCollection)
-	filter := bson.M{"id": id}
+// GetDailyTotals returns per-day entry totals for a user within a date range.
+// Meal and workout entries are combined and grouped by day in a single
+// aggregation; only days with at least one entry are returned, ordered by date.
+func (s *MongoStore) GetDailyTotals(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyTotals, error) {
+	totals := make([]models.DailyTotals, 0)
+	filter := entryRangeFilter(userID, startDate, endDate)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$project", Value: bson.M{
+			"date":         1,
+			"calories":     1,
+			"protein":      1,
+			"carbs":        1,
+			"fat":          1,
+			"burned":       bson.M{"$literal": 0},
+			"mealCount":    bson.M{"$literal": 1},
+			"workoutCount": bson.M{"$literal": 0},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": workoutEntriesCollection,
+			"pipeline": bson.A{
+				bson.M{"$match": filter},
+				bson.M{"$project": bson.M{
+					"date":         1,
+					"calories":     bson.M{"$literal": 0},
+					"protein":      bson.M{"$literal": 0},
+					"carbs":        bson.M{"$literal": 0},
+					"fat":          bson.M{"$literal": 0},
+					"burned":       "$caloriesBurned",
+					"mealCount":    bson.M{"$literal": 0},
+					"workoutCount": bson.M{"$literal": 1},
+				}},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$date"}},
+			"calories":     bson.M{"$sum": "$calories"},
+			"protein":      bson.M{"$sum": "$protein"},
+			"carbs":        bson.M{"$sum": "$carbs"},
+			"fat":          bson.M{"$sum": "$fat"},
+			"burned":       bson.M{"$sum": "$burned"},
+			"mealCount":    bson.M{"$sum": "$mealCount"},
+			"workoutCount": bson.M{"$sum": "$workoutCount"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
 
-	// First find the user to return it
-	var user models.User
-	err := userCollection.FindOne(s.ctx, filter).Decode(&user)
+	cursor, err := s.db.Collection(mealEntriesCollection).Aggregate(ctx, pipeline)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return models.User{}, fmt.Errorf("user not found: %s", id)
-		}
-		return models.User{}, err
+		return nil, wrapMongoError("failed to aggregate daily totals", err)
 	}
+	defer cursor.Close(ctx)
 
-	// Then delete it
-	_, err = userCollection.DeleteOne(s.ctx, filter)
-	if err != nil {
-		return models.User{}, fmt.Errorf("failed to delete user: %w", err)
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, wrapMongoError("failed to decode daily totals", err)
 	}
 
-	return user, nil
+	return totals, nil
+}
+
+// packageVersionDoc stores one version of a package
+type packageVersionDoc[T any] struct {
+	ID        string `bson:"_id"` // packageVersionID
+	PackageID string `bson:"packageId"`
+	Version   int    `bson:"version"`
+	Package   T      `bson:"package"`
+}
+
+// findPackageVersion reads a stored package version. It returns mongo.ErrNoDocuments
+// unwrapped when the version is not stored.
+func findPackageVersion[T any](ctx context.Context, collection *mongo.Collection, id string, version int) (T, error) {
+	var doc packageVersionDoc[T]
+	err := collection.FindOne(ctx, bson.M{"_id": packageVersionID(id, version)}).Decode(&doc)
+	return doc.Package, err
+}
+
+// savePackageVersion stores a package version, replacing an earlier copy of the same version
+func savePackageVersion[T any](ctx context.Context, collection *mongo.Collection, id string, version int, pkg T) error {
+	doc := packageVersionDoc[T]{ID: packageVersionID(id, version), PackageID: id, Version: version, Package: pkg}
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+// versionFilter matches a package by ID at the given version. Version 0 also
+// matches packages written before versioning, which have no version field.
+func versionFilter(id string, version int) bson.M {
+	filter := idFilter(id)
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
+	}
+	return filter
+}
+
+// archiveUpdate sets or clears the archived flag and time of a package
+func archiveUpdate(archived bool) bson.M {
+	if archived {
+		return bson.M{"$set": bson.M{"archived": true, "archivedAt": time.Now()}}
+	}
+	return bson.M{"$unset": bson.M{"archived": "", "archivedAt": ""}}
+}
+
+// idFilter matches a document by its string ID, or by ObjectID for
+// documents that were inserted directly into MongoDB
+func idFilter(id string) bson.M {
+	if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"_id": bson.M{"$in": bson.A{id, objID}}}
+	}
+	return bson.M{"_id": id}
+}
+
+// entryRangeFilter matches a user's entries dated within [startDate, endDate]
+func entryRangeFilter(userID string, startDate, endDate time.Time) bson.M {
+	return bson.M{
+		"userId": userID,
+		"date": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}
+}
+
+// entrySortOptions orders entries by date, then by the time they were logged
+func entrySortOptions() *options.FindOptions {
+	return options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "timestamp", Value: 1}})
 }
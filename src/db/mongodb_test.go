@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ownerCondition returns the ownerId condition of a package filter
+func ownerCondition(t *testing.T, filter bson.D) interface{} {
+	t.Helper()
+	for _, e := range filter {
+		if e.Key == "ownerId" {
+			return e.Value
+		}
+	}
+	t.Fatalf("filter %v has no ownerId condition", filter)
+	return nil
+}
+
+func TestPackageFilterMine(t *testing.T) {
+	if got := ownerCondition(t, packageFilter("", "", false, ScopeMine, "u1")); got != "u1" {
+		t.Errorf("ownerId condition = %v, want u1", got)
+	}
+
+	// Like matchesScope, no viewer owns nothing: the condition must not match the catalog's empty owner
+	got, ok := ownerCondition(t, packageFilter("", "", false, ScopeMine, "")).(bson.D)
+	if !ok || len(got) != 1 || got[0].Key != "$in" || len(got[0].Value.(bson.A)) != 0 {
+		t.Errorf("ownerId condition without a viewer = %v, want {$in: []}", got)
+	}
+}
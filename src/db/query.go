@@ -0,0 +1,419 @@
+package db
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// Package list page sizes
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PackageSort names the order of a package list
+type PackageSort string
+
+// Package sort keys. Every order breaks ties by package ID so that cursors are stable.
+const (
+	SortByName           PackageSort = "name"
+	SortByCalories       PackageSort = "calories"       // Meal calories or workout calories burned
+	SortByProtein        PackageSort = "protein"        // Meals only
+	SortByProteinDensity PackageSort = "proteinDensity" // Meals only: grams of protein per 100 kcal
+	SortByDuration       PackageSort = "duration"       // Workouts only
+	SortByBurnRate       PackageSort = "burnRate"       // Workouts only: calories burned per minute
+)
+
+// PackageScope selects whose packages a list includes
+type PackageScope string
+
+// Package list scopes
+const (
+	ScopeAll     PackageScope = "all"     // The catalog, the viewer's own packages and other users' public packages
+	ScopeCatalog PackageScope = "catalog" // Only catalog packages, which have no owner
+	ScopeMine    PackageScope = "mine"    // Only the viewer's own packages
+)
+
+// Range bounds a numeric field. Nil bounds are open.
+type Range struct {
+	Min *int
+	Max *int
+}
+
+// contains reports whether value lies within the range, bounds included
+func (r Range) contains(value int) bool {
+	return (r.Min == nil || value >= *r.Min) && (r.Max == nil || value <= *r.Max)
+}
+
+// MealPackageQuery filters, orders and pages a meal package list.
+// The zero value lists the first page of all catalog and public packages by name.
+type MealPackageQuery struct {
+	GoalType        models.GoalType // LOSE and GAIN also match BOTH; GoalTypeAll matches every goal
+	MealType        models.MealType // Empty matches every meal type
+	Search          string          // Words matched against name, description and ingredients
+	Calories        Range
+	Protein         Range
+	Carbs           Range
+	Fat             Range
+	Viewer          string       // User the list is for; their private and shared packages are included
+	Scope           PackageScope // Empty means ScopeAll
+	IncludeArchived bool
+	Sort            PackageSort
+	Descending      bool
+	Cursor          string // NextCursor of the previous page
+	Limit           int
+}
+
+// WorkoutPackageQuery filters, orders and pages a workout package list.
+// The zero value lists the first page of all catalog and public packages by name.
+type WorkoutPackageQuery struct {
+	GoalType        models.GoalType // LOSE and GAIN also match BOTH; GoalTypeAll matches every goal
+	WorkoutType     string          // Empty matches every workout type
+	Search          string          // Words matched against name, description and instructions
+	Calories        Range           // Calories burned
+	Duration        Range           // Minutes
+	Viewer          string          // User the list is for; their private and shared packages are included
+	Scope           PackageScope    // Empty means ScopeAll
+	IncludeArchived bool
+	Sort            PackageSort
+	Descending      bool
+	Cursor          string // NextCursor of the previous page
+	Limit           int
+}
+
+// FoodQuery searches and pages the food list, which is ordered by name.
+// The zero value lists the first page of all foods.
+type FoodQuery struct {
+	Search string // Words matched against the name
+	Cursor string // NextCursor of the previous page
+	Limit  int
+}
+
+// FoodUpsertResult counts the foods of a bulk upsert. A food whose ID is new is
+// created; one whose ID exists is merged as models.Food.MergeImport describes.
+type FoodUpsertResult struct {
+	Created int64
+	Updated int64
+}
+
+// cacheKey identifies the query for caching. It is a hash so that search
+// text does not end up in Redis keys. The viewer is part of the query, so
+// each user's list is cached separately.
+func (q MealPackageQuery) cacheKey() string {
+	return hashQuery(q)
+}
+
+// cacheKey identifies the query for caching
+func (q WorkoutPackageQuery) cacheKey() string {
+	return hashQuery(q)
+}
+
+// hashQuery hashes the JSON encoding of a query
+func hashQuery(query any) string {
+	data, _ := json.Marshal(query)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// pageLimit returns the page size for a requested limit
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
+
+// pageCursor marks the last package of a page: its sort value and ID.
+// The next page starts after it in the same order.
+type pageCursor struct {
+	Sort  PackageSort `json:"s"`
+	Value any         `json:"v"` // string for SortByName, float64 otherwise
+	ID    string      `json:"id"`
+}
+
+// encodeCursor makes an opaque cursor string
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor string produced by encodeCursor for the same sort key.
+// An empty string decodes to nil, meaning the first page.
+func decodeCursor(value string, sortKey PackageSort) (*pageCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	invalid := NewValidationError("cursor", "Invalid cursor; request the first page again")
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.Sort != sortKey {
+		return nil, invalid
+	}
+
+	// The value type must match the sort key, or comparisons would silently misbehave
+	switch cursor.Value.(type) {
+	case string:
+		if sortKey != SortByName {
+			return nil, invalid
+		}
+	case float64:
+		if sortKey == SortByName {
+			return nil, invalid
+		}
+	default:
+		return nil, invalid
+	}
+
+	return &cursor, nil
+}
+
+// checkSort rejects sort keys that do not apply to a kind of package
+func checkSort(sortKey PackageSort, allowed ...PackageSort) error {
+	for _, key := range allowed {
+		if sortKey == key {
+			return nil
+		}
+	}
+
+	names := make([]string, 0, len(allowed))
+	for _, key := range allowed {
+		names = append(names, string(key))
+	}
+	return NewValidationError("sort", fmt.Sprintf("Must be one of %s", strings.Join(names, ", ")))
+}
+
+// matchesGoal reports whether a package goal type matches a query goal type.
+// Querying LOSE or GAIN includes packages for BOTH; querying BOTH matches only those.
+func matchesGoal(packageGoal, queryGoal models.GoalType) bool {
+	switch queryGoal {
+	case models.GoalTypeAll:
+		return true
+	case models.GoalTypeBoth:
+		return packageGoal == models.GoalTypeBoth
+	default:
+		return packageGoal.Suits(queryGoal)
+	}
+}
+
+// matchesScope reports whether a package with the given owner and visibility
+// belongs in a list of the given scope for the viewer
+func matchesScope(ownerID string, visibility models.PackageVisibility, scope PackageScope, viewer string) bool {
+	switch scope {
+	case ScopeCatalog:
+		return ownerID == ""
+	case ScopeMine:
+		return ownerID != "" && ownerID == viewer
+	default:
+		return ownerID == "" || ownerID == viewer || visibility == models.VisibilityPublic
+	}
+}
+
+// checkScope rejects unknown list scopes
+func checkScope(scope PackageScope) error {
+	switch scope {
+	case "", ScopeAll, ScopeCatalog, ScopeMine:
+		return nil
+	}
+	return NewValidationError("scope", "Must be one of all, catalog, mine")
+}
+
+// searchTerms splits search text into lowercase words
+func searchTerms(search string) []string {
+	return strings.Fields(strings.ToLower(search))
+}
+
+// matchesSearch reports whether any search term occurs in any of the texts.
+// It approximates MongoDB text search, which matches any of the words.
+func matchesSearch(terms []string, texts ...string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortable is an item of a package list with its sort value
+type sortable[T any] struct {
+	item  T
+	id    string
+	value any // string or float64, as in pageCursor
+}
+
+// lessValue orders two sort values of the same kind
+func lessValue(a, b any) bool {
+	if as, ok := a.(string); ok {
+		return as < b.(string)
+	}
+	return a.(float64) < b.(float64)
+}
+
+// paginate orders items, skips those up to and including the cursor and returns
+// one page with the cursor for the next page, which is empty on the last page.
+// It mirrors the keyset pagination done by MongoStore.
+func paginate[T any](items []sortable[T], sortKey PackageSort, descending bool, cursor *pageCursor, limit int) ([]T, string) {
+	before := func(a, b sortable[T]) bool {
+		if a.value != b.value {
+			if descending {
+				return lessValue(b.value, a.value)
+			}
+			return lessValue(a.value, b.value)
+		}
+		if descending {
+			return a.id > b.id
+		}
+		return a.id < b.id
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return before(items[i], items[j])
+	})
+
+	start := 0
+	if cursor != nil {
+		last := sortable[T]{id: cursor.ID, value: cursor.Value}
+		start = sort.Search(len(items), func(i int) bool {
+			return before(last, items[i])
+		})
+	}
+
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := make([]T, 0, end-start)
+	for _, entry := range items[start:end] {
+		page = append(page, entry.item)
+	}
+
+	next := ""
+	if end < len(items) && end > start {
+		last := items[end-1]
+		next = encodeCursor(pageCursor{Sort: sortKey, Value: last.value, ID: last.id})
+	}
+	return page, next
+}
+
+// proteinDensity returns grams of protein per 100 kcal
+func proteinDensity(pkg models.MealPackage) float64 {
+	if pkg.BaseCalories <= 0 {
+		return 0
+	}
+	return float64(pkg.BaseProtein) * 100 / float64(pkg.BaseCalories)
+}
+
+// burnRate returns calories burned per minute
+func burnRate(pkg models.WorkoutPackage) float64 {
+	if pkg.BaseDurationMinutes <= 0 {
+		return 0
+	}
+	return float64(pkg.BaseCaloriesBurn) / float64(pkg.BaseDurationMinutes)
+}
+
+// mealSortValue returns the value a meal package is ordered by
+func mealSortValue(pkg models.MealPackage, sortKey PackageSort) any {
+	switch sortKey {
+	case SortByCalories:
+		return float64(pkg.BaseCalories)
+	case SortByProtein:
+		return float64(pkg.BaseProtein)
+	case SortByProteinDensity:
+		return proteinDensity(pkg)
+	default:
+		return pkg.Name
+	}
+}
+
+// workoutSortValue returns the value a workout package is ordered by
+func workoutSortValue(pkg models.WorkoutPackage, sortKey PackageSort) any {
+	switch sortKey {
+	case SortByCalories:
+		return float64(pkg.BaseCaloriesBurn)
+	case SortByDuration:
+		return float64(pkg.BaseDurationMinutes)
+	case SortByBurnRate:
+		return burnRate(pkg)
+	default:
+		return pkg.Name
+	}
+}
+
+// normalizeMealQuery applies defaults and validates the sort key, scope and cursor
+func normalizeMealQuery(query *MealPackageQuery) (*pageCursor, error) {
+	if query.Sort == "" {
+		query.Sort = SortByName
+	}
+	if err := checkSort(query.Sort, SortByName, SortByCalories, SortByProtein, SortByProteinDensity); err != nil {
+		return nil, err
+	}
+	if err := checkScope(query.Scope); err != nil {
+		return nil, err
+	}
+	query.Limit = pageLimit(query.Limit)
+	return decodeCursor(query.Cursor, query.Sort)
+}
+
+// normalizeWorkoutQuery applies defaults and validates the sort key, scope and cursor
+func normalizeWorkoutQuery(query *WorkoutPackageQuery) (*pageCursor, error) {
+	if query.Sort == "" {
+		query.Sort = SortByName
+	}
+	if err := checkSort(query.Sort, SortByName, SortByCalories, SortByDuration, SortByBurnRate); err != nil {
+		return nil, err
+	}
+	if err := checkScope(query.Scope); err != nil {
+		return nil, err
+	}
+	query.Limit = pageLimit(query.Limit)
+	return decodeCursor(query.Cursor, query.Sort)
+}
+
+// normalizeFoodQuery applies the default page size and validates the cursor
+func normalizeFoodQuery(query *FoodQuery) (*pageCursor, error) {
+	query.Limit = pageLimit(query.Limit)
+	return decodeCursor(query.Cursor, SortByName)
+}
+
+// usesAnyFood reports whether a recipe contains any of the foods
+func usesAnyFood(recipe []models.RecipeItem, foodIDs []string) bool {
+	for _, item := range recipe {
+		if slices.Contains(foodIDs, item.FoodID) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageVersionID is the ID of the stored copy of one version of a package
+func packageVersionID(id string, version int) string {
+	return fmt.Sprintf("%s@%d", id, version)
+}
+
+// versionConflict builds the error returned when a package update is based on a stale version
+func versionConflict(kind, id string, version int) error {
+	return fmt.Errorf("%s %s: version %d is not the current version: %w", kind, id, version, ErrConflict)
+}
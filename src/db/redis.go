@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zhenyili/BalanceLife/src/config"
+)
+
+// Redis connection timeouts. Cache operations are kept short so that a slow
+// or unavailable Redis degrades to direct store access instead of stalling requests.
+const (
+	redisDialTimeout = 5 * time.Second
+	redisOpTimeout   = 250 * time.Millisecond
+)
+
+// ErrRedisNotConfigured is returned when neither a Redis URI nor address is configured
+var ErrRedisNotConfigured = errors.New("redis is not configured")
+
+// NewRedisClient creates a Redis client from the configuration and verifies the connection
+func NewRedisClient(cfg *config.AppConfig) (*redis.Client, error) {
+	var opts *redis.Options
+
+	switch {
+	case cfg.Redis.URI != "":
+		parsed, err := redis.ParseURL(cfg.Redis.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis URI: %v", err)
+		}
+		opts = parsed
+	case cfg.Redis.Addr != "":
+		opts = &redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		}
+	default:
+		return nil, ErrRedisNotConfigured
+	}
+
+	opts.DialTimeout = redisDialTimeout
+	opts.ReadTimeout = redisOpTimeout
+	opts.WriteTimeout = redisOpTimeout
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisDialTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping Redis: %v", err)
+	}
+
+	return client, nil
+}
+
+// Cache key builders
+
+func userCacheKey(id string) string {
+	return "user:" + id
+}
+
+func mealPackageCacheKey(id string) string {
+	return "meal_package:" + id
+}
+
+// mealPackageVersionCacheKey caches one stored version of a package; versions never change
+func mealPackageVersionCacheKey(id string, version int) string {
+	return "meal_package:" + packageVersionID(id, version)
+}
+
+// mealPackagesVersionKey holds a counter that is part of every cached meal
+// package list key; incrementing it invalidates all cached pages at once
+func mealPackagesVersionKey() string {
+	return "meal_packages_version"
+}
+
+// mealPackagesCacheKey caches one page of a package list under a hash of its query
+func mealPackagesCacheKey(version int64, queryHash string) string {
+	return fmt.Sprintf("meal_packages:%d:%s", version, queryHash)
+}
+
+func workoutPackageCacheKey(id string) string {
+	return "workout_package:" + id
+}
+
+func workoutPackageVersionCacheKey(id string, version int) string {
+	return "workout_package:" + packageVersionID(id, version)
+}
+
+// workoutPackagesVersionKey is the workout counterpart of mealPackagesVersionKey
+func workoutPackagesVersionKey() string {
+	return "workout_packages_version"
+}
+
+func workoutPackagesCacheKey(version int64, queryHash string) string {
+	return fmt.Sprintf("workout_packages:%d:%s", version, queryHash)
+}
+
+func foodCacheKey(id string) string {
+	return "food:" + id
+}
+
+func foodBarcodeCacheKey(barcode string) string {
+	return "food_barcode:" + barcode
+}
+
+// mealEntriesVersionKey holds a per-user counter that is part of every cached
+// meal entry range key; incrementing it invalidates all ranges for the user at once
+func mealEntriesVersionKey(userID string) string {
+	return "meal_entries_version:" + userID
+}
+
+func mealEntriesCacheKey(userID string, version int64, startDate, endDate time.Time) string {
+	return fmt.Sprintf("meal_entries:%s:%d:%d:%d", userID, version, startDate.Unix(), endDate.Unix())
+}
+
+// workoutEntriesVersionKey is the workout counterpart of mealEntriesVersionKey
+func workoutEntriesVersionKey(userID string) string {
+	return "workout_entries_version:" + userID
+}
+
+func workoutEntriesCacheKey(userID string, version int64, startDate, endDate time.Time) string {
+	return fmt.Sprintf("workout_entries:%s:%d:%d:%d", userID, version, startDate.Unix(), endDate.Unix())
+}
+
+// weightEntriesVersionKey is the weight counterpart of mealEntriesVersionKey
+func weightEntriesVersionKey(userID string) string {
+	return "weight_entries_version:" + userID
+}
+
+func weightEntriesCacheKey(userID string, version int64, startDate, endDate time.Time) string {
+	return fmt.Sprintf("weight_entries:%s:%d:%d:%d", userID, version, startDate.Unix(), endDate.Unix())
+}
+
+// dailyTotalsCacheKey depends on both entry versions, so logging either a meal
+// or a workout invalidates cached totals
+func dailyTotalsCacheKey(userID string, mealVersion, workoutVersion int64, startDate, endDate time.Time) string {
+	return fmt.Sprintf("daily_totals:%s:%d:%d:%d:%d", userID, mealVersion, workoutVersion, startDate.Unix(), endDate.Unix())
+}
@@ -0,0 +1,146 @@
+package db
+
+import "github.com/zhenyili/BalanceLife/src/models"
+
+// The memory store starts with these packages. fixtures/base holds the same
+// catalog for seeding MongoDB; keep the two in step.
+
+// sampleMealPackages returns the sample meal packages from the PRD appendix (§9.1)
+// and a snack shared by both goals
+func sampleMealPackages() []models.MealPackage {
+	return []models.MealPackage{
+		{
+			ID:           "meal1",
+			Name:         "Protein Breakfast Bowl",
+			Description:  "Greek yogurt topped with berries and low-sugar granola",
+			GoalType:     models.GoalTypeLose,
+			MealType:     models.MealTypeBreakfast,
+			BaseCalories: 300,
+			BaseProtein:  25,
+			BaseCarbs:    30,
+			BaseFat:      7,
+			Ingredients:  []string{"Greek yogurt", "Berries", "Low-sugar granola"},
+		},
+		{
+			ID:           "meal2",
+			Name:         "Lean Chicken Lunch",
+			Description:  "Grilled chicken breast with mixed veggies and quinoa",
+			GoalType:     models.GoalTypeLose,
+			MealType:     models.MealTypeLunch,
+			BaseCalories: 400,
+			BaseProtein:  35,
+			BaseCarbs:    40,
+			BaseFat:      8,
+			Ingredients:  []string{"Grilled chicken breast", "Mixed veggies", "Quinoa"},
+		},
+		{
+			ID:           "meal3",
+			Name:         "Salmon Dinner",
+			Description:  "Baked salmon fillet with steamed broccoli and sweet potato",
+			GoalType:     models.GoalTypeLose,
+			MealType:     models.MealTypeDinner,
+			BaseCalories: 450,
+			BaseProtein:  30,
+			BaseCarbs:    35,
+			BaseFat:      15,
+			Ingredients:  []string{"Baked salmon fillet", "Steamed broccoli", "Sweet potato"},
+		},
+		{
+			ID:           "meal4",
+			Name:         "Power Breakfast",
+			Description:  "Egg whites and oatmeal with a banana and a protein shake",
+			GoalType:     models.GoalTypeGain,
+			MealType:     models.MealTypeBreakfast,
+			BaseCalories: 600,
+			BaseProtein:  40,
+			BaseCarbs:    70,
+			BaseFat:      10,
+			Ingredients:  []string{"Egg whites", "Oatmeal", "Banana", "Protein shake"},
+		},
+		{
+			ID:           "meal5",
+			Name:         "Muscle Lunch",
+			Description:  "Lean beef with brown rice and mixed vegetables",
+			GoalType:     models.GoalTypeGain,
+			MealType:     models.MealTypeLunch,
+			BaseCalories: 700,
+			BaseProtein:  45,
+			BaseCarbs:    80,
+			BaseFat:      15,
+			Ingredients:  []string{"Lean beef", "Brown rice", "Mixed vegetables"},
+		},
+		{
+			ID:           "meal6",
+			Name:         "Recovery Dinner",
+			Description:  "Chicken thighs with whole grain pasta and leafy greens",
+			GoalType:     models.GoalTypeGain,
+			MealType:     models.MealTypeDinner,
+			BaseCalories: 650,
+			BaseProtein:  40,
+			BaseCarbs:    65,
+			BaseFat:      18,
+			Ingredients:  []string{"Chicken thighs", "Whole grain pasta", "Leafy greens"},
+		},
+		{
+			ID:           "meal7",
+			Name:         "Balanced Snack Plate",
+			Description:  "Apple slices with peanut butter and a boiled egg",
+			GoalType:     models.GoalTypeBoth,
+			MealType:     models.MealTypeSnack,
+			BaseCalories: 250,
+			BaseProtein:  11,
+			BaseCarbs:    22,
+			BaseFat:      14,
+			Ingredients:  []string{"Apple", "Peanut butter", "Boiled egg"},
+		},
+	}
+}
+
+// sampleWorkoutPackages returns the sample workout packages from the PRD appendix (§9.2)
+// and a strength workout shared by both goals
+func sampleWorkoutPackages() []models.WorkoutPackage {
+	return []models.WorkoutPackage{
+		{
+			ID:                  "workout1",
+			Name:                "Fat-Burning HIIT",
+			Description:         "High-intensity interval training",
+			GoalType:            models.GoalTypeLose,
+			WorkoutType:         "HIIT",
+			BaseDurationMinutes: 30,
+			BaseCaloriesBurn:    350,
+			CaloriesBurnFormula: models.DefaultCaloriesBurnFormula,
+			Instructions: []string{
+				"8 rounds of 20 seconds max effort, 10 seconds rest",
+				"Intensity levels: Beginner, Intermediate, Advanced",
+			},
+		},
+		{
+			ID:                  "workout2",
+			Name:                "Cardio Blast",
+			Description:         "Steady-state cardio with interval bursts",
+			GoalType:            models.GoalTypeLose,
+			WorkoutType:         "CARDIO",
+			BaseDurationMinutes: 45,
+			BaseCaloriesBurn:    450,
+			CaloriesBurnFormula: models.DefaultCaloriesBurnFormula,
+			Instructions: []string{
+				"Walking/jogging alternating with sprints",
+				"Suitable for all fitness levels",
+			},
+		},
+		{
+			ID:                  "workout3",
+			Name:                "Full-Body Strength",
+			Description:         "Compound lifts for strength and muscle retention",
+			GoalType:            models.GoalTypeBoth,
+			WorkoutType:         "STRENGTH",
+			BaseDurationMinutes: 45,
+			BaseCaloriesBurn:    300,
+			CaloriesBurnFormula: models.DefaultCaloriesBurnFormula,
+			Instructions: []string{
+				"Squats, push-ups, rows and deadlifts, 3 sets of 8-12 reps each",
+				"Rest 60-90 seconds between sets",
+			},
+		},
+	}
+}
@@ -1,31 +1,88 @@
 package db
 
 import (
+	"context"
 	"time"
 
 	"github.com/zhenyili/BalanceLife/src/models"
 )
 
-// Store defines the interface for data storage
+// Store defines the interface for data storage.
+// Every method takes the caller's context so that request cancellation and
+// deadlines reach the underlying driver, and reports failures as errors.
+//
+// Packages are versioned: Create stores version 1, and Update replaces the
+// version given in the package with the next one, failing with ErrConflict when
+// the given version is no longer current. Every version stays readable through
+// Get*PackageVersion so that entries can be recomputed from the values they were
+// logged with. Packages are archived rather than deleted.
+//
+// UpdateUser keeps the stored password, token version and last login time, so a
+// stale copy of the user cannot undo a login or password change. Only RecordLogin
+// and ChangePassword write them; ChangePassword also increments the token version,
+// which revokes every token issued before.
 type Store interface {
 	// User operations
-	GetUsers() []models.User
-	GetUser(id string) (models.User, error)
-	CreateUser(user models.User) (models.User, error)
-	DeleteUser(id string) (models.User, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	GetUser(ctx context.Context, id string) (models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	UpdateUser(ctx context.Context, user models.User) (models.User, error)
+	RecordLogin(ctx context.Context, id string, at time.Time) (models.User, error)
+	ChangePassword(ctx context.Context, id, hash string) (models.User, error)
+	UpdateAdaptiveTDEE(ctx context.Context, id string, estimate models.TDEEEstimate, targets *models.GoalInfo) (models.User, error) // Writes only the estimate and, unless targets is nil or the stored goal is MANUAL, the goal targets
+	DeleteUser(ctx context.Context, id string) (models.User, error)
 
 	// MealPackage operations
-	GetMealPackages(goalType models.GoalType) []models.MealPackage
-	GetMealPackage(id string) (models.MealPackage, error)
+	GetMealPackages(ctx context.Context, query MealPackageQuery) (models.MealPackagePage, error)
+	GetMealPackage(ctx context.Context, id string) (models.MealPackage, error)
+	GetMealPackageVersion(ctx context.Context, id string, version int) (models.MealPackage, error)
+	CreateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error)
+	UpdateMealPackage(ctx context.Context, pkg models.MealPackage) (models.MealPackage, error)
+	ArchiveMealPackage(ctx context.Context, id string, archived bool) (models.MealPackage, error)
+	GetMealPackagesByFoods(ctx context.Context, foodIDs []string) ([]models.MealPackage, error) // Current versions whose recipe uses any of the foods, archived included
 
 	// WorkoutPackage operations
-	GetWorkoutPackages(goalType models.GoalType) []models.WorkoutPackage
-	GetWorkoutPackage(id string) (models.WorkoutPackage, error)
+	GetWorkoutPackages(ctx context.Context, query WorkoutPackageQuery) (models.WorkoutPackagePage, error)
+	GetWorkoutPackage(ctx context.Context, id string) (models.WorkoutPackage, error)
+	GetWorkoutPackageVersion(ctx context.Context, id string, version int) (models.WorkoutPackage, error)
+	CreateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error)
+	UpdateWorkoutPackage(ctx context.Context, pkg models.WorkoutPackage) (models.WorkoutPackage, error)
+	ArchiveWorkoutPackage(ctx context.Context, id string, archived bool) (models.WorkoutPackage, error)
+
+	// Food operations
+	GetFoods(ctx context.Context, query FoodQuery) (models.FoodPage, error)
+	GetFood(ctx context.Context, id string) (models.Food, error)
+	GetFoodByBarcode(ctx context.Context, barcode string) (models.Food, error) // barcode is normalized, see package gtin
+	CreateFood(ctx context.Context, food models.Food) (models.Food, error)
+	UpdateFood(ctx context.Context, food models.Food) (models.Food, error)
+	UpsertFoods(ctx context.Context, foods []models.Food) (FoodUpsertResult, error) // Bulk import, see FoodUpsertResult
+
 	// MealEntry operations
-	CreateMealEntry(entry models.MealEntry) (models.MealEntry, error)
-	GetMealEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.MealEntry
+	CreateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error)
+	GetMealEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.MealEntry, error)
+	GetMealEntry(ctx context.Context, id string) (models.MealEntry, error)
+	UpdateMealEntry(ctx context.Context, entry models.MealEntry) (models.MealEntry, error)
+	DeleteMealEntry(ctx context.Context, id string) (models.MealEntry, error)
 
 	// WorkoutEntry operations
-	CreateWorkoutEntry(entry models.WorkoutEntry) (models.WorkoutEntry, error)
-	GetWorkoutEntriesByUserAndDateRange(userID string, startDate, endDate time.Time) []models.WorkoutEntry
+	CreateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error)
+	GetWorkoutEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WorkoutEntry, error)
+	GetWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error)
+	UpdateWorkoutEntry(ctx context.Context, entry models.WorkoutEntry) (models.WorkoutEntry, error)
+	DeleteWorkoutEntry(ctx context.Context, id string) (models.WorkoutEntry, error)
+
+	// WeightEntry operations
+	CreateWeightEntry(ctx context.Context, entry models.WeightEntry) (models.WeightEntry, error)
+	GetWeightEntriesByUserAndDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.WeightEntry, error)
+	GetLatestWeightEntry(ctx context.Context, userID string) (models.WeightEntry, error)
+	GetWeightEntry(ctx context.Context, id string) (models.WeightEntry, error)
+	DeleteWeightEntry(ctx context.Context, id string) (models.WeightEntry, error)
+
+	// UserInfo operations
+	CreateUserInfo(ctx context.Context, info models.UserInfo) (models.UserInfo, error)
+	GetUserInfoHistory(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.UserInfo, error)
+
+	// Analytics operations
+	GetDailyTotals(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyTotals, error)
 }
@@ -0,0 +1,360 @@
+package foodimport
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// FDC bundle files. branded_food.csv is only part of the branded and full downloads.
+const (
+	fdcFoodFile         = "food.csv"
+	fdcFoodNutrientFile = "food_nutrient.csv"
+	fdcNutrientFile     = "nutrient.csv"
+	fdcBrandedFoodFile  = "branded_food.csv"
+)
+
+// fdcDataTypes are the FDC data types that have nutrients per 100 g. The others
+// are lab samples and acquisitions that describe a measurement rather than a food.
+var fdcDataTypes = map[string]bool{
+	"branded_food":      true,
+	"foundation_food":   true,
+	"sr_legacy_food":    true,
+	"survey_fndds_food": true,
+}
+
+// fdcNutrientNumbers maps FDC nutrient numbers to nutrients. Some nutrients are
+// reported in more than one way; for those the lowest rank present wins.
+var fdcNutrientNumbers = map[string]struct {
+	nutrient nutrient
+	rank     int
+}{
+	"208":   {energy, 0},  // Energy, kcal
+	"957":   {energy, 1},  // Energy (Atwater General Factors)
+	"958":   {energy, 2},  // Energy (Atwater Specific Factors)
+	"268":   {energy, 3},  // Energy, kJ
+	"203":   {protein, 0}, // Protein
+	"205":   {carbs, 0},   // Carbohydrate, by difference
+	"205.2": {carbs, 1},   // Carbohydrate, by summation
+	"204":   {fat, 0},     // Total lipid (fat)
+	"291":   {fiber, 0},   // Fiber, total dietary
+	"269":   {sugar, 0},   // Sugars, total
+	"307":   {sodium, 0},  // Sodium, Na
+}
+
+// errExcluded marks a record of a kind the import leaves out
+var errExcluded = errors.New("excluded data type")
+
+// fdcNutrient is how an FDC nutrient ID maps to a nutrient
+type fdcNutrient struct {
+	nutrient nutrient
+	rank     int
+	unit     string
+}
+
+// fdcReader reads an FDC CSV bundle. food.csv is read row by row and joined with the
+// rows of food_nutrient.csv and branded_food.csv for the same food as it goes. FDC
+// writes its tables ordered by fdc_id, so the join holds only the current food's rows.
+type fdcReader struct {
+	foods     *table
+	nutrients *joinedTable
+	branded   *joinedTable // nil when the bundle has no branded foods
+	codes     map[string]fdcNutrient
+	lastID    int64
+	bundle    *fdcBundle
+}
+
+// fdcBundle opens the files of an FDC download, which is either a directory or the
+// zip file as downloaded. Files are found by name anywhere in the bundle.
+type fdcBundle struct {
+	files   map[string]func() (io.ReadCloser, error)
+	size    int64
+	closers []io.Closer
+}
+
+// openFDCBundle indexes the files of a bundle
+func openFDCBundle(bundlePath string, count *byteCounter) (*fdcBundle, error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &fdcBundle{files: make(map[string]func() (io.ReadCloser, error))}
+
+	if info.IsDir() {
+		err := filepath.WalkDir(bundlePath, func(p string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			name := strings.ToLower(entry.Name())
+			if _, seen := bundle.files[name]; seen || !isFDCFile(name) {
+				return nil
+			}
+			fileInfo, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			bundle.size += fileInfo.Size()
+			bundle.files[name] = func() (io.ReadCloser, error) {
+				file, _, err := openFile(p, count)
+				return file, err
+			}
+			return nil
+		})
+		return bundle, err
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(countingReaderAt{r: file, count: count}, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", bundlePath, err)
+	}
+	bundle.size = info.Size()
+	bundle.closers = append(bundle.closers, file)
+	for _, entry := range archive.File {
+		name := strings.ToLower(path.Base(entry.Name))
+		if _, seen := bundle.files[name]; seen || !isFDCFile(name) {
+			continue
+		}
+		bundle.files[name] = entry.Open
+	}
+	return bundle, nil
+}
+
+// isFDCFile reports whether a file is one the import reads
+func isFDCFile(name string) bool {
+	switch name {
+	case fdcFoodFile, fdcFoodNutrientFile, fdcNutrientFile, fdcBrandedFoodFile:
+		return true
+	}
+	return false
+}
+
+// open opens a file of the bundle as a table. A missing optional file returns a nil table.
+func (b *fdcBundle) open(name string, required bool, cols ...string) (*table, error) {
+	open, ok := b.files[name]
+	if !ok {
+		if required {
+			return nil, fmt.Errorf("FDC bundle has no %s", name)
+		}
+		return nil, nil
+	}
+
+	file, err := open()
+	if err != nil {
+		return nil, err
+	}
+	b.closers = append(b.closers, file)
+
+	t, err := newTable(name, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.require(cols...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// close closes the files opened from the bundle
+func (b *fdcBundle) close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		errs = append(errs, b.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
+
+// newFDCReader opens the tables of a bundle and loads the nutrient definitions
+func newFDCReader(bundle *fdcBundle) (*fdcReader, error) {
+	defs, err := bundle.open(fdcNutrientFile, true, "id", "unit_name", "nutrient_nbr")
+	if err != nil {
+		return nil, err
+	}
+	codes, err := readFDCNutrients(defs)
+	if err != nil {
+		return nil, err
+	}
+
+	foods, err := bundle.open(fdcFoodFile, true, "fdc_id", "data_type", "description")
+	if err != nil {
+		return nil, err
+	}
+	nutrients, err := bundle.open(fdcFoodNutrientFile, true, "fdc_id", "nutrient_id", "amount")
+	if err != nil {
+		return nil, err
+	}
+	branded, err := bundle.open(fdcBrandedFoodFile, false, "fdc_id")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &fdcReader{foods: foods, nutrients: &joinedTable{table: nutrients}, codes: codes, bundle: bundle}
+	if branded != nil {
+		r.branded = &joinedTable{table: branded}
+	}
+	return r, nil
+}
+
+// readFDCNutrients maps the IDs of nutrient.csv to the nutrients the import uses, by
+// their nutrient number, which is stable across FDC releases
+func readFDCNutrients(t *table) (map[string]fdcNutrient, error) {
+	codes := make(map[string]fdcNutrient)
+	for {
+		row, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var malformed *recordError
+		if errors.As(err, &malformed) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		number := strings.TrimSuffix(t.get(row, "nutrient_nbr"), ".0")
+		if known, ok := fdcNutrientNumbers[number]; ok {
+			codes[t.get(row, "id")] = fdcNutrient{nutrient: known.nutrient, rank: known.rank, unit: t.get(row, "unit_name")}
+		}
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%s defines none of the nutrients the import reads", t.name)
+	}
+	return codes, nil
+}
+
+func (r *fdcReader) next() (record, error) {
+	row, err := r.foods.next()
+	if err != nil {
+		return record{}, err
+	}
+
+	id, err := strconv.ParseInt(r.foods.get(row, "fdc_id"), 10, 64)
+	if err != nil {
+		return record{}, &recordError{file: r.foods.name, line: r.foods.line(), err: fmt.Errorf("fdc_id: %w", err)}
+	}
+	if id <= r.lastID {
+		return record{}, notOrdered(r.foods.name, id, r.lastID)
+	}
+	r.lastID = id
+
+	// Foods that are left out are not joined; their rows are skipped with the next food
+	if !fdcDataTypes[r.foods.get(row, "data_type")] {
+		return record{}, errExcluded
+	}
+
+	rec := record{
+		source:   models.FoodSourceUSDA,
+		sourceID: strconv.FormatInt(id, 10),
+		name:     r.foods.get(row, "description"),
+	}
+
+	ranks := [nutrientCount]int{}
+	err = r.nutrients.rows(id, func(t *table, row []string) {
+		code, ok := r.codes[t.get(row, "nutrient_id")]
+		if !ok {
+			return
+		}
+		amount, ok := parseNumber(t.get(row, "amount"))
+		if !ok {
+			return
+		}
+		value, ok := convert(code.nutrient, amount, code.unit)
+		if !ok || rec.known[code.nutrient] && ranks[code.nutrient] <= code.rank {
+			return
+		}
+		rec.set(code.nutrient, value)
+		ranks[code.nutrient] = code.rank
+	})
+	if err != nil {
+		return record{}, err
+	}
+
+	if r.branded != nil {
+		err = r.branded.rows(id, func(t *table, row []string) {
+			rec.brand = firstNonEmpty(t.get(row, "brand_name"), t.get(row, "brand_owner"))
+			rec.barcode = t.get(row, "gtin_upc")
+			if size, ok := parseNumber(t.get(row, "serving_size")); ok {
+				if grams, ok := toGrams(size, t.get(row, "serving_size_unit")); ok {
+					rec.servingGrams = grams
+				}
+			}
+		})
+		if err != nil {
+			return record{}, err
+		}
+	}
+
+	return rec, nil
+}
+
+// joinedTable reads a table whose rows belong to foods, in step with food.csv
+type joinedTable struct {
+	*table
+	pending   []string // First row of a later food, read ahead
+	pendingID int64
+	lastID    int64
+	done      bool
+}
+
+// rows calls fn with each row of the food, skipping the rows of foods before it.
+// Rows that cannot be parsed are ignored. It fails when the table is not ordered by fdc_id.
+func (t *joinedTable) rows(id int64, fn func(t *table, row []string)) error {
+	for {
+		if t.pending == nil {
+			if t.done {
+				return nil
+			}
+			row, err := t.next()
+			if errors.Is(err, io.EOF) {
+				t.done = true
+				return nil
+			}
+			var malformed *recordError
+			if errors.As(err, &malformed) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			rowID, err := strconv.ParseInt(t.get(row, "fdc_id"), 10, 64)
+			if err != nil {
+				continue
+			}
+			if rowID < t.lastID {
+				return notOrdered(t.name, rowID, t.lastID)
+			}
+			t.pending, t.pendingID, t.lastID = row, rowID, rowID
+		}
+
+		if t.pendingID > id {
+			return nil
+		}
+		if t.pendingID == id {
+			fn(t.table, t.pending)
+		}
+		t.pending = nil
+	}
+}
+
+// notOrdered reports a table that the join cannot read in step with food.csv
+func notOrdered(name string, id, previous int64) error {
+	return fmt.Errorf("%s is not ordered by fdc_id (%d after %d); FDC downloads are, so the file may have been re-sorted", name, id, previous)
+}
+
+func (r *fdcReader) close() error {
+	return r.bundle.close()
+}
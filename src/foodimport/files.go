@@ -0,0 +1,239 @@
+package foodimport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxLineBytes bounds one line of a dump. Longer lines are skipped as malformed,
+// so that a corrupt file cannot make the import hold it in memory.
+const maxLineBytes = 16 << 20
+
+// readBufferSize is the read buffer of each file
+const readBufferSize = 1 << 16
+
+// errLineTooLong reports a line longer than maxLineBytes
+var errLineTooLong = errors.New("line too long")
+
+// recordError is a record that could not be parsed. The import counts and skips it.
+type recordError struct {
+	file string
+	line int64
+	err  error
+}
+
+func (e *recordError) Error() string {
+	return fmt.Sprintf("%s line %d: %v", e.file, e.line, e.err)
+}
+
+func (e *recordError) Unwrap() error {
+	return e.err
+}
+
+// byteCounter counts the bytes read from the files of a dump, for progress reports
+type byteCounter struct {
+	n int64
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r     io.Reader
+	count *byteCounter
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.count.n += int64(n)
+	return n, err
+}
+
+// countingReaderAt counts the bytes read through it
+type countingReaderAt struct {
+	r     io.ReaderAt
+	count *byteCounter
+}
+
+func (r countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.r.ReadAt(p, off)
+	r.count.n += int64(n)
+	return n, err
+}
+
+// openFile opens a dump file for reading, decompressing it when its name ends in
+// .gz, and returns its size on disk. Bytes are counted as stored, before decompression.
+func openFile(path string, count *byteCounter) (io.ReadCloser, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	var r io.Reader = bufio.NewReaderSize(countingReader{r: file, count: count}, readBufferSize)
+	if !strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return readCloser{Reader: r, closers: []io.Closer{file}}, info.Size(), nil
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("%s: %w", path, err)
+	}
+	return readCloser{Reader: gz, closers: []io.Closer{gz, file}}, info.Size(), nil
+}
+
+// readCloser closes every layer of a stacked reader
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r readCloser) Close() error {
+	var errs []error
+	for _, c := range r.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// lineReader reads lines of any length while holding at most maxLineBytes of one
+type lineReader struct {
+	r    *bufio.Reader
+	buf  []byte
+	line int64
+}
+
+// next returns the next line without its line ending. The line is only valid until
+// the next call. A line longer than maxLineBytes is consumed and reported as
+// errLineTooLong. At the end of the input next returns io.EOF.
+func (l *lineReader) next() ([]byte, error) {
+	l.buf = l.buf[:0]
+	tooLong := false
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		if len(l.buf)+len(chunk) > maxLineBytes {
+			tooLong = true
+		}
+		if !tooLong {
+			l.buf = append(l.buf, chunk...)
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(l.buf) > 0 || tooLong):
+			// The last line has no line ending
+		case err != nil:
+			return nil, err
+		}
+
+		l.line++
+		if tooLong {
+			return nil, errLineTooLong
+		}
+		return bytes.TrimRight(l.buf, "\r\n"), nil
+	}
+}
+
+// table reads a delimited text file with a header row. Tab-separated files are
+// split on tabs without quoting, as the Open Food Facts export is written; other
+// files are read as comma-separated CSV.
+type table struct {
+	name  string
+	csv   *csv.Reader
+	lines *lineReader
+	cols  map[string]int
+}
+
+// newTable reads the header of a table and detects its delimiter from it
+func newTable(name string, r io.Reader) (*table, error) {
+	br := bufio.NewReaderSize(r, readBufferSize)
+	head, _ := br.Peek(readBufferSize)
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	t := &table{name: name}
+	if bytes.IndexByte(head, '\t') >= 0 {
+		t.lines = &lineReader{r: br}
+	} else {
+		t.csv = csv.NewReader(br)
+		t.csv.FieldsPerRecord = -1
+		t.csv.LazyQuotes = true
+	}
+
+	header, err := t.next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s is empty", name)
+		}
+		return nil, fmt.Errorf("%s header: %w", name, err)
+	}
+
+	t.cols = make(map[string]int, len(header))
+	for i, col := range header {
+		col = strings.TrimPrefix(col, "\ufeff")
+		t.cols[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	return t, nil
+}
+
+// require fails unless the table has all the columns
+func (t *table) require(cols ...string) error {
+	for _, col := range cols {
+		if _, ok := t.cols[col]; !ok {
+			return fmt.Errorf("%s has no %s column", t.name, col)
+		}
+	}
+	return nil
+}
+
+// next returns the next row. A row that cannot be parsed is reported as a
+// *recordError, after which reading can go on.
+func (t *table) next() ([]string, error) {
+	if t.lines != nil {
+		line, err := t.lines.next()
+		if errors.Is(err, errLineTooLong) {
+			return nil, &recordError{file: t.name, line: t.lines.line, err: err}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return strings.Split(string(line), "\t"), nil
+	}
+
+	row, err := t.csv.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &recordError{file: t.name, line: int64(parseErr.Line), err: parseErr.Err}
+	}
+	return row, err
+}
+
+// line returns the line number of the row read last
+func (t *table) line() int64 {
+	if t.lines != nil {
+		return t.lines.line
+	}
+	line, _ := t.csv.FieldPos(0)
+	return int64(line)
+}
+
+// get returns a row's value in a column, or "" when the row does not have it
+func (t *table) get(row []string, col string) string {
+	i, ok := t.cols[col]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
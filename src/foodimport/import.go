@@ -0,0 +1,283 @@
+// Package foodimport imports foods from local dumps of Open Food Facts and USDA
+// FoodData Central into a store. Dumps are streamed and written in batches, so
+// memory use does not grow with the size of the dump, and nothing is downloaded.
+package foodimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/recipes"
+)
+
+// Format is the layout of a dump
+type Format string
+
+// Dump formats
+const (
+	FormatOFFJSONL Format = "off-jsonl" // Open Food Facts JSONL export, one product per line
+	FormatOFFCSV   Format = "off-csv"   // Open Food Facts CSV export, which is tab-separated
+	FormatFDC      Format = "fdc"       // USDA FoodData Central CSV download, as a directory or zip file
+)
+
+// DefaultBatchSize is the number of foods written per bulk upsert when Options do not set one
+const DefaultBatchSize = 1000
+
+// Options configure an import
+type Options struct {
+	Format    Format      // Detected from the path when empty
+	BatchSize int         // Foods written per bulk upsert
+	Limit     int64       // Stop after this many records; zero reads the whole dump
+	DryRun    bool        // Read and normalize the dump without using the store
+	Progress  func(Stats) // Called after every batch
+}
+
+// Stats count the progress of an import
+type Stats struct {
+	BytesRead  int64 // Bytes of the dump read so far, as stored on disk (compressed for .gz and .zip)
+	BytesTotal int64
+	Read       int64 // Records read
+	Malformed  int64 // Records that could not be parsed
+	Skipped    int64 // Records without a name, protein, carbs and fat, with impossible values, or of a kind left out
+	Duplicates int64 // Records merged into another of the same batch with the same barcode, or brand and name
+	Created    int64 // Foods created
+	Updated    int64 // Foods that already existed, from an earlier batch or import, and were merged
+	Recomputed int   // Meal packages that got a new version because a food in their recipe changed
+}
+
+// source reads the records of a dump. next returns a *recordError for a record
+// that cannot be parsed, errExcluded for one the import leaves out, and io.EOF at the end.
+type source interface {
+	next() (record, error)
+	close() error
+}
+
+// DetectFormat infers the format of a dump from its path: a directory or .zip file is
+// an FDC download, .jsonl an Open Food Facts JSONL export and .csv or .tsv an Open
+// Food Facts CSV export. A .gz suffix is allowed on the single-file exports.
+func DetectFormat(path string) (Format, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return FormatFDC, nil
+	}
+
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(path)), ".gz")
+	switch filepath.Ext(name) {
+	case ".zip":
+		return FormatFDC, nil
+	case ".jsonl", ".ndjson":
+		return FormatOFFJSONL, nil
+	case ".csv", ".tsv":
+		return FormatOFFCSV, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s; set it explicitly", path)
+}
+
+// Import reads a dump and upserts its foods into the store. Foods are identified by
+// barcode, or by brand and name when they have none, so records for the same food
+// are merged and importing a dump again updates the foods it created. Meal packages
+// whose recipe uses an updated food are recomputed. On error the stats so far are
+// returned with it.
+func Import(ctx context.Context, store db.Store, path string, opts Options) (Stats, error) {
+	if opts.Format == "" {
+		format, err := DetectFormat(path)
+		if err != nil {
+			return Stats{}, err
+		}
+		opts.Format = format
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	count := &byteCounter{}
+	src, total, err := open(path, opts.Format, count)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer src.close()
+
+	imp := &importer{
+		store: store,
+		opts:  opts,
+		count: count,
+		stats: Stats{BytesTotal: total},
+		batch: newBatch(opts.BatchSize),
+	}
+	err = imp.run(ctx, src)
+	imp.countBytes()
+	return imp.stats, err
+}
+
+// open opens a dump and returns its size on disk
+func open(path string, format Format, count *byteCounter) (source, int64, error) {
+	switch format {
+	case FormatOFFJSONL:
+		file, size, err := openFile(path, count)
+		if err != nil {
+			return nil, 0, err
+		}
+		return newOFFJSONLReader(filepath.Base(path), file), size, nil
+
+	case FormatOFFCSV:
+		file, size, err := openFile(path, count)
+		if err != nil {
+			return nil, 0, err
+		}
+		src, err := newOFFCSVReader(filepath.Base(path), file)
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		return src, size, nil
+
+	case FormatFDC:
+		bundle, err := openFDCBundle(path, count)
+		if err != nil {
+			return nil, 0, err
+		}
+		src, err := newFDCReader(bundle)
+		if err != nil {
+			bundle.close()
+			return nil, 0, err
+		}
+		return src, bundle.size, nil
+	}
+	return nil, 0, fmt.Errorf("unknown format %q, expected %s, %s or %s", format, FormatOFFJSONL, FormatOFFCSV, FormatFDC)
+}
+
+// importer holds the state of one import
+type importer struct {
+	store db.Store
+	opts  Options
+	count *byteCounter
+	stats Stats
+	batch *batch
+}
+
+// run reads the dump to the end, or to the record limit, writing a batch whenever one is full
+func (imp *importer) run(ctx context.Context, src source) error {
+	for imp.opts.Limit == 0 || imp.stats.Read < imp.opts.Limit {
+		rec, err := src.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var malformed *recordError
+		switch {
+		case errors.As(err, &malformed):
+			imp.stats.Read++
+			imp.stats.Malformed++
+			continue
+		case errors.Is(err, errExcluded):
+			imp.stats.Read++
+			imp.stats.Skipped++
+			continue
+		case err != nil:
+			return err
+		}
+
+		imp.stats.Read++
+		food, err := rec.food()
+		if err != nil {
+			imp.stats.Skipped++
+			continue
+		}
+		if imp.batch.add(food) {
+			imp.stats.Duplicates++
+		}
+
+		if len(imp.batch.foods) >= imp.opts.BatchSize {
+			if err := imp.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return imp.flush(ctx)
+}
+
+// flush writes the batch and recomputes the meal packages that use its updated foods
+func (imp *importer) flush(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(imp.batch.foods) == 0 {
+		return nil
+	}
+
+	if !imp.opts.DryRun {
+		result, err := imp.store.UpsertFoods(ctx, imp.batch.foods)
+		if err != nil {
+			return err
+		}
+		imp.stats.Created += result.Created
+		imp.stats.Updated += result.Updated
+
+		// Only foods that existed before can be in a recipe
+		if result.Updated > 0 {
+			ids := make([]string, 0, len(imp.batch.foods))
+			for _, food := range imp.batch.foods {
+				ids = append(ids, food.ID)
+			}
+			recomputed, err := recipes.RecomputeForFoods(ctx, imp.store, ids...)
+			imp.stats.Recomputed += len(recomputed)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	imp.batch.reset()
+	if imp.opts.Progress != nil {
+		imp.countBytes()
+		imp.opts.Progress(imp.stats)
+	}
+	return nil
+}
+
+// countBytes updates the bytes read. Zip archives are read out of order and
+// buffered reads run ahead, so the count is capped at the size of the dump.
+func (imp *importer) countBytes() {
+	imp.stats.BytesRead = min(imp.count.n, imp.stats.BytesTotal)
+}
+
+// batch collects the foods of one bulk upsert, merging those with the same ID so
+// that the store receives each food once
+type batch struct {
+	foods []models.Food
+	index map[string]int
+}
+
+// newBatch creates an empty batch
+func newBatch(size int) *batch {
+	return &batch{
+		foods: make([]models.Food, 0, size),
+		index: make(map[string]int, size),
+	}
+}
+
+// add adds a food to the batch and reports whether it was merged into one already there
+func (b *batch) add(food models.Food) bool {
+	if i, ok := b.index[food.ID]; ok {
+		b.foods[i] = b.foods[i].MergeImport(food)
+		return true
+	}
+	b.index[food.ID] = len(b.foods)
+	b.foods = append(b.foods, food)
+	return false
+}
+
+// reset empties the batch, keeping its memory for the next one
+func (b *batch) reset() {
+	b.foods = b.foods[:0]
+	clear(b.index)
+}
@@ -0,0 +1,212 @@
+package foodimport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/gtin"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// Limits applied to imported foods, matching those of the food API
+const (
+	maxNameLength      = 200
+	maxBrandLength     = 100
+	maxCaloriesPer100g = 900 // Pure fat
+	maxServingGrams    = 5000
+)
+
+// kJPerKcal converts kilojoules to kilocalories
+const kJPerKcal = 4.184
+
+// sodiumPerSalt is the share of sodium in salt, by weight
+const sodiumPerSalt = 0.4
+
+// nutrient identifies a value of models.Nutrients
+type nutrient int
+
+// Nutrients read from dumps
+const (
+	energy nutrient = iota
+	protein
+	carbs
+	fat
+	fiber
+	sugar
+	sodium
+	nutrientCount
+)
+
+// errIncomplete rejects records that cannot be used in a recipe
+var errIncomplete = errors.New("name, protein, carbs or fat missing")
+
+// record is a food as read from a dump, with nutrient values per 100 g converted to
+// the units of models.Nutrients. Nutrients the dump does not have are not known.
+type record struct {
+	source       models.FoodSource
+	sourceID     string
+	name         string
+	brand        string
+	barcode      string
+	servingGrams float64
+	values       [nutrientCount]float64
+	known        [nutrientCount]bool
+}
+
+// set records a nutrient value
+func (r *record) set(n nutrient, value float64) {
+	r.values[n] = value
+	r.known[n] = true
+}
+
+// food normalizes a record into a food. Records without a name or without protein,
+// carbs and fat are rejected, as are physically impossible values; missing energy is
+// computed from the macros. Barcodes that are invalid or only meaningful within one
+// store are dropped, so such records are deduplicated by name instead.
+func (r record) food() (models.Food, error) {
+	name := truncate(cleanText(r.name), maxNameLength)
+	if name == "" || !r.known[protein] || !r.known[carbs] || !r.known[fat] {
+		return models.Food{}, errIncomplete
+	}
+	if !r.known[energy] {
+		r.set(energy, 4*r.values[protein]+4*r.values[carbs]+9*r.values[fat])
+	}
+
+	per100g := models.Nutrients{
+		Calories: round(r.values[energy], 1),
+		Protein:  round(r.values[protein], 2),
+		Carbs:    round(r.values[carbs], 2),
+		Fat:      round(r.values[fat], 2),
+		Fiber:    round(r.values[fiber], 2),
+		Sugar:    round(r.values[sugar], 2),
+		Sodium:   round(r.values[sodium], 1),
+	}
+	if err := nutrition.CheckPer100g(per100g); err != nil {
+		return models.Food{}, err
+	}
+	if per100g.Calories > maxCaloriesPer100g {
+		return models.Food{}, fmt.Errorf("more than %d kcal per 100 g", maxCaloriesPer100g)
+	}
+
+	food := models.Food{
+		Name:     name,
+		Brand:    truncate(cleanText(r.brand), maxBrandLength),
+		Per100g:  per100g,
+		Source:   r.source,
+		SourceID: r.sourceID,
+	}
+	if r.servingGrams > 0 && r.servingGrams <= maxServingGrams {
+		food.ServingGrams = round(r.servingGrams, 1)
+	}
+	if code, err := gtin.Normalize(r.barcode); err == nil && !gtin.Restricted(code) {
+		food.Barcode = code
+	}
+	food.ID = foodID(food)
+	return food, nil
+}
+
+// foodID derives the ID that deduplicates foods across records, dumps and repeated
+// imports: the barcode when there is one, and otherwise a hash of the brand and
+// name, compared without regard to case
+func foodID(food models.Food) string {
+	if food.Barcode != "" {
+		return models.BarcodeFoodID(food.Barcode)
+	}
+	key := strings.ToLower(food.Brand) + "\x00" + strings.ToLower(food.Name)
+	sum := sha256.Sum256([]byte(key))
+	return "name-" + hex.EncodeToString(sum[:8])
+}
+
+// gramsPerUnit maps mass and volume units to grams. Volumes assume the density of
+// water, as nutrition labels of drinks commonly do.
+var gramsPerUnit = map[string]float64{
+	"g": 1, "gr": 1, "gram": 1, "grams": 1, "grm": 1,
+	"kg": 1000, "mg": 1e-3, "ug": 1e-6, "µg": 1e-6, "mcg": 1e-6,
+	"ml": 1, "mlt": 1, "cl": 10, "dl": 100, "l": 1000,
+	"oz": 28.3495, "lb": 453.592, "fl oz": 29.5735,
+}
+
+// toGrams converts an amount in a mass or volume unit to grams
+func toGrams(value float64, unit string) (float64, bool) {
+	unit = strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(unit, ".", ""))), " ")
+	factor, ok := gramsPerUnit[unit]
+	return value * factor, ok
+}
+
+// convert converts a nutrient amount to the unit of models.Nutrients: kcal for
+// energy, milligrams for sodium and grams for the rest
+func convert(n nutrient, value float64, unit string) (float64, bool) {
+	if n == energy {
+		switch strings.ToLower(unit) {
+		case "kcal":
+			return value, true
+		case "kj":
+			return value / kJPerKcal, true
+		}
+		return 0, false
+	}
+
+	grams, ok := toGrams(value, unit)
+	if n == sodium {
+		return grams * 1000, ok
+	}
+	return grams, ok
+}
+
+// servingPattern finds an amount with a mass or volume unit in a serving size label
+// such as "1 cup (240 ml)"
+var servingPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(fl\.?\s*oz|mcg|mg|kg|grams?|gr|g|ml|cl|dl|l|oz|lb)\b`)
+
+// parseServing returns the grams of a serving size label, using the first amount with a unit
+func parseServing(label string) (float64, bool) {
+	match := servingPattern.FindStringSubmatch(label)
+	if match == nil {
+		return 0, false
+	}
+	value, ok := parseNumber(match[1])
+	if !ok {
+		return 0, false
+	}
+	return toGrams(value, match[2])
+}
+
+// parseNumber parses a number as dumps write it, accepting a decimal comma and a
+// leading "<" or "~" for values below a detection limit or estimated
+func parseNumber(text string) (float64, bool) {
+	text = strings.TrimLeft(strings.TrimSpace(text), "<>~")
+	if text == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// cleanText trims text and collapses runs of whitespace
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncate shortens text to at most max bytes without splitting a character
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	cut := strings.ToValidUTF8(text[:max], "")
+	return strings.TrimSpace(cut)
+}
+
+// round rounds a value to the given number of decimals
+func round(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
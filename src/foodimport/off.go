@@ -0,0 +1,212 @@
+package foodimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// flexNumber is a value that Open Food Facts exports either as a JSON number or as a string
+type flexNumber string
+
+func (n *flexNumber) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*n = flexNumber(text)
+		return nil
+	}
+	// Numbers are kept as written; anything else fails to parse later and counts as missing
+	*n = flexNumber(data)
+	return nil
+}
+
+// value parses the number
+func (n flexNumber) value() (float64, bool) {
+	return parseNumber(string(n))
+}
+
+// offNutriments are the nutrient fields of an Open Food Facts product the import
+// reads. Values are per 100 g, in grams except for energy.
+type offNutriments struct {
+	EnergyKcal flexNumber `json:"energy-kcal_100g"`
+	EnergyKJ   flexNumber `json:"energy-kj_100g"`
+	Energy     flexNumber `json:"energy_100g"` // Kilojoules
+	Proteins   flexNumber `json:"proteins_100g"`
+	Carbs      flexNumber `json:"carbohydrates_100g"`
+	Fat        flexNumber `json:"fat_100g"`
+	Fiber      flexNumber `json:"fiber_100g"`
+	Sugars     flexNumber `json:"sugars_100g"`
+	Sodium     flexNumber `json:"sodium_100g"`
+	Salt       flexNumber `json:"salt_100g"`
+}
+
+// offProduct is an Open Food Facts product. The JSONL export nests the nutrients
+// under "nutriments"; the CSV export has them as columns with the same names.
+type offProduct struct {
+	Code            string        `json:"code"`
+	ProductName     string        `json:"product_name"`
+	ProductNameEN   string        `json:"product_name_en"`
+	GenericName     string        `json:"generic_name"`
+	Brands          string        `json:"brands"`
+	ServingSize     string        `json:"serving_size"`     // Label such as "2 biscuits (25 g)"
+	ServingQuantity flexNumber    `json:"serving_quantity"` // Grams or millilitres
+	Nutriments      offNutriments `json:"nutriments"`
+}
+
+// record converts the product to a record in the units of models.Nutrients
+func (p offProduct) record() record {
+	r := record{
+		source:   models.FoodSourceOpenFoodFacts,
+		sourceID: p.Code,
+		barcode:  p.Code,
+		name:     firstNonEmpty(p.ProductName, p.ProductNameEN, p.GenericName),
+	}
+	// Brands are a comma-separated list, most specific first
+	r.brand, _, _ = strings.Cut(p.Brands, ",")
+
+	n := p.Nutriments
+	if v, ok := n.EnergyKcal.value(); ok {
+		r.set(energy, v)
+	} else if v, ok := firstValue(n.EnergyKJ, n.Energy); ok {
+		r.set(energy, v/kJPerKcal)
+	}
+	grams := []struct {
+		nutrient nutrient
+		value    flexNumber
+	}{{protein, n.Proteins}, {carbs, n.Carbs}, {fat, n.Fat}, {fiber, n.Fiber}, {sugar, n.Sugars}}
+	for _, field := range grams {
+		if v, ok := field.value.value(); ok {
+			r.set(field.nutrient, v)
+		}
+	}
+	if v, ok := n.Sodium.value(); ok {
+		r.set(sodium, v*1000)
+	} else if v, ok := n.Salt.value(); ok {
+		r.set(sodium, v*sodiumPerSalt*1000)
+	}
+
+	if v, ok := p.ServingQuantity.value(); ok && v > 0 {
+		r.servingGrams = v
+	} else if v, ok := parseServing(p.ServingSize); ok {
+		r.servingGrams = v
+	}
+	return r
+}
+
+// offJSONLReader reads the Open Food Facts JSONL export, one product per line
+type offJSONLReader struct {
+	name  string
+	file  io.ReadCloser
+	lines *lineReader
+}
+
+// newOFFJSONLReader reads products from a JSONL file
+func newOFFJSONLReader(name string, file io.ReadCloser) *offJSONLReader {
+	return &offJSONLReader{
+		name:  name,
+		file:  file,
+		lines: &lineReader{r: bufio.NewReaderSize(file, readBufferSize)},
+	}
+}
+
+func (r *offJSONLReader) next() (record, error) {
+	for {
+		line, err := r.lines.next()
+		if err != nil {
+			if err == errLineTooLong {
+				return record{}, &recordError{file: r.name, line: r.lines.line, err: err}
+			}
+			return record{}, err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var product offProduct
+		if err := json.Unmarshal(line, &product); err != nil {
+			return record{}, &recordError{file: r.name, line: r.lines.line, err: err}
+		}
+		return product.record(), nil
+	}
+}
+
+func (r *offJSONLReader) close() error {
+	return r.file.Close()
+}
+
+// offCSVReader reads the Open Food Facts CSV export, which is tab-separated
+type offCSVReader struct {
+	file  io.ReadCloser
+	table *table
+}
+
+// newOFFCSVReader reads products from a CSV file
+func newOFFCSVReader(name string, file io.ReadCloser) (*offCSVReader, error) {
+	t, err := newTable(name, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.require("code", "product_name"); err != nil {
+		return nil, err
+	}
+	return &offCSVReader{file: file, table: t}, nil
+}
+
+func (r *offCSVReader) next() (record, error) {
+	row, err := r.table.next()
+	if err != nil {
+		return record{}, err
+	}
+
+	get := func(col string) string { return r.table.get(row, col) }
+	product := offProduct{
+		Code:            get("code"),
+		ProductName:     get("product_name"),
+		ProductNameEN:   get("product_name_en"),
+		GenericName:     get("generic_name"),
+		Brands:          get("brands"),
+		ServingSize:     get("serving_size"),
+		ServingQuantity: flexNumber(get("serving_quantity")),
+		Nutriments: offNutriments{
+			EnergyKcal: flexNumber(get("energy-kcal_100g")),
+			EnergyKJ:   flexNumber(get("energy-kj_100g")),
+			Energy:     flexNumber(get("energy_100g")),
+			Proteins:   flexNumber(get("proteins_100g")),
+			Carbs:      flexNumber(get("carbohydrates_100g")),
+			Fat:        flexNumber(get("fat_100g")),
+			Fiber:      flexNumber(get("fiber_100g")),
+			Sugars:     flexNumber(get("sugars_100g")),
+			Sodium:     flexNumber(get("sodium_100g")),
+			Salt:       flexNumber(get("salt_100g")),
+		},
+	}
+	return product.record(), nil
+}
+
+func (r *offCSVReader) close() error {
+	return r.file.Close()
+}
+
+// firstNonEmpty returns the first of the values that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// firstValue returns the first of the numbers that parses
+func firstValue(values ...flexNumber) (float64, bool) {
+	for _, value := range values {
+		if v, ok := value.value(); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,79 @@
+// Package gtin validates and normalizes product barcodes (GTINs): EAN-8, UPC-A,
+// EAN-13 and GTIN-14
+package gtin
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned by Normalize
+var (
+	ErrFormat     = errors.New("barcode must have 8, 12, 13 or 14 digits")
+	ErrCheckDigit = errors.New("barcode check digit does not match")
+)
+
+// Normalize validates a barcode and returns it in canonical form: 13 digits, or 14
+// for a GTIN-14 whose indicator digit is not zero. Shorter codes are padded with
+// leading zeros, so a UPC-A and the EAN-13 it is printed as map to the same value.
+// Spaces and dashes are ignored.
+func Normalize(code string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+
+	switch len(digits) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrFormat
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", ErrFormat
+		}
+	}
+	if strings.Trim(digits, "0") == "" {
+		return "", ErrFormat
+	}
+
+	padded := strings.Repeat("0", 14-len(digits)) + digits
+	if checkDigit(padded[:13]) != padded[13] {
+		return "", ErrCheckDigit
+	}
+	if padded[0] == '0' {
+		return padded[1:], nil
+	}
+	return padded, nil
+}
+
+// checkDigit computes the GS1 check digit of the digits before it: from the right,
+// digits are weighted 3, 1, 3, ... and the check digit rounds the sum up to a multiple of 10
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Restricted reports whether a normalized barcode is from a range reserved for
+// in-store use, such as variable-weight items. Those codes are reused by
+// different retailers, so they do not identify a product.
+func Restricted(code string) bool {
+	if len(code) != 13 {
+		return false
+	}
+	if strings.HasPrefix(code, "00000") {
+		// EAN-8 codes starting with 0 or 2 are restricted
+		return code[5] == '0' || code[5] == '2'
+	}
+	// EAN-13 prefixes 20-29, and UPC-A number systems 2 and 4
+	return code[0] == '2' || strings.HasPrefix(code, "02") || strings.HasPrefix(code, "04")
+}
@@ -0,0 +1,117 @@
+package gtin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr error
+	}{
+		{"EAN-8", "96385074", "0000096385074", nil},
+		{"EAN-8 mistyped", "96385075", "", ErrCheckDigit},
+		{"UPC-A", "036000291452", "0036000291452", nil},
+		{"UPC-A mistyped", "036000291453", "", ErrCheckDigit},
+		{"EAN-13", "4006381333931", "4006381333931", nil},
+		{"EAN-13 mistyped", "4006381333932", "", ErrCheckDigit},
+		{"EAN-13 transposed digits", "4006381339331", "", ErrCheckDigit},
+		{"GTIN-14", "10036000291459", "10036000291459", nil},
+		{"GTIN-14 mistyped", "10036000291458", "", ErrCheckDigit},
+		{"GTIN-14 with indicator 0", "00036000291452", "0036000291452", nil},
+		{"spaces", "4 006381 333931", "4006381333931", nil},
+		{"dashes", "0-36000-29145-2", "0036000291452", nil},
+		{"all zeros", "0000000000000", "", ErrFormat},
+		{"all zeros EAN-8", "00000000", "", ErrFormat},
+		{"empty", "", "", ErrFormat},
+		{"11 digits", "03600029145", "", ErrFormat},
+		{"15 digits", "400638133393100", "", ErrFormat},
+		{"letters", "40063813339A1", "", ErrFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize(%q) error = %v, want %v", tt.code, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeUPCAMatchesEAN13(t *testing.T) {
+	upc, err := Normalize("036000291452")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ean, err := Normalize("0036000291452")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upc != ean {
+		t.Errorf("UPC-A normalizes to %q, its EAN-13 form to %q", upc, ean)
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"9638507", '4'},       // EAN-8
+		{"03600029145", '2'},   // UPC-A
+		{"400638133393", '1'},  // EAN-13
+		{"1003600029145", '9'}, // GTIN-14
+		{"501234567890", '0'},  // Sum already a multiple of 10
+	}
+	for _, tt := range tests {
+		if got := checkDigit(tt.digits); got != tt.want {
+			t.Errorf("checkDigit(%q) = %c, want %c", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestRestricted(t *testing.T) {
+	tests := []struct {
+		name string
+		code string // Normalized
+		want bool
+	}{
+		{"EAN-13 prefix 20", "2001234567893", true},
+		{"UPC-A number system 2", "0212345678909", true},
+		{"UPC-A number system 4", "0412345678903", true},
+		{"EAN-8 starting with 0", "0000001234565", true},
+		{"EAN-8 starting with 2", "0000021234569", true},
+		{"EAN-8", "0000096385074", false},
+		{"UPC-A", "0036000291452", false},
+		{"EAN-13", "4006381333931", false},
+		{"EAN-13 prefix 50", "5012345678900", false},
+		{"GTIN-14", "10036000291459", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Restricted(tt.code); got != tt.want {
+				t.Errorf("Restricted(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+// Restricted codes are still valid barcodes; callers decide what to do with them
+func TestNormalizeRestricted(t *testing.T) {
+	for _, code := range []string{"2001234567893", "212345678909", "01234565"} {
+		normalized, err := Normalize(code)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", code, err)
+			continue
+		}
+		if !Restricted(normalized) {
+			t.Errorf("Restricted(%q) = false for %q, want true", normalized, code)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/auth"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// Context keys holding the authenticated caller
+const (
+	userIDKey   = "userId"
+	userRoleKey = "userRole"
+)
+
+// AuthHandler handles login and token refresh requests
+type AuthHandler struct {
+	store  db.Store
+	tokens *auth.TokenManager
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(store db.Store, tokens *auth.TokenManager) *AuthHandler {
+	return &AuthHandler{
+		store:  store,
+		tokens: tokens,
+	}
+}
+
+// RegisterRoutes registers the authenticated auth routes to the router
+func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/auth/password", h.ChangePassword)
+}
+
+// RegisterPublicRoutes registers the auth routes that do not require authentication
+func (h *AuthHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	authGroup := router.Group("/auth")
+	{
+		authGroup.POST("/login", h.Login)
+		authGroup.POST("/refresh", h.Refresh)
+	}
+}
+
+// loginRequest defines the structure for a login request
+type loginRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
+	Password string `json:"password" binding:"required" example:"SecurePassword123"`
+}
+
+// changePasswordRequest defines the structure for a password change request
+type changePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required" example:"SecurePassword123"`
+	NewPassword string `json:"newPassword" binding:"required,min=6,max=72" example:"EvenMoreSecure456"`
+}
+
+// refreshRequest defines the structure for a token refresh request
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// loginResponse is returned after a successful login
+type loginResponse struct {
+	auth.TokenPair
+	User models.User `json:"user"`
+}
+
+// Login godoc
+// @Summary      Log in
+// @Description  Verifies email and password, records the login time and returns an access and refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      loginRequest  true  "Login credentials"
+// @Success      200          {object}  loginResponse
+// @Failure      400          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	user, err := h.store.GetUserByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			// Take as long as a wrong password so the response does not reveal the account is missing
+			auth.CheckNoPassword(req.Password)
+			c.Error(errInvalidCredentials)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if err := auth.CheckPassword(user.Password, req.Password); err != nil {
+		if errors.Is(err, auth.ErrPasswordMismatch) {
+			c.Error(errInvalidCredentials)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	user, err = h.store.RecordLogin(c.Request.Context(), user.ID, time.Now())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tokens, err := h.tokens.IssuePair(user)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse{TokenPair: tokens, User: user})
+}
+
+// Refresh godoc
+// @Summary      Refresh tokens
+// @Description  Exchanges a valid refresh token for a new access and refresh token. Tokens issued before the user's last password change are rejected.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body      refreshRequest  true  "Refresh token"
+// @Success      200    {object}  auth.TokenPair
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	claims, err := h.tokens.Parse(req.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		c.Error(unauthorized(err.Error()))
+		return
+	}
+
+	// Reload the user so deleted accounts and role changes take effect
+	user, err := h.store.GetUser(c.Request.Context(), claims.Subject)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.Error(unauthorized("user no longer exists"))
+			return
+		}
+		c.Error(err)
+		return
+	}
+	if !claims.Current(user) {
+		c.Error(unauthorized(errTokenRevoked))
+		return
+	}
+
+	tokens, err := h.tokens.IssuePair(user)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Changes the authenticated user's password after verifying the current one. Every access and refresh token issued before the change is revoked, including the caller's; the response has a new pair for the caller to continue with.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        passwords  body      changePasswordRequest  true  "Current and new password"
+// @Success      200        {object}  auth.TokenPair
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
+// @Router       /auth/password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := auth.CheckPassword(user.Password, req.OldPassword); err != nil {
+		if errors.Is(err, auth.ErrPasswordMismatch) {
+			c.Error(db.NewValidationError("oldPassword", "does not match the current password"))
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	hash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// The store also increments the token version, which revokes the tokens of every
+	// session, as they may have been stolen
+	user, err = h.store.ChangePassword(c.Request.Context(), user.ID, hash)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tokens, err := h.tokens.IssuePair(user)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// errTokenRevoked rejects tokens issued before the user's last password change
+const errTokenRevoked = "token was revoked by a password change"
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer <token>"
+// access token, or whose token was revoked, and stores the caller's ID and role in
+// the context. The user is loaded on every request, so the role is the current one
+// rather than the one in the token; the cached store keeps that cheap.
+func RequireAuth(tokens *auth.TokenManager, store db.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			c.Error(unauthorized("missing bearer token"))
+			c.Abort()
+			return
+		}
+
+		claims, err := tokens.Parse(tokenString, auth.TokenTypeAccess)
+		if err != nil {
+			c.Error(unauthorized(err.Error()))
+			c.Abort()
+			return
+		}
+
+		user, err := store.GetUser(c.Request.Context(), claims.Subject)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				err = unauthorized("user no longer exists")
+			}
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if !claims.Current(user) {
+			c.Error(unauthorized(errTokenRevoked))
+			c.Abort()
+			return
+		}
+
+		c.Set(userIDKey, claims.Subject)
+		c.Set(userRoleKey, user.Role)
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated callers that do not have the given role
+func RequireRole(role models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if currentRole(c) != role {
+			c.Error(forbidden("requires " + string(role) + " role"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// currentUserID returns the authenticated caller's user ID
+func currentUserID(c *gin.Context) string {
+	return c.GetString(userIDKey)
+}
+
+// currentRole returns the authenticated caller's role
+func currentRole(c *gin.Context) models.Role {
+	role, _ := c.Get(userRoleKey)
+	r, _ := role.(models.Role)
+	return r
+}
+
+// authorizeUser allows access to a user's resources only to that user or an admin
+func authorizeUser(c *gin.Context, userID string) error {
+	if userID == currentUserID(c) || currentRole(c) == models.RoleAdmin {
+		return nil
+	}
+	return forbidden("cannot access another user's data")
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/auth"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// testAPI is the API router over a memory store, as main wires it up
+type testAPI struct {
+	router *gin.Engine
+	store  db.Store
+	tokens *auth.TokenManager
+}
+
+func newTestAPI(t *testing.T, store db.Store) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	tokens, err := auth.NewTokenManager("test-secret", time.Hour, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.Use(RequestID(), ErrorHandler())
+	api := router.Group("/api")
+	authHandler := NewAuthHandler(store, tokens)
+	authHandler.RegisterPublicRoutes(api)
+	protected := api.Group("", RequireAuth(tokens, store))
+	authHandler.RegisterRoutes(protected)
+	NewUserHandler(store).RegisterRoutes(protected)
+
+	return &testAPI{router: router, store: store, tokens: tokens}
+}
+
+// do sends a request with an optional JSON body and bearer token
+func (a *testAPI) do(method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// createUser stores a user with a hashed password
+func (a *testAPI) createUser(t *testing.T, id, password string, role models.Role) models.User {
+	t.Helper()
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := a.store.CreateUser(context.Background(), models.User{ID: id, Email: id + "@example.com", Password: hash, Role: role})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestRequireAuthUsesCurrentRole(t *testing.T) {
+	api := newTestAPI(t, db.NewMemoryStore())
+	admin := api.createUser(t, "admin", "secret1", models.RoleAdmin)
+	tokens, err := api.tokens.IssuePair(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := api.do(http.MethodGet, "/api/users", "", tokens.AccessToken); rec.Code != http.StatusOK {
+		t.Fatalf("admin listing users: status %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	admin.Role = models.RoleUser
+	if _, err := api.store.UpdateUser(context.Background(), admin); err != nil {
+		t.Fatal(err)
+	}
+	if rec := api.do(http.MethodGet, "/api/users", "", tokens.AccessToken); rec.Code != http.StatusForbidden {
+		t.Errorf("demoted admin listing users with an ADMIN token: status %d, want 403", rec.Code)
+	}
+}
+
+// loginRaceStore runs change right after Login has read the user, as a password
+// change committing between Login's read and write would
+type loginRaceStore struct {
+	*db.MemoryStore
+	change func()
+}
+
+func (s *loginRaceStore) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	user, err := s.MemoryStore.GetUserByEmail(ctx, email)
+	if s.change != nil {
+		change := s.change
+		s.change = nil
+		change()
+	}
+	return user, err
+}
+
+func TestLoginDoesNotUndoPasswordChange(t *testing.T) {
+	store := &loginRaceStore{MemoryStore: db.NewMemoryStore()}
+	api := newTestAPI(t, store)
+	user := api.createUser(t, "u1", "secret1", models.RoleUser)
+	stolen, err := api.tokens.IssuePair(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.change = func() {
+		rec := api.do(http.MethodPost, "/api/auth/password", `{"oldPassword":"secret1","newPassword":"secret2"}`, stolen.AccessToken)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("changing the password: status %d, want 200: %s", rec.Code, rec.Body)
+		}
+	}
+	login := `{"email":"u1@example.com","password":"secret1"}`
+	if rec := api.do(http.MethodPost, "/api/auth/login", login, ""); rec.Code != http.StatusOK {
+		t.Fatalf("login that read the old password: status %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	got, err := store.GetUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TokenVersion != 1 || got.LastLoginAt.IsZero() {
+		t.Errorf("token version %d, last login %v; want version 1 and the login recorded", got.TokenVersion, got.LastLoginAt)
+	}
+	if rec := api.do(http.MethodGet, "/api/users/u1", "", stolen.AccessToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("token revoked by the password change: status %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/api/auth/login", login, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("login with the old password: status %d, want 401", rec.Code)
+	}
+	if rec := api.do(http.MethodPost, "/api/auth/login", `{"email":"u1@example.com","password":"secret2"}`, ""); rec.Code != http.StatusOK {
+		t.Errorf("login with the new password: status %d, want 200", rec.Code)
+	}
+}
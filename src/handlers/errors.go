@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/utils"
+)
+
+// Error codes used in the API error envelope
+const (
+	CodeValidation   = "VALIDATION_ERROR"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeForbidden    = "FORBIDDEN"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeUnavailable  = "SERVICE_UNAVAILABLE"
+	CodeTimeout      = "TIMEOUT"
+	CodeInternal     = "INTERNAL_ERROR"
+)
+
+// requestIDHeader is the header used to read and echo the request ID
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID
+const requestIDKey = "requestId"
+
+// ErrorResponse is the envelope returned for every failed API request
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIError describes a failed request
+type APIError struct {
+	Code      string          `json:"code" example:"NOT_FOUND"`
+	Message   string          `json:"message" example:"user 42: not found"`
+	Details   []db.FieldError `json:"details,omitempty"`
+	RequestID string          `json:"requestId" example:"1742300000000000000123"`
+}
+
+// RequestID assigns every request an ID, reusing the client's X-Request-ID
+// header when present, and echoes it back in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = utils.GenerateID()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// ErrorHandler renders the last error attached with c.Error as an ErrorResponse.
+// Handlers report failures with c.Error(err) and return without writing a body.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, apiErr := toAPIError(err)
+		apiErr.RequestID = c.GetString(requestIDKey)
+
+		if status >= http.StatusInternalServerError {
+			log.Printf("Request %s failed: %v", apiErr.RequestID, err)
+		}
+
+		c.JSON(status, ErrorResponse{Error: apiErr})
+	}
+}
+
+// toAPIError maps an error to an HTTP status and envelope.
+// Internal errors get a generic message so driver details are not leaked.
+func toAPIError(err error) (int, APIError) {
+	var validationErr *db.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, APIError{Code: CodeValidation, Message: "Request validation failed", Details: validationErr.Fields}
+	case errors.Is(err, db.ErrValidation):
+		return http.StatusBadRequest, APIError{Code: CodeValidation, Message: err.Error()}
+	case errors.Is(err, errUnauthorized):
+		return http.StatusUnauthorized, APIError{Code: CodeUnauthorized, Message: err.Error()}
+	case errors.Is(err, errForbidden):
+		return http.StatusForbidden, APIError{Code: CodeForbidden, Message: err.Error()}
+	case errors.Is(err, db.ErrNotFound):
+		return http.StatusNotFound, APIError{Code: CodeNotFound, Message: err.Error()}
+	case errors.Is(err, db.ErrConflict):
+		return http.StatusConflict, APIError{Code: CodeConflict, Message: err.Error()}
+	case errors.Is(err, db.ErrUnavailable):
+		return http.StatusServiceUnavailable, APIError{Code: CodeUnavailable, Message: "The data store is temporarily unavailable"}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, APIError{Code: CodeTimeout, Message: "The request timed out"}
+	default:
+		return http.StatusInternalServerError, APIError{Code: CodeInternal, Message: "An internal error occurred"}
+	}
+}
+
+// init makes validator report fields by their JSON names
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// bindingError converts a gin binding error into a ValidationError with per-field details
+func bindingError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]db.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, db.FieldError{
+				Field:   fe.Field(),
+				Message: validationMessage(fe),
+			})
+		}
+		return &db.ValidationError{Fields: fields}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return db.NewValidationError(typeErr.Field, fmt.Sprintf("must be a %s", typeErr.Type.String()))
+	}
+
+	return db.NewValidationError("body", "Malformed JSON request body")
+}
+
+// validationMessage describes a failed validator tag in plain words
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	default:
+		return "failed the " + fe.Tag() + " check"
+	}
+}
+
+// referenceError turns a not-found error for a document referenced from the
+// request body into a validation error on that field; other errors pass through
+func referenceError(field string, err error) error {
+	if errors.Is(err, db.ErrNotFound) {
+		return db.NewValidationError(field, err.Error())
+	}
+	return err
+}
+
+// Authentication and authorization errors. They are HTTP concerns rather than
+// store errors, so they live here instead of in package db.
+var (
+	errUnauthorized = errors.New("unauthorized")
+	errForbidden    = errors.New("forbidden")
+
+	errInvalidCredentials = unauthorized("invalid email or password")
+)
+
+// statusError attaches a client-facing message to one of the sentinel errors above
+type statusError struct {
+	kind    error
+	message string
+}
+
+// Error implements the error interface
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// Unwrap exposes the sentinel error to errors.Is
+func (e *statusError) Unwrap() error {
+	return e.kind
+}
+
+// unauthorized builds an error rendered as 401
+func unauthorized(message string) error {
+	return &statusError{kind: errUnauthorized, message: message}
+}
+
+// forbidden builds an error rendered as 403
+func forbidden(message string) error {
+	return &statusError{kind: errForbidden, message: message}
+}
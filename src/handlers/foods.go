@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/gtin"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"github.com/zhenyili/BalanceLife/src/recipes"
+)
+
+// defaultServingGrams is the serving of foods without a labelled serving size
+const defaultServingGrams = 100
+
+// FoodHandler handles food database requests
+type FoodHandler struct {
+	store db.Store
+}
+
+// NewFoodHandler creates a new food handler
+func NewFoodHandler(store db.Store) *FoodHandler {
+	return &FoodHandler{
+		store: store,
+	}
+}
+
+// RegisterRoutes registers food routes to the router
+func (h *FoodHandler) RegisterRoutes(router *gin.RouterGroup) {
+	foods := router.Group("/foods")
+	{
+		foods.GET("", h.GetFoods)
+		foods.GET("/:id", h.GetFood)
+		foods.GET("/barcode/:code", h.GetFoodByBarcode)
+		foods.POST("/barcode/:code/entries", h.LogBarcodeFood)
+
+		// The food database is shared, so only admins change it
+		foods.POST("", RequireRole(models.RoleAdmin), h.CreateFood)
+		foods.PUT("/:id", RequireRole(models.RoleAdmin), h.UpdateFood)
+	}
+}
+
+// GetFoods godoc
+// @Summary      List foods
+// @Description  Returns one page of foods ordered by name. Text search matches words in the name. Pass nextCursor from a response as cursor to get the next page.
+// @Tags         foods
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        q       query     string  false  "Text search"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Param        limit   query     int     false  "Page size (1-100), defaults to 20"
+// @Success      200     {object}  models.FoodPage
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Failure      503     {object}  ErrorResponse
+// @Router       /foods [get]
+func (h *FoodHandler) GetFoods(c *gin.Context) {
+	query := db.FoodQuery{
+		Search: strings.TrimSpace(c.Query("q")),
+		Cursor: c.Query("cursor"),
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > db.MaxPageSize {
+			c.Error(db.NewValidationError("limit", "Must be a number between 1 and 100"))
+			return
+		}
+		query.Limit = limit
+	}
+
+	page, err := h.store.GetFoods(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetFood godoc
+// @Summary      Get a food by ID
+// @Description  Returns a food with its nutrition per 100 g
+// @Tags         foods
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Food ID"
+// @Success      200  {object}  models.Food
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /foods/{id} [get]
+func (h *FoodHandler) GetFood(c *gin.Context) {
+	food, err := h.store.GetFood(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, food)
+}
+
+// GetFoodByBarcode godoc
+// @Summary      Look up a food by barcode
+// @Description  Returns the packaged food with a barcode and its nutrition per serving. UPC-A, EAN-8, EAN-13 and GTIN-14 codes are accepted and the check digit is verified, so a UPC-A finds the same food as the EAN-13 it is printed as. The serving is the labelled serving size, or 100 g when the food has none.
+// @Tags         foods
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        code  path      string  true  "Barcode"
+// @Success      200   {object}  models.FoodServing
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /foods/barcode/{code} [get]
+func (h *FoodHandler) GetFoodByBarcode(c *gin.Context) {
+	food, err := h.barcodeFood(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, foodServing(food))
+}
+
+// foodEntryRequest defines a meal entry logged from a food by number of servings
+type foodEntryRequest struct {
+	Servings float64 `json:"servings" binding:"required,gt=0,max=20" example:"1.5"`
+	MealType string  `json:"mealType" binding:"required" example:"SNACK" enums:"BREAKFAST,LUNCH,DINNER,SNACK"`
+	Date     string  `json:"date" binding:"required" example:"2023-03-18"`
+}
+
+// LogBarcodeFood godoc
+// @Summary      Log a food by barcode
+// @Description  Logs the packaged food with a barcode as a meal entry for the authenticated user. Nutrition is computed from the food's serving size, or 100 g when it has none, times the number of servings. The entry keeps the food ID and the nutrition of one serving, so changing its servings later rescales it, while later corrections to the food leave it unchanged.
+// @Tags         foods
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        code   path      string            true  "Barcode"
+// @Param        entry  body      foodEntryRequest  true  "Servings, meal type and date"
+// @Success      201    {object}  models.MealEntry
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /foods/barcode/{code}/entries [post]
+func (h *FoodHandler) LogBarcodeFood(c *gin.Context) {
+	var req foodEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	newEntry, err := newMealEntry(c, req.Date, req.MealType)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	food, err := h.barcodeFood(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	newEntry.FoodID = food.ID
+	newEntry.PortionMultiplier = req.Servings
+	applyFood(&newEntry, food)
+
+	saveMealEntry(c, h.store, newEntry)
+}
+
+// barcodeFood normalizes the barcode in the path and fetches its food
+func (h *FoodHandler) barcodeFood(c *gin.Context) (models.Food, error) {
+	code, err := normalizeBarcode("code", c.Param("code"))
+	if err != nil {
+		return models.Food{}, err
+	}
+	return h.store.GetFoodByBarcode(c.Request.Context(), code)
+}
+
+// normalizeBarcode validates a barcode, reporting problems on the given field
+func normalizeBarcode(field, code string) (string, error) {
+	normalized, err := gtin.Normalize(code)
+	switch {
+	case errors.Is(err, gtin.ErrFormat):
+		return "", db.NewValidationError(field, "Must be a UPC-A, EAN-8, EAN-13 or GTIN-14 barcode")
+	case errors.Is(err, gtin.ErrCheckDigit):
+		return "", db.NewValidationError(field, "Check digit does not match; the barcode may be mistyped")
+	}
+	return normalized, err
+}
+
+// foodServing computes the nutrition of one serving of a food
+func foodServing(food models.Food) models.FoodServing {
+	grams := food.ServingGrams
+	if grams <= 0 {
+		grams = defaultServingGrams
+	}
+	return models.FoodServing{
+		Food:         food,
+		ServingGrams: grams,
+		PerServing:   nutrition.RoundNutrients(nutrition.ForGrams(food.Per100g, grams)),
+	}
+}
+
+// nutrientsRequest defines nutrition values per 100 g
+type nutrientsRequest struct {
+	Calories float64 `json:"calories" binding:"min=0,max=900" example:"165"`
+	Protein  float64 `json:"protein" binding:"min=0,max=100" example:"31"`
+	Carbs    float64 `json:"carbs" binding:"min=0,max=100" example:"0"`
+	Fat      float64 `json:"fat" binding:"min=0,max=100" example:"3.6"`
+	Fiber    float64 `json:"fiber" binding:"min=0,max=100" example:"0"`
+	Sugar    float64 `json:"sugar" binding:"min=0,max=100" example:"0"`
+	Sodium   float64 `json:"sodium" binding:"min=0,max=100000" example:"74"` // Milligrams
+}
+
+// foodRequest defines the fields of a food
+type foodRequest struct {
+	Name         string           `json:"name" binding:"required,max=200" example:"Chicken breast, cooked"`
+	Brand        string           `json:"brand" binding:"max=100" example:"Acme"`
+	Per100g      nutrientsRequest `json:"per100g"`
+	ServingGrams float64          `json:"servingGrams" binding:"min=0,max=5000" example:"30"` // Labelled serving size, optional
+}
+
+// createFoodRequest defines the structure for food creation
+type createFoodRequest struct {
+	FoodID  string `json:"foodId" binding:"omitempty,max=64" example:"chicken-breast"` // Generated when omitted, or derived from the barcode
+	Barcode string `json:"barcode" example:"0012345678905"`                            // UPC-A, EAN-8, EAN-13 or GTIN-14 of a packaged food, optional
+	foodRequest
+}
+
+// updateFoodResponse is a corrected food with the meal packages recomputed from it
+type updateFoodResponse struct {
+	Food               models.Food `json:"food"`
+	RecomputedPackages []string    `json:"recomputedPackages"` // IDs of the meal packages that got a new version
+}
+
+// toFood validates the request and builds the food it describes
+func (r foodRequest) toFood(id string) (models.Food, error) {
+	per100g := models.Nutrients(r.Per100g)
+	if err := nutrition.CheckPer100g(per100g); err != nil {
+		return models.Food{}, db.NewValidationError("per100g", err.Error())
+	}
+
+	return models.Food{
+		ID:           id,
+		Name:         strings.TrimSpace(r.Name),
+		Brand:        strings.TrimSpace(r.Brand),
+		Per100g:      per100g,
+		ServingGrams: r.ServingGrams,
+	}, nil
+}
+
+// CreateFood godoc
+// @Summary      Create a food
+// @Description  Adds a food with its nutrition per 100 g to the food database. A packaged food may have a barcode, which must be valid, not store-internal and not used by another food. Requires the ADMIN role.
+// @Tags         foods
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        food  body      createFoodRequest  true  "Food"
+// @Success      201   {object}  models.Food
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /foods [post]
+func (h *FoodHandler) CreateFood(c *gin.Context) {
+	var req createFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	food, err := req.toFood(req.FoodID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if req.Barcode != "" {
+		if err := h.setBarcode(c, &food, req.Barcode); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	created, err := h.store.CreateFood(c.Request.Context(), food)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// setBarcode validates a new food's barcode and checks that no other food has it.
+// Foods with a barcode get the ID the importer gives them, so importing the
+// product later updates the food instead of duplicating it.
+func (h *FoodHandler) setBarcode(c *gin.Context, food *models.Food, code string) error {
+	barcode, err := normalizeBarcode("barcode", code)
+	if err != nil {
+		return err
+	}
+	if gtin.Restricted(barcode) {
+		return db.NewValidationError("barcode", "Store-internal and coupon barcodes cannot identify a food")
+	}
+
+	existing, err := h.store.GetFoodByBarcode(c.Request.Context(), barcode)
+	switch {
+	case err == nil:
+		return fmt.Errorf("barcode %s belongs to food %s: %w", barcode, existing.ID, db.ErrConflict)
+	case !errors.Is(err, db.ErrNotFound):
+		return err
+	}
+
+	food.Barcode = barcode
+	if food.ID == "" {
+		food.ID = models.BarcodeFoodID(barcode)
+	}
+	return nil
+}
+
+// UpdateFood godoc
+// @Summary      Correct a food
+// @Description  Replaces a food's name, brand, serving size and nutrition; the barcode and source of an imported food are kept. Every meal package whose recipe uses the food is recomputed and gets a new version; entries logged earlier keep their values. Requires the ADMIN role.
+// @Tags         foods
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string       true  "Food ID"
+// @Param        food  body      foodRequest  true  "Food"
+// @Success      200   {object}  updateFoodResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /foods/{id} [put]
+func (h *FoodHandler) UpdateFood(c *gin.Context) {
+	var req foodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	food, err := req.toFood(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := h.store.UpdateFood(c.Request.Context(), food)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	recomputed, err := recipes.RecomputeForFoods(c.Request.Context(), h.store, updated.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updateFoodResponse{Food: updated, RecomputedPackages: recomputed})
+}
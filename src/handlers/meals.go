@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhenyili/BalanceLife/src/db"
 	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
 	"github.com/zhenyili/BalanceLife/src/utils"
 )
 
@@ -29,129 +33,303 @@ func (h *MealHandler) RegisterRoutes(router *gin.RouterGroup) {
 		// Meal packages
 		meals.GET("/packages", h.GetMealPackages)
 		meals.GET("/packages/:id", h.GetMealPackage)
+		meals.GET("/packages/:id/versions/:version", h.GetMealPackageVersion)
+		meals.POST("/packages/:id/clone", h.CloneMealPackage)
+
+		// Package management. Admins manage the catalog; owners manage their own packages.
+		meals.POST("/packages", RequireRole(models.RoleAdmin), h.CreateMealPackage)
+		meals.PUT("/packages/:id", h.UpdateMealPackage)
+		meals.DELETE("/packages/:id", h.ArchiveMealPackage)
+		meals.POST("/packages/:id/restore", h.RestoreMealPackage)
 
 		// Meal entries
 		meals.POST("/entries", h.CreateMealEntry)
+		meals.POST("/entries/custom", h.CreateCustomMealEntry)
 		meals.GET("/entries", h.GetMealEntries)
+		meals.GET("/entries/:id", h.GetMealEntry)
+		meals.PATCH("/entries/:id", h.UpdateMealEntry)
+		meals.DELETE("/entries/:id", h.DeleteMealEntry)
+		meals.POST("/entries/:id/promote", h.PromoteMealEntry)
 	}
 }
 
 // GetMealPackages godoc
-// @Summary      Get all meal packages
-// @Description  Returns a list of all meal packages, optionally filtered by goal type
+// @Summary      List meal packages
+// @Description  Returns one page of meal packages matching the filters. Filtering on LOSE or GAIN includes packages for BOTH goals, and suitsGoal marks the packages that fit the caller's current goal. Text search matches words in the name, description and ingredients. Lists include the catalog, the caller's own packages and other users' public packages unless scope narrows them. Pass nextCursor from a response as cursor to get the next page.
 // @Tags         meals
 // @Produce      json
-// @Param        goalType  query     string  false  "Goal type filter (LOSE, GAIN, ALL)"
-// @Success      200       {array}   models.MealPackage
+// @Security     ApiKeyAuth
+// @Param        goalType         query     string  false  "Goal type filter (LOSE, GAIN, BOTH, ALL)"
+// @Param        mealType         query     string  false  "Meal type filter (BREAKFAST, LUNCH, DINNER, SNACK)"
+// @Param        q                query     string  false  "Text search"
+// @Param        minCalories      query     int     false  "Minimum calories"
+// @Param        maxCalories      query     int     false  "Maximum calories"
+// @Param        minProtein       query     int     false  "Minimum protein in grams"
+// @Param        maxProtein       query     int     false  "Maximum protein in grams"
+// @Param        minCarbs         query     int     false  "Minimum carbs in grams"
+// @Param        maxCarbs         query     int     false  "Maximum carbs in grams"
+// @Param        minFat           query     int     false  "Minimum fat in grams"
+// @Param        maxFat           query     int     false  "Maximum fat in grams"
+// @Param        sort             query     string  false  "Sort key (name, calories, protein, proteinDensity), prefix with - for descending"
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Page size (1-100), defaults to 20"
+// @Param        scope            query     string  false  "Packages to list: all (default) for the catalog, your own and public packages; catalog; or mine"
+// @Param        includeArchived  query     bool    false  "Include archived packages (ADMIN only)"
+// @Success      200              {object}  models.MealPackagePage
+// @Failure      400              {object}  ErrorResponse
+// @Failure      401              {object}  ErrorResponse
+// @Failure      403              {object}  ErrorResponse
+// @Failure      500              {object}  ErrorResponse
+// @Failure      503              {object}  ErrorResponse
 // @Router       /meals/packages [get]
 func (h *MealHandler) GetMealPackages(c *gin.Context) {
-	goalType := models.GoalType(c.Query("goalType"))
-	packages := h.store.GetMealPackages(goalType)
-	c.JSON(http.StatusOK, packages)
+	params, err := parsePackageListParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query := db.MealPackageQuery{
+		GoalType:        params.GoalType,
+		Search:          params.Search,
+		Sort:            params.Sort,
+		Viewer:          currentUserID(c),
+		Scope:           params.Scope,
+		IncludeArchived: params.IncludeArchived,
+		Descending:      params.Descending,
+		Cursor:          params.Cursor,
+		Limit:           params.Limit,
+	}
+	if query.MealType, err = parseMealType(c.Query("mealType")); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Calories, err = parseRange(c, "Calories"); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Protein, err = parseRange(c, "Protein"); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Carbs, err = parseRange(c, "Carbs"); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Fat, err = parseRange(c, "Fat"); err != nil {
+		c.Error(err)
+		return
+	}
+
+	goal, err := callerGoal(c, h.store)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.store.GetMealPackages(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	for i := range page.Packages {
+		page.Packages[i].SuitsGoal = page.Packages[i].GoalType.Suits(goal)
+	}
+	c.JSON(http.StatusOK, page)
 }
 
 // GetMealPackage godoc
 // @Summary      Get a meal package by ID
-// @Description  Returns details of a specific meal package
+// @Description  Returns details of a specific meal package, with suitsGoal set for the caller's current goal. Private packages are visible only to their owner, and packages shared by link need their share token.
 // @Tags         meals
 // @Produce      json
-// @Param        id   path      string  true  "Meal Package ID"
-// @Success      200  {object}  models.MealPackage
-// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Param        id     path      string  true   "Meal Package ID"
+// @Param        token  query     string  false  "Share token of a package shared by link"
+// @Success      200    {object}  models.MealPackage
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
 // @Router       /meals/packages/{id} [get]
 func (h *MealHandler) GetMealPackage(c *gin.Context) {
-	id := c.Param("id")
-	pkg, err := h.store.GetMealPackage(id)
+	goal, err := callerGoal(c, h.store)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
+
+	pkg, err := h.viewableMealPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	pkg.SuitsGoal = pkg.GoalType.Suits(goal)
 	c.JSON(http.StatusOK, pkg)
 }
 
 // mealEntryRequest defines the structure for meal entry creation
 type mealEntryRequest struct {
-	UserID            string  `json:"userId" binding:"required" example:"usr1"`
 	PackageID         string  `json:"packageId" binding:"required" example:"meal1"`
 	PortionMultiplier float64 `json:"portionMultiplier" binding:"required,min=0.1,max=3" example:"1.0"`
 	Date              string  `json:"date" binding:"required" example:"2023-03-18"`
+	ShareToken        string  `json:"shareToken"` // Needed for packages shared by link
 }
 
 // CreateMealEntry godoc
 // @Summary      Create a new meal entry
-// @Description  Logs a meal for a user with specified portion size
+// @Description  Logs a meal for the authenticated user with specified portion size
 // @Tags         meals
 // @Accept       json
 // @Produce      json
+// @Security     ApiKeyAuth
 // @Param        entry  body      mealEntryRequest  true  "Meal entry details"
 // @Success      201    {object}  models.MealEntry
-// @Failure      400    {object}  map[string]string
-// @Failure      500    {object}  map[string]string
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
 // @Router       /meals/entries [post]
 func (h *MealHandler) CreateMealEntry(c *gin.Context) {
 	var req mealEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindingError(err))
 		return
 	}
 
-	// Parse date
-	date, err := time.Parse("2006-01-02", req.Date)
+	// The meal type comes from the package
+	newEntry, err := newMealEntry(c, req.Date, "")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		c.Error(err)
 		return
 	}
 
 	// Get meal package to calculate nutritional values
-	pkg, err := h.store.GetMealPackage(req.PackageID)
+	pkg, err := h.loggableMealPackage(c, req.PackageID, req.ShareToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal package: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
-	// Calculate nutritional values based on portion size
-	newEntry := models.MealEntry{
-		ID:                utils.GenerateID(),
-		UserID:            req.UserID,
-		PackageID:         req.PackageID,
-		PortionMultiplier: req.PortionMultiplier,
-		Calories:          int(float64(pkg.BaseCalories) * req.PortionMultiplier),
-		Protein:           int(float64(pkg.BaseProtein) * req.PortionMultiplier),
-		Carbs:             int(float64(pkg.BaseCarbs) * req.PortionMultiplier),
-		Fat:               int(float64(pkg.BaseFat) * req.PortionMultiplier),
-		MealType:          pkg.MealType,
-		Date:              date,
-		Timestamp:         time.Now(),
-		CreatedAt:         time.Now(),
+	newEntry.PackageID = req.PackageID
+	newEntry.PortionMultiplier = req.PortionMultiplier
+	applyMealPackage(&newEntry, pkg)
+
+	saveMealEntry(c, h.store, newEntry)
+}
+
+// customMealEntryRequest defines a meal entry with manually entered nutrition values
+type customMealEntryRequest struct {
+	Name     string `json:"name" binding:"required,max=100" example:"Homemade lasagna"`
+	MealType string `json:"mealType" binding:"required" example:"DINNER" enums:"BREAKFAST,LUNCH,DINNER,SNACK"`
+	Calories int    `json:"calories" binding:"required,min=1,max=5000" example:"650"` // Must be within 15% of the calories implied by the macros
+	Protein  int    `json:"protein" binding:"min=0,max=500" example:"35"`
+	Carbs    int    `json:"carbs" binding:"min=0,max=1000" example:"60"`
+	Fat      int    `json:"fat" binding:"min=0,max=500" example:"28"`
+	Date     string `json:"date" binding:"required" example:"2023-03-18"`
+}
+
+// CreateCustomMealEntry godoc
+// @Summary      Create a custom meal entry
+// @Description  Logs a meal for the authenticated user from manually entered calories and macros instead of a package. Calories must be within 15% of the calories implied by the macros. Custom entries count toward summaries and trends like package entries.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        entry  body      customMealEntryRequest  true  "Custom meal entry details"
+// @Success      201    {object}  models.MealEntry
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /meals/entries/custom [post]
+func (h *MealHandler) CreateCustomMealEntry(c *gin.Context) {
+	var req customMealEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
 	}
 
-	// Save the entry
-	createdEntry, err := h.store.CreateMealEntry(newEntry)
+	newEntry, err := newMealEntry(c, req.Date, req.MealType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save meal entry: " + err.Error()})
+		c.Error(err)
+		return
+	}
+
+	newEntry.Custom = true
+	newEntry.Name = strings.TrimSpace(req.Name)
+	newEntry.PortionMultiplier = 1
+	newEntry.Calories = req.Calories
+	newEntry.Protein = req.Protein
+	newEntry.Carbs = req.Carbs
+	newEntry.Fat = req.Fat
+	if err := checkCustomMeal(newEntry); err != nil {
+		c.Error(err)
+		return
+	}
+
+	saveMealEntry(c, h.store, newEntry)
+}
+
+// newMealEntry starts a meal entry for the caller on a YYYY-MM-DD date, with a meal
+// type unless mealType is empty. The caller adds what it was logged from.
+func newMealEntry(c *gin.Context, date, mealType string) (models.MealEntry, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return models.MealEntry{}, db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD")
+	}
+
+	parsedType, err := parseMealType(mealType)
+	if err != nil {
+		return models.MealEntry{}, err
+	}
+
+	now := time.Now()
+	return models.MealEntry{
+		ID:        utils.GenerateID(),
+		UserID:    currentUserID(c),
+		MealType:  parsedType,
+		Date:      day,
+		Timestamp: now,
+		CreatedAt: now,
+	}, nil
+}
+
+// saveMealEntry stores a new meal entry and responds with it
+func saveMealEntry(c *gin.Context, store db.Store, entry models.MealEntry) {
+	createdEntry, err := store.CreateMealEntry(c.Request.Context(), entry)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, createdEntry)
 }
 
+// checkCustomMeal verifies that a custom entry's calories agree with its macros
+func checkCustomMeal(entry models.MealEntry) error {
+	if err := nutrition.CheckMacroCalories(entry.Calories, entry.Protein, entry.Carbs, entry.Fat); err != nil {
+		return db.NewValidationError("calories", err.Error())
+	}
+	return nil
+}
+
 // GetMealEntries godoc
-// @Summary      Get meal entries for a user
-// @Description  Returns meal entries for a user within a date range
+// @Summary      Get meal entries for the caller
+// @Description  Returns the authenticated user's meal entries within a date range
 // @Tags         meals
 // @Produce      json
-// @Param        userId     query     string  true   "User ID"
+// @Security     ApiKeyAuth
 // @Param        startDate  query     string  false  "Start date (YYYY-MM-DD)"
 // @Param        endDate    query     string  false  "End date (YYYY-MM-DD)"
 // @Success      200        {array}   models.MealEntry
-// @Failure      400        {object}  map[string]string
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
 // @Router       /meals/entries [get]
 func (h *MealHandler) GetMealEntries(c *gin.Context) {
-	userID := c.Query("userId")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
-		return
-	}
+	userID := currentUserID(c)
 
 	// Parse start and end dates
 	startDateStr := c.DefaultQuery("startDate", time.Now().Format("2006-01-02"))
@@ -159,19 +337,321 @@ func (h *MealHandler) GetMealEntries(c *gin.Context) {
 
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid startDate format, use YYYY-MM-DD"})
+		c.Error(db.NewValidationError("startDate", "Invalid date format, use YYYY-MM-DD"))
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endDate format, use YYYY-MM-DD"})
+		c.Error(db.NewValidationError("endDate", "Invalid date format, use YYYY-MM-DD"))
 		return
 	}
 
 	// Make sure the end date is inclusive by setting it to the end of the day
 	endDate = endDate.Add(24*time.Hour - time.Second)
 
-	entries := h.store.GetMealEntriesByUserAndDateRange(userID, startDate, endDate)
+	entries, err := h.store.GetMealEntriesByUserAndDateRange(c.Request.Context(), userID, startDate, endDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 	c.JSON(http.StatusOK, entries)
 }
+
+// applyMealPackage sets an entry's nutritional values from its package, scaled by the portion size
+func applyMealPackage(entry *models.MealEntry, pkg models.MealPackage) {
+	entry.PackageVersion = pkg.Version
+	entry.Calories = int(float64(pkg.BaseCalories) * entry.PortionMultiplier)
+	entry.Protein = int(float64(pkg.BaseProtein) * entry.PortionMultiplier)
+	entry.Carbs = int(float64(pkg.BaseCarbs) * entry.PortionMultiplier)
+	entry.Fat = int(float64(pkg.BaseFat) * entry.PortionMultiplier)
+	entry.MealType = pkg.MealType
+}
+
+// applyFood sets a food entry's name, serving and nutritional values from the food,
+// for the entry's number of servings. The entry keeps the nutrition of one serving,
+// so later changes to the food do not change it.
+func applyFood(entry *models.MealEntry, food models.Food) {
+	serving := foodServing(food)
+	entry.Name = food.Name
+	if food.Brand != "" {
+		entry.Name = fmt.Sprintf("%s (%s)", food.Name, food.Brand)
+	}
+	entry.ServingGrams = serving.ServingGrams
+	entry.PerServing = &serving.PerServing
+	applyServings(entry)
+}
+
+// applyServings sets a food entry's nutritional values from its stored serving,
+// scaled by the number of servings
+func applyServings(entry *models.MealEntry) {
+	n := entry.PerServing
+	entry.Calories = int(math.Round(n.Calories * entry.PortionMultiplier))
+	entry.Protein = int(math.Round(n.Protein * entry.PortionMultiplier))
+	entry.Carbs = int(math.Round(n.Carbs * entry.PortionMultiplier))
+	entry.Fat = int(math.Round(n.Fat * entry.PortionMultiplier))
+}
+
+// loggableMealPackage fetches the current version of a package for a new entry, rejecting
+// archived packages and packages the caller may not see. token is the package's share token, if any.
+func (h *MealHandler) loggableMealPackage(c *gin.Context, id, token string) (models.MealPackage, error) {
+	pkg, err := h.viewableMealPackage(c, id, token)
+	if err != nil {
+		return models.MealPackage{}, referenceError("packageId", err)
+	}
+	if pkg.Archived {
+		return models.MealPackage{}, archivedPackageError(id)
+	}
+	return pkg, nil
+}
+
+// entryMealPackage fetches the package version an entry was logged with.
+// Entries logged before versioning use the current version.
+func (h *MealHandler) entryMealPackage(c *gin.Context, entry models.MealEntry) (models.MealPackage, error) {
+	var pkg models.MealPackage
+	var err error
+	if entry.PackageVersion == 0 {
+		pkg, err = h.store.GetMealPackage(c.Request.Context(), entry.PackageID)
+	} else {
+		pkg, err = h.store.GetMealPackageVersion(c.Request.Context(), entry.PackageID, entry.PackageVersion)
+	}
+	if err != nil {
+		return models.MealPackage{}, referenceError("packageId", err)
+	}
+	return pkg, nil
+}
+
+// loadMealEntry fetches a meal entry and checks that the caller may access it
+func (h *MealHandler) loadMealEntry(c *gin.Context) (models.MealEntry, error) {
+	entry, err := h.store.GetMealEntry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		return models.MealEntry{}, err
+	}
+	if err := authorizeUser(c, entry.UserID); err != nil {
+		return models.MealEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetMealEntry godoc
+// @Summary      Get a meal entry by ID
+// @Description  Returns one of the authenticated user's meal entries
+// @Tags         meals
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Meal Entry ID"
+// @Success      200  {object}  models.MealEntry
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /meals/entries/{id} [get]
+func (h *MealHandler) GetMealEntry(c *gin.Context) {
+	entry, err := h.loadMealEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// updateMealEntryRequest defines the fields of a meal entry that can be changed.
+// Omitted fields keep their current values. Package entries accept packageId and
+// portionMultiplier; custom entries accept name, mealType, calories and macros;
+// food entries accept servings and mealType.
+type updateMealEntryRequest struct {
+	PackageID         *string  `json:"packageId" binding:"omitempty,min=1" example:"meal2"`
+	PortionMultiplier *float64 `json:"portionMultiplier" binding:"omitempty,min=0.1,max=3" example:"1.5"`
+	Date              *string  `json:"date" example:"2023-03-18"`
+	Name              *string  `json:"name" binding:"omitempty,min=1,max=100" example:"Homemade lasagna"`
+	MealType          *string  `json:"mealType" example:"DINNER" enums:"BREAKFAST,LUNCH,DINNER,SNACK"`
+	Calories          *int     `json:"calories" binding:"omitempty,min=1,max=5000" example:"650"`
+	Protein           *int     `json:"protein" binding:"omitempty,min=0,max=500" example:"35"`
+	Carbs             *int     `json:"carbs" binding:"omitempty,min=0,max=1000" example:"60"`
+	Fat               *int     `json:"fat" binding:"omitempty,min=0,max=500" example:"28"`
+	Servings          *float64 `json:"servings" binding:"omitempty,gt=0,max=20" example:"2"`
+	ShareToken        string   `json:"shareToken"` // Needed when changing to a package shared by link
+}
+
+// customFields reports whether the request changes any field that only custom entries have
+func (r updateMealEntryRequest) customFields() bool {
+	return r.Name != nil || r.MealType != nil || r.Calories != nil || r.Protein != nil || r.Carbs != nil || r.Fat != nil
+}
+
+// UpdateMealEntry godoc
+// @Summary      Update a meal entry
+// @Description  Changes the package, portion size or date of a package entry, the name, meal type, calories, macros or date of a custom entry, or the servings, meal type or date of a food entry. Package entries are recomputed from the package version they were logged with, or from the current version when the package changes. Custom entries must keep calories within 15% of the macros. Food entries are rescaled from the nutrition per serving they were logged with when the servings change.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id     path      string                  true  "Meal Entry ID"
+// @Param        entry  body      updateMealEntryRequest  true  "Fields to change"
+// @Success      200    {object}  models.MealEntry
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      403    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /meals/entries/{id} [patch]
+func (h *MealHandler) UpdateMealEntry(c *gin.Context) {
+	var req updateMealEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	entry, err := h.loadMealEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.Date != nil {
+		date, err := time.Parse("2006-01-02", *req.Date)
+		if err != nil {
+			c.Error(db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD"))
+			return
+		}
+		entry.Date = date
+	}
+	switch {
+	case entry.Custom:
+		err = applyCustomMealUpdate(&entry, req)
+	case entry.FoodID != "":
+		err = applyFoodMealUpdate(&entry, req)
+	default:
+		err = h.applyPackageMealUpdate(c, &entry, req)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	updatedEntry, err := h.store.UpdateMealEntry(c.Request.Context(), entry)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedEntry)
+}
+
+// applyPackageMealUpdate applies the changed fields of a package entry and recomputes it
+func (h *MealHandler) applyPackageMealUpdate(c *gin.Context, entry *models.MealEntry, req updateMealEntryRequest) error {
+	if req.customFields() {
+		return db.NewValidationError("entry", "Only custom entries accept name, mealType, calories and macros")
+	}
+	if req.Servings != nil {
+		return db.NewValidationError("servings", "Only food entries have servings; change portionMultiplier instead")
+	}
+	if req.PortionMultiplier != nil {
+		entry.PortionMultiplier = *req.PortionMultiplier
+	}
+
+	// The entry keeps the package version it was logged with unless the package changes
+	var pkg models.MealPackage
+	var err error
+	if req.PackageID != nil && *req.PackageID != entry.PackageID {
+		pkg, err = h.loggableMealPackage(c, *req.PackageID, req.ShareToken)
+	} else {
+		pkg, err = h.entryMealPackage(c, *entry)
+	}
+	if err != nil {
+		return err
+	}
+	entry.PackageID = pkg.ID
+	applyMealPackage(entry, pkg)
+	return nil
+}
+
+// applyFoodMealUpdate applies the changed fields of a food entry. A change of servings
+// rescales the serving stored on the entry; the food itself is not read again.
+func applyFoodMealUpdate(entry *models.MealEntry, req updateMealEntryRequest) error {
+	if req.PackageID != nil || req.PortionMultiplier != nil || req.Name != nil ||
+		req.Calories != nil || req.Protein != nil || req.Carbs != nil || req.Fat != nil {
+		return db.NewValidationError("entry", "Food entries accept servings, mealType and date")
+	}
+
+	if req.MealType != nil {
+		mealType, err := parseMealType(*req.MealType)
+		if err != nil {
+			return err
+		}
+		if mealType == "" {
+			return db.NewValidationError("mealType", "Must be one of BREAKFAST, LUNCH, DINNER, SNACK")
+		}
+		entry.MealType = mealType
+	}
+	if req.Servings != nil {
+		entry.PortionMultiplier = *req.Servings
+		applyServings(entry)
+	}
+	return nil
+}
+
+// applyCustomMealUpdate applies the changed fields of a custom entry and
+// checks the resulting calories against the macros
+func applyCustomMealUpdate(entry *models.MealEntry, req updateMealEntryRequest) error {
+	if req.PackageID != nil || req.PortionMultiplier != nil || req.Servings != nil {
+		return db.NewValidationError("entry", "Custom entries have no package or portion; change calories and macros instead")
+	}
+
+	if req.Name != nil {
+		entry.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.MealType != nil {
+		mealType, err := parseMealType(*req.MealType)
+		if err != nil {
+			return err
+		}
+		if mealType == "" {
+			return db.NewValidationError("mealType", "Must be one of BREAKFAST, LUNCH, DINNER, SNACK")
+		}
+		entry.MealType = mealType
+	}
+	if req.Calories != nil {
+		entry.Calories = *req.Calories
+	}
+	if req.Protein != nil {
+		entry.Protein = *req.Protein
+	}
+	if req.Carbs != nil {
+		entry.Carbs = *req.Carbs
+	}
+	if req.Fat != nil {
+		entry.Fat = *req.Fat
+	}
+
+	return checkCustomMeal(*entry)
+}
+
+// DeleteMealEntry godoc
+// @Summary      Delete a meal entry
+// @Description  Removes one of the authenticated user's meal entries
+// @Tags         meals
+// @Security     ApiKeyAuth
+// @Param        id   path  string  true  "Meal Entry ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /meals/entries/{id} [delete]
+func (h *MealHandler) DeleteMealEntry(c *gin.Context) {
+	entry, err := h.loadMealEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := h.store.DeleteMealEntry(c.Request.Context(), entry.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/analytics"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/jobs"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"github.com/zhenyili/BalanceLife/src/utils"
+)
+
+// MetricsHandler handles health metrics requests
+type MetricsHandler struct {
+	store db.Store
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(store db.Store) *MetricsHandler {
+	return &MetricsHandler{
+		store: store,
+	}
+}
+
+// RegisterRoutes registers metrics routes to the router
+func (h *MetricsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/users/:id/metrics", h.GetMetrics)
+	router.GET("/users/:id/metrics/history", h.GetMetricsHistory)
+	router.GET("/users/:id/tdee", h.GetAdaptiveTDEE)
+}
+
+// GetMetrics godoc
+// @Summary      Get current health metrics
+// @Description  Computes BMI with its WHO category, BMR, TDEE, the ideal weight range and recommended macro grams from the user's current profile
+// @Tags         metrics
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  models.UserInfo
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /users/{id}/metrics [get]
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nutrition.Metrics(user, time.Now()))
+}
+
+// GetMetricsHistory godoc
+// @Summary      Get health metrics history
+// @Description  Returns the health metrics snapshots stored whenever the user's profile or weight changed, oldest first
+// @Tags         metrics
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id         path      string  true   "User ID"
+// @Param        startDate  query     string  false  "Start date (YYYY-MM-DD), defaults to 90 days before endDate"
+// @Param        endDate    query     string  false  "End date (YYYY-MM-DD), defaults to today"
+// @Success      200        {array}   models.UserInfo
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
+// @Router       /users/{id}/metrics/history [get]
+func (h *MetricsHandler) GetMetricsHistory(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("endDate", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+
+	startDateStr := c.DefaultQuery("startDate", endDate.AddDate(0, 0, -90).Format("2006-01-02"))
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("startDate", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+
+	history, err := h.store.GetUserInfoHistory(c.Request.Context(), id, startDate, endDate.Add(24*time.Hour-time.Second))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// adaptiveTDEEResponse is a live adaptive TDEE estimate compared with the formula TDEE
+type adaptiveTDEEResponse struct {
+	models.TDEEEstimate
+	FormulaTDEE     int  `json:"formulaTdee" example:"2480"` // BMR times the activity factor
+	Usable          bool `json:"usable"`                     // Confident enough to replace the formula TDEE
+	AdaptiveTargets bool `json:"adaptiveTargets"`            // Whether the user opted in to adaptive targets
+}
+
+// GetAdaptiveTDEE godoc
+// @Summary      Get the adaptive TDEE estimate
+// @Description  Estimates the user's actual energy expenditure from logged meal calories and the trend of their weight entries over the days before today, with a confidence score from 0 to 1. A background job refreshes the stored estimate and, for users with adaptiveTargets on, recalculates AUTO targets from it.
+// @Tags         metrics
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string  true   "User ID"
+// @Param        days  query     int     false  "Window length in days (14-28), defaults to 28"
+// @Success      200   {object}  adaptiveTDEEResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /users/{id}/tdee [get]
+func (h *MetricsHandler) GetAdaptiveTDEE(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	days := analytics.DefaultTDEEWindowDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < analytics.MinTDEEWindowDays || days > analytics.MaxTDEEWindowDays {
+			c.Error(db.NewValidationError("days", "Must be a number of days between 14 and 28"))
+			return
+		}
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	estimate, err := jobs.EstimateTDEE(c.Request.Context(), h.store, user, days, now)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := adaptiveTDEEResponse{
+		TDEEEstimate:    estimate,
+		Usable:          nutrition.UsableEstimate(&estimate, now),
+		AdaptiveTargets: user.AdaptiveTargets,
+	}
+
+	// The formula TDEE ignores any stored estimate
+	user.AdaptiveTargets = false
+	response.FormulaTDEE = nutrition.CalculateTargets(user, now).TDEE
+
+	c.JSON(http.StatusOK, response)
+}
+
+// recordMetrics stores a health metrics snapshot after a profile change.
+// The change itself is already saved, so a failure is logged rather than returned.
+func recordMetrics(ctx context.Context, store db.Store, user models.User) {
+	info := nutrition.Metrics(user, time.Now())
+	info.ID = utils.GenerateID()
+
+	if _, err := store.CreateUserInfo(ctx, info); err != nil {
+		log.Printf("Failed to record metrics for user %s: %v", user.ID, err)
+	}
+}
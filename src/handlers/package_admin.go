@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"github.com/zhenyili/BalanceLife/src/recipes"
+)
+
+// mealPackageRequest defines the fields of a meal package
+type mealPackageRequest struct {
+	Name             string              `json:"name" binding:"required,max=100" example:"Protein Breakfast Bowl"`
+	Description      string              `json:"description" binding:"max=500" example:"Greek yogurt topped with berries and low-sugar granola"`
+	GoalType         string              `json:"goalType" binding:"required" example:"LOSE" enums:"LOSE,GAIN,BOTH"`
+	MealType         string              `json:"mealType" binding:"required" example:"BREAKFAST" enums:"BREAKFAST,LUNCH,DINNER,SNACK"`
+	BaseCalories     int                 `json:"baseCalories" binding:"min=0,max=5000" example:"300"` // Required without a recipe; must be within 15% of the calories implied by the macros
+	BaseProtein      int                 `json:"baseProtein" binding:"min=0,max=500" example:"25"`
+	BaseCarbs        int                 `json:"baseCarbs" binding:"min=0,max=1000" example:"30"`
+	BaseFat          int                 `json:"baseFat" binding:"min=0,max=500" example:"7"`
+	ImageURL         string              `json:"imageUrl" binding:"omitempty,url" example:"https://example.com/bowl.jpg"`
+	PreparationSteps []string            `json:"preparationSteps"`
+	Ingredients      []string            `json:"ingredients"`
+	Recipe           []recipeItemRequest `json:"recipe" binding:"omitempty,max=50,dive"`                   // When given, calories, macros and ingredients are computed from it
+	Visibility       string              `json:"visibility" example:"PRIVATE" enums:"PRIVATE,LINK,PUBLIC"` // Only for packages owned by a user; defaults to PRIVATE
+}
+
+// recipeItemRequest defines an amount of one food in a recipe
+type recipeItemRequest struct {
+	FoodID string  `json:"foodId" binding:"required" example:"chicken-breast"`
+	Grams  float64 `json:"grams" binding:"required,gt=0,max=5000" example:"150"`
+}
+
+// toRecipe converts recipe items from a request
+func toRecipe(items []recipeItemRequest) []models.RecipeItem {
+	if len(items) == 0 {
+		return nil
+	}
+	recipe := make([]models.RecipeItem, 0, len(items))
+	for _, item := range items {
+		recipe = append(recipe, models.RecipeItem(item))
+	}
+	return recipe
+}
+
+// createMealPackageRequest defines the structure for meal package creation
+type createMealPackageRequest struct {
+	PackageID string `json:"packageId" binding:"omitempty,max=64" example:"meal8"` // Generated when omitted
+	mealPackageRequest
+}
+
+// updateMealPackageRequest replaces every field of a meal package
+type updateMealPackageRequest struct {
+	Version *int `json:"version" binding:"required,min=0" example:"1"` // The version being replaced
+	mealPackageRequest
+}
+
+// toPackage validates the request and builds the package it describes.
+// A package with a recipe still needs recipes.Apply to compute its values.
+func (r mealPackageRequest) toPackage(id string) (models.MealPackage, error) {
+	goalType, err := parsePackageGoalType(r.GoalType)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+	mealType, err := parseMealType(r.MealType)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+	if len(r.Recipe) == 0 {
+		if r.BaseCalories < 1 {
+			return models.MealPackage{}, db.NewValidationError("baseCalories", "Required unless the package has a recipe")
+		}
+		if err := nutrition.CheckMacroCalories(r.BaseCalories, r.BaseProtein, r.BaseCarbs, r.BaseFat); err != nil {
+			return models.MealPackage{}, db.NewValidationError("baseCalories", err.Error())
+		}
+	}
+
+	return models.MealPackage{
+		ID:               id,
+		Name:             strings.TrimSpace(r.Name),
+		Description:      r.Description,
+		GoalType:         goalType,
+		MealType:         mealType,
+		BaseCalories:     r.BaseCalories,
+		BaseProtein:      r.BaseProtein,
+		BaseCarbs:        r.BaseCarbs,
+		BaseFat:          r.BaseFat,
+		ImageURL:         r.ImageURL,
+		PreparationSteps: r.PreparationSteps,
+		Ingredients:      r.Ingredients,
+		Recipe:           toRecipe(r.Recipe),
+	}, nil
+}
+
+// CreateMealPackage godoc
+// @Summary      Create a meal package
+// @Description  Adds a meal package to the catalog at version 1. Calories must agree with the macros within 15%, unless the package has a recipe of foods, from which calories, macros and ingredients are computed. Requires the ADMIN role.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        package  body      createMealPackageRequest  true  "Meal package"
+// @Success      201      {object}  models.MealPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /meals/packages [post]
+func (h *MealHandler) CreateMealPackage(c *gin.Context) {
+	var req createMealPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	pkg, err := req.toPackage(req.PackageID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if _, _, err := packageVisibility("", req.Visibility, ""); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := recipes.Apply(c.Request.Context(), h.store, &pkg); err != nil {
+		c.Error(referenceError("recipe", err))
+		return
+	}
+
+	created, err := h.store.CreateMealPackage(c.Request.Context(), pkg)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateMealPackage godoc
+// @Summary      Update a meal package
+// @Description  Replaces a meal package with a new version. The request names the version it replaces and fails with 409 if another update came first. Entries logged with earlier versions keep their values. Catalog packages require the ADMIN role; users may update their own packages and change their visibility.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                    true  "Meal Package ID"
+// @Param        package  body      updateMealPackageRequest  true  "Meal package"
+// @Success      200      {object}  models.MealPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /meals/packages/{id} [put]
+func (h *MealHandler) UpdateMealPackage(c *gin.Context) {
+	var req updateMealPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	current, err := h.viewableMealPackage(c, c.Param("id"), "")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := authorizePackageWrite(c, current.OwnerID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := req.toPackage(current.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	pkg.Version = *req.Version
+	pkg.OwnerID = current.OwnerID
+	if pkg.Visibility, pkg.ShareToken, err = packageVisibility(current.OwnerID, req.Visibility, current.ShareToken); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := recipes.Apply(c.Request.Context(), h.store, &pkg); err != nil {
+		c.Error(referenceError("recipe", err))
+		return
+	}
+
+	updated, err := h.store.UpdateMealPackage(c.Request.Context(), pkg)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// ArchiveMealPackage godoc
+// @Summary      Archive a meal package
+// @Description  Hides a meal package from lists and stops new entries from using it. Existing entries and versions are kept. Catalog packages require the ADMIN role; users may archive their own packages.
+// @Tags         meals
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Meal Package ID"
+// @Success      200  {object}  models.MealPackage
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /meals/packages/{id} [delete]
+func (h *MealHandler) ArchiveMealPackage(c *gin.Context) {
+	h.setMealPackageArchived(c, true)
+}
+
+// RestoreMealPackage godoc
+// @Summary      Restore an archived meal package
+// @Description  Makes an archived meal package available again. Catalog packages require the ADMIN role; users may restore their own packages.
+// @Tags         meals
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Meal Package ID"
+// @Success      200  {object}  models.MealPackage
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /meals/packages/{id}/restore [post]
+func (h *MealHandler) RestoreMealPackage(c *gin.Context) {
+	h.setMealPackageArchived(c, false)
+}
+
+// setMealPackageArchived archives or restores the package named in the path
+func (h *MealHandler) setMealPackageArchived(c *gin.Context, archived bool) {
+	current, err := h.viewableMealPackage(c, c.Param("id"), "")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := authorizePackageWrite(c, current.OwnerID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := h.store.ArchiveMealPackage(c.Request.Context(), current.ID, archived)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// GetMealPackageVersion godoc
+// @Summary      Get a version of a meal package
+// @Description  Returns a meal package as it was at the given version, such as the version a meal entry was logged with
+// @Tags         meals
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string  true   "Meal Package ID"
+// @Param        version  path      int     true   "Package version"
+// @Param        token    query     string  false  "Share token of a package shared by link"
+// @Success      200      {object}  models.MealPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /meals/packages/{id}/versions/{version} [get]
+func (h *MealHandler) GetMealPackageVersion(c *gin.Context) {
+	version, err := parsePackageVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	current, err := h.viewableMealPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := h.store.GetMealPackageVersion(c.Request.Context(), current.ID, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !ownsPackage(c, pkg.OwnerID) {
+		pkg.ShareToken = ""
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// workoutPackageRequest defines the fields of a workout package
+type workoutPackageRequest struct {
+	Name                string   `json:"name" binding:"required,max=100" example:"HIIT Fat Burner"`
+	Description         string   `json:"description" binding:"max=500" example:"High-intensity interval training to maximize calorie burn"`
+	GoalType            string   `json:"goalType" binding:"required" example:"LOSE" enums:"LOSE,GAIN,BOTH"`
+	WorkoutType         string   `json:"workoutType" binding:"required,max=30" example:"HIIT"`
+	BaseDurationMinutes int      `json:"baseDurationMinutes" binding:"required,min=1,max=600" example:"30"`
+	BaseCaloriesBurn    int      `json:"baseCaloriesBurn" binding:"required,min=1,max=5000" example:"300"`
+	CaloriesBurnFormula string   `json:"caloriesBurnFormula" binding:"max=200"` // Defaults to the standard formula
+	ImageURL            string   `json:"imageUrl" binding:"omitempty,url" example:"https://example.com/hiit.jpg"`
+	Instructions        []string `json:"instructions"`
+	Visibility          string   `json:"visibility" example:"PRIVATE" enums:"PRIVATE,LINK,PUBLIC"` // Only for packages owned by a user; defaults to PRIVATE
+}
+
+// createWorkoutPackageRequest defines the structure for workout package creation
+type createWorkoutPackageRequest struct {
+	PackageID string `json:"packageId" binding:"omitempty,max=64" example:"workout4"` // Generated when omitted
+	workoutPackageRequest
+}
+
+// updateWorkoutPackageRequest replaces every field of a workout package
+type updateWorkoutPackageRequest struct {
+	Version *int `json:"version" binding:"required,min=0" example:"1"` // The version being replaced
+	workoutPackageRequest
+}
+
+// toPackage validates the request and builds the package it describes
+func (r workoutPackageRequest) toPackage(id string) (models.WorkoutPackage, error) {
+	goalType, err := parsePackageGoalType(r.GoalType)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+
+	formula := r.CaloriesBurnFormula
+	if formula == "" {
+		formula = models.DefaultCaloriesBurnFormula
+	}
+
+	return models.WorkoutPackage{
+		ID:                  id,
+		Name:                strings.TrimSpace(r.Name),
+		Description:         r.Description,
+		GoalType:            goalType,
+		WorkoutType:         strings.ToUpper(r.WorkoutType),
+		BaseDurationMinutes: r.BaseDurationMinutes,
+		BaseCaloriesBurn:    r.BaseCaloriesBurn,
+		CaloriesBurnFormula: formula,
+		ImageURL:            r.ImageURL,
+		Instructions:        r.Instructions,
+	}, nil
+}
+
+// CreateWorkoutPackage godoc
+// @Summary      Create a workout package
+// @Description  Adds a workout package to the catalog at version 1. Requires the ADMIN role.
+// @Tags         workouts
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        package  body      createWorkoutPackageRequest  true  "Workout package"
+// @Success      201      {object}  models.WorkoutPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /workouts/packages [post]
+func (h *WorkoutHandler) CreateWorkoutPackage(c *gin.Context) {
+	var req createWorkoutPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	pkg, err := req.toPackage(req.PackageID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if _, _, err := packageVisibility("", req.Visibility, ""); err != nil {
+		c.Error(err)
+		return
+	}
+
+	created, err := h.store.CreateWorkoutPackage(c.Request.Context(), pkg)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateWorkoutPackage godoc
+// @Summary      Update a workout package
+// @Description  Replaces a workout package with a new version. The request names the version it replaces and fails with 409 if another update came first. Entries logged with earlier versions keep their values. Catalog packages require the ADMIN role; users may update their own packages and change their visibility.
+// @Tags         workouts
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                       true  "Workout Package ID"
+// @Param        package  body      updateWorkoutPackageRequest  true  "Workout package"
+// @Success      200      {object}  models.WorkoutPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /workouts/packages/{id} [put]
+func (h *WorkoutHandler) UpdateWorkoutPackage(c *gin.Context) {
+	var req updateWorkoutPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	current, err := h.viewableWorkoutPackage(c, c.Param("id"), "")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := authorizePackageWrite(c, current.OwnerID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := req.toPackage(current.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	pkg.Version = *req.Version
+	pkg.OwnerID = current.OwnerID
+	if pkg.Visibility, pkg.ShareToken, err = packageVisibility(current.OwnerID, req.Visibility, current.ShareToken); err != nil {
+		c.Error(err)
+		return
+	}
+
+	updated, err := h.store.UpdateWorkoutPackage(c.Request.Context(), pkg)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// ArchiveWorkoutPackage godoc
+// @Summary      Archive a workout package
+// @Description  Hides a workout package from lists and stops new entries from using it. Existing entries and versions are kept. Catalog packages require the ADMIN role; users may archive their own packages.
+// @Tags         workouts
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Workout Package ID"
+// @Success      200  {object}  models.WorkoutPackage
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /workouts/packages/{id} [delete]
+func (h *WorkoutHandler) ArchiveWorkoutPackage(c *gin.Context) {
+	h.setWorkoutPackageArchived(c, true)
+}
+
+// RestoreWorkoutPackage godoc
+// @Summary      Restore an archived workout package
+// @Description  Makes an archived workout package available again. Catalog packages require the ADMIN role; users may restore their own packages.
+// @Tags         workouts
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Workout Package ID"
+// @Success      200  {object}  models.WorkoutPackage
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /workouts/packages/{id}/restore [post]
+func (h *WorkoutHandler) RestoreWorkoutPackage(c *gin.Context) {
+	h.setWorkoutPackageArchived(c, false)
+}
+
+// setWorkoutPackageArchived archives or restores the package named in the path
+func (h *WorkoutHandler) setWorkoutPackageArchived(c *gin.Context, archived bool) {
+	current, err := h.viewableWorkoutPackage(c, c.Param("id"), "")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := authorizePackageWrite(c, current.OwnerID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := h.store.ArchiveWorkoutPackage(c.Request.Context(), current.ID, archived)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// GetWorkoutPackageVersion godoc
+// @Summary      Get a version of a workout package
+// @Description  Returns a workout package as it was at the given version, such as the version a workout entry was logged with
+// @Tags         workouts
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string  true   "Workout Package ID"
+// @Param        version  path      int     true   "Package version"
+// @Param        token    query     string  false  "Share token of a package shared by link"
+// @Success      200      {object}  models.WorkoutPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /workouts/packages/{id}/versions/{version} [get]
+func (h *WorkoutHandler) GetWorkoutPackageVersion(c *gin.Context) {
+	version, err := parsePackageVersion(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	current, err := h.viewableWorkoutPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkg, err := h.store.GetWorkoutPackageVersion(c.Request.Context(), current.ID, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !ownsPackage(c, pkg.OwnerID) {
+		pkg.ShareToken = ""
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// parsePackageGoalType validates the goal type of a package
+func parsePackageGoalType(value string) (models.GoalType, error) {
+	goalType := models.GoalType(strings.ToUpper(value))
+	switch goalType {
+	case models.GoalTypeLose, models.GoalTypeGain, models.GoalTypeBoth:
+		return goalType, nil
+	}
+	return "", db.NewValidationError("goalType", "Must be one of LOSE, GAIN, BOTH")
+}
+
+// parsePackageVersion reads the version path parameter
+func parsePackageVersion(c *gin.Context) (int, error) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		return 0, db.NewValidationError("version", "Must be a positive whole number")
+	}
+	return version, nil
+}
+
+// archivedPackageError rejects logging a new entry against an archived package
+func archivedPackageError(id string) error {
+	return db.NewValidationError("packageId", "Package "+id+" is archived")
+}
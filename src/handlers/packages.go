@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// packageListParams are the query parameters shared by the package list endpoints
+type packageListParams struct {
+	GoalType        models.GoalType
+	Search          string
+	Scope           db.PackageScope
+	IncludeArchived bool
+	Sort            db.PackageSort
+	Descending      bool
+	Cursor          string
+	Limit           int
+}
+
+// parsePackageListParams reads the goal type, search, scope, archive, sort and pagination parameters.
+// The sort key itself is validated by the store, which knows which keys apply.
+func parsePackageListParams(c *gin.Context) (packageListParams, error) {
+	params := packageListParams{
+		Search: strings.TrimSpace(c.Query("q")),
+		Cursor: c.Query("cursor"),
+	}
+
+	switch goalType := strings.ToUpper(c.Query("goalType")); goalType {
+	case "", "ALL":
+		params.GoalType = models.GoalTypeAll
+	case string(models.GoalTypeLose), string(models.GoalTypeGain), string(models.GoalTypeBoth):
+		params.GoalType = models.GoalType(goalType)
+	default:
+		return packageListParams{}, db.NewValidationError("goalType", "Must be one of LOSE, GAIN, BOTH, ALL")
+	}
+
+	switch scope := db.PackageScope(strings.ToLower(c.Query("scope"))); scope {
+	case "", db.ScopeAll, db.ScopeCatalog, db.ScopeMine:
+		params.Scope = scope
+	default:
+		return packageListParams{}, db.NewValidationError("scope", "Must be one of all, catalog, mine")
+	}
+
+	// Only admins see archived packages in lists
+	if includeStr := c.Query("includeArchived"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			return packageListParams{}, db.NewValidationError("includeArchived", "Must be true or false")
+		}
+		if include && currentRole(c) != models.RoleAdmin {
+			return packageListParams{}, forbidden("includeArchived requires " + string(models.RoleAdmin) + " role")
+		}
+		params.IncludeArchived = include
+	}
+
+	// A leading "-" sorts in descending order
+	sortKey := c.Query("sort")
+	if strings.HasPrefix(sortKey, "-") {
+		params.Descending = true
+		sortKey = sortKey[1:]
+	}
+	params.Sort = db.PackageSort(sortKey)
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > db.MaxPageSize {
+			return packageListParams{}, db.NewValidationError("limit", "Must be a number between 1 and 100")
+		}
+		params.Limit = limit
+	}
+
+	return params, nil
+}
+
+// parseRange reads the optional min<name> and max<name> query parameters,
+// e.g. minCalories and maxCalories for name "Calories"
+func parseRange(c *gin.Context, name string) (db.Range, error) {
+	var r db.Range
+
+	for _, bound := range []struct {
+		param string
+		dest  **int
+	}{
+		{"min" + name, &r.Min},
+		{"max" + name, &r.Max},
+	} {
+		valueStr := c.Query(bound.param)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value < 0 {
+			return db.Range{}, db.NewValidationError(bound.param, "Must be a non-negative whole number")
+		}
+		*bound.dest = &value
+	}
+
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return db.Range{}, db.NewValidationError("max"+name, "Must not be less than min"+name)
+	}
+	return r, nil
+}
+
+// parseMealType validates an optional meal type filter
+func parseMealType(value string) (models.MealType, error) {
+	mealType := models.MealType(strings.ToUpper(value))
+	switch mealType {
+	case "", models.MealTypeBreakfast, models.MealTypeLunch, models.MealTypeDinner, models.MealTypeSnack:
+		return mealType, nil
+	}
+	return "", db.NewValidationError("mealType", "Must be one of BREAKFAST, LUNCH, DINNER, SNACK")
+}
+
+// callerGoal returns the authenticated user's current goal, used to mark
+// the packages that suit it
+func callerGoal(c *gin.Context, store db.Store) (models.GoalType, error) {
+	user, err := store.GetUser(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		return "", err
+	}
+	return user.Goal.Type, nil
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/analytics"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// SummaryHandler handles calorie balance dashboard requests
+type SummaryHandler struct {
+	store db.Store
+}
+
+// NewSummaryHandler creates a new summary handler
+func NewSummaryHandler(store db.Store) *SummaryHandler {
+	return &SummaryHandler{
+		store: store,
+	}
+}
+
+// RegisterRoutes registers summary routes to the router
+func (h *SummaryHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/users/:id/summary", h.GetDailySummary)
+	router.GET("/users/:id/trends", h.GetTrends)
+	router.GET("/users/:id/projection", h.GetProjection)
+}
+
+// maxTrendDays bounds the range a single trend request may cover
+const maxTrendDays = 366
+
+// GetDailySummary godoc
+// @Summary      Get the daily calorie balance
+// @Description  Returns calorie and macro targets, consumption, burn, net balance and progress toward the goal for one day
+// @Tags         summary
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string  true   "User ID"
+// @Param        date  query     string  false  "Day to summarize (YYYY-MM-DD), defaults to today"
+// @Success      200   {object}  models.DailySummary
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /users/{id}/summary [get]
+func (h *SummaryHandler) GetDailySummary(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.store.GetUser(ctx, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Entries are dated at midnight, so the day spans [date, date + 24h)
+	endOfDay := date.Add(24*time.Hour - time.Second)
+
+	meals, err := h.store.GetMealEntriesByUserAndDateRange(ctx, id, date, endOfDay)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	workouts, err := h.store.GetWorkoutEntriesByUserAndDateRange(ctx, id, date, endOfDay)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	summary := analytics.DailySummary(user, date, meals, workouts)
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetTrends godoc
+// @Summary      Get calorie balance trends
+// @Description  Returns a per-day series of intake, burn, net balance and macros for a date range, with days without logs filled in, plus seven-day aggregates and the protein/carb/fat calorie split. Defaults to the last seven days.
+// @Tags         summary
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id         path      string  true   "User ID"
+// @Param        startDate  query     string  false  "Start date (YYYY-MM-DD), defaults to six days before endDate"
+// @Param        endDate    query     string  false  "End date (YYYY-MM-DD), defaults to today"
+// @Success      200        {object}  models.TrendReport
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
+// @Router       /users/{id}/trends [get]
+func (h *SummaryHandler) GetTrends(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("endDate", "Invalid end date format, use YYYY-MM-DD"))
+		return
+	}
+
+	startDateStr := c.DefaultQuery("startDate", endDate.AddDate(0, 0, -6).Format("2006-01-02"))
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("startDate", "Invalid start date format, use YYYY-MM-DD"))
+		return
+	}
+
+	if endDate.Before(startDate) {
+		c.Error(db.NewValidationError("endDate", "End date must not be before start date"))
+		return
+	}
+	if endDate.Sub(startDate) >= maxTrendDays*24*time.Hour {
+		c.Error(db.NewValidationError("startDate", fmt.Sprintf("Date range must not exceed %d days", maxTrendDays)))
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.store.GetUser(ctx, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	totals, err := h.store.GetDailyTotals(ctx, id, startDate, endDate.Add(24*time.Hour-time.Second))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	report := analytics.Trend(user, startDate, endDate, totals)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetProjection godoc
+// @Summary      Project the goal timeline
+// @Description  Estimates when the user reaches goal.targetWeight from their current weight and average daily intake against TDEE over recently logged days (workout burn is not subtracted, since TDEE already includes activity), falling back to the planned calorie target when fewer than 7 days are logged. Returns the projected date, a weekly weight curve and warnings when the rate of change exceeds safe limits (losing more than 1% or gaining more than 0.5% of body weight per week).
+// @Tags         summary
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id             path      string  true   "User ID"
+// @Param        days           query     int     false  "Days of logs to average (7-90), defaults to 28"
+// @Param        energyDensity  query     int     false  "kcal per kg of weight change (3000-10000), defaults to 7700"
+// @Success      200            {object}  models.GoalProjection
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Failure      503            {object}  ErrorResponse
+// @Router       /users/{id}/projection [get]
+func (h *SummaryHandler) GetProjection(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	days := analytics.DefaultProjectionWindowDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < analytics.MinProjectionWindowDays || days > analytics.MaxProjectionWindowDays {
+			c.Error(db.NewValidationError("days", "Must be a number of days between 7 and 90"))
+			return
+		}
+	}
+
+	energyDensity := nutrition.EnergyPerKg
+	if densityStr := c.Query("energyDensity"); densityStr != "" {
+		var err error
+		energyDensity, err = strconv.Atoi(densityStr)
+		if err != nil || energyDensity < 3000 || energyDensity > 10000 {
+			c.Error(db.NewValidationError("energyDensity", "Must be a number of kcal per kg between 3000 and 10000"))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.store.GetUser(ctx, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if user.Goal.TargetWeight == 0 {
+		c.Error(db.NewValidationError("targetWeight", "Set a target weight on the profile to get a projection"))
+		return
+	}
+
+	// Start from the latest weigh-in, as weight progress does, falling back to the profile weight
+	currentWeight := user.Weight
+	if entry, err := h.store.GetLatestWeightEntry(ctx, id); err == nil {
+		currentWeight = entry.Weight
+	} else if !errors.Is(err, db.ErrNotFound) {
+		c.Error(err)
+		return
+	}
+	if currentWeight <= 0 {
+		c.Error(db.NewValidationError("weight", "Log your weight to get a projection"))
+		return
+	}
+
+	// Average the days before today, since today's meals are usually not all logged yet
+	now := time.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	totals, err := h.store.GetDailyTotals(ctx, id, today.AddDate(0, 0, -days), today.Add(-time.Second))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	projection := analytics.Project(user, currentWeight, days, totals, energyDensity, today)
+	c.JSON(http.StatusOK, projection)
+}
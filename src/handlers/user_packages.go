@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"github.com/zhenyili/BalanceLife/src/recipes"
+	"github.com/zhenyili/BalanceLife/src/utils"
+)
+
+// canViewPackage reports whether the caller may see a package. Catalog and public
+// packages are visible to everyone, private packages only to their owner, and
+// packages shared by link to anyone presenting their share token. Admins see all.
+func canViewPackage(c *gin.Context, ownerID string, visibility models.PackageVisibility, shareToken, token string) bool {
+	switch {
+	case ownerID == "", ownerID == currentUserID(c), currentRole(c) == models.RoleAdmin:
+		return true
+	case visibility == models.VisibilityPublic:
+		return true
+	case visibility == models.VisibilityLink:
+		return shareToken != "" && subtle.ConstantTimeCompare([]byte(shareToken), []byte(token)) == 1
+	}
+	return false
+}
+
+// hiddenPackageError reports a package the caller may not see as missing, so that
+// private package IDs cannot be probed
+func hiddenPackageError(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, db.ErrNotFound)
+}
+
+// authorizePackageWrite allows changing catalog packages only to admins, and
+// user packages only to their owner or an admin
+func authorizePackageWrite(c *gin.Context, ownerID string) error {
+	if ownerID == "" {
+		if currentRole(c) != models.RoleAdmin {
+			return forbidden("requires " + string(models.RoleAdmin) + " role")
+		}
+		return nil
+	}
+	if err := authorizeUser(c, ownerID); err != nil {
+		return forbidden("cannot change another user's package")
+	}
+	return nil
+}
+
+// ownsPackage reports whether the caller owns a package or is an admin, and so may see its share token
+func ownsPackage(c *gin.Context, ownerID string) bool {
+	return ownerID != "" && ownerID == currentUserID(c) || currentRole(c) == models.RoleAdmin
+}
+
+// parseVisibility validates the visibility of a user package. Empty defaults to PRIVATE.
+func parseVisibility(value string) (models.PackageVisibility, error) {
+	if value == "" {
+		return models.VisibilityPrivate, nil
+	}
+	visibility := models.PackageVisibility(strings.ToUpper(value))
+	if !visibility.Valid() {
+		return "", db.NewValidationError("visibility", "Must be one of PRIVATE, LINK, PUBLIC")
+	}
+	return visibility, nil
+}
+
+// packageVisibility resolves the visibility and share token a package gets from a request.
+// Catalog packages have neither. A user package shared by link keeps its token; any
+// other visibility drops it, so that switching back to LINK revokes old links.
+func packageVisibility(ownerID, requested, currentToken string) (models.PackageVisibility, string, error) {
+	if ownerID == "" {
+		if requested != "" {
+			return "", "", db.NewValidationError("visibility", "Catalog packages are visible to everyone")
+		}
+		return "", "", nil
+	}
+
+	visibility, err := parseVisibility(requested)
+	if err != nil {
+		return "", "", err
+	}
+	if visibility != models.VisibilityLink {
+		return visibility, "", nil
+	}
+	if currentToken == "" {
+		currentToken = utils.GenerateToken()
+	}
+	return visibility, currentToken, nil
+}
+
+// viewableMealPackage fetches a meal package the caller may see, using the share token in token
+func (h *MealHandler) viewableMealPackage(c *gin.Context, id, token string) (models.MealPackage, error) {
+	pkg, err := h.store.GetMealPackage(c.Request.Context(), id)
+	if err != nil {
+		return models.MealPackage{}, err
+	}
+	if !canViewPackage(c, pkg.OwnerID, pkg.Visibility, pkg.ShareToken, token) {
+		return models.MealPackage{}, hiddenPackageError("meal package", id)
+	}
+	if !ownsPackage(c, pkg.OwnerID) {
+		pkg.ShareToken = ""
+	}
+	return pkg, nil
+}
+
+// viewableWorkoutPackage fetches a workout package the caller may see, using the share token in token
+func (h *WorkoutHandler) viewableWorkoutPackage(c *gin.Context, id, token string) (models.WorkoutPackage, error) {
+	pkg, err := h.store.GetWorkoutPackage(c.Request.Context(), id)
+	if err != nil {
+		return models.WorkoutPackage{}, err
+	}
+	if !canViewPackage(c, pkg.OwnerID, pkg.Visibility, pkg.ShareToken, token) {
+		return models.WorkoutPackage{}, hiddenPackageError("workout package", id)
+	}
+	if !ownsPackage(c, pkg.OwnerID) {
+		pkg.ShareToken = ""
+	}
+	return pkg, nil
+}
+
+// promoteMealEntryRequest defines the package made from a custom meal entry.
+// The meal type, calories and macros come from the entry.
+type promoteMealEntryRequest struct {
+	Name        string   `json:"name" binding:"max=100" example:"Homemade lasagna"` // Defaults to the entry's name
+	Description string   `json:"description" binding:"max=500" example:"Grandma's recipe, one slice"`
+	GoalType    string   `json:"goalType" example:"BOTH" enums:"LOSE,GAIN,BOTH"`           // Defaults to BOTH
+	Visibility  string   `json:"visibility" example:"PRIVATE" enums:"PRIVATE,LINK,PUBLIC"` // Defaults to PRIVATE
+	Ingredients []string `json:"ingredients"`
+}
+
+// PromoteMealEntry godoc
+// @Summary      Save a custom meal entry as a package
+// @Description  Creates a meal package owned by the entry's user from a custom entry's name, meal type, calories and macros, so that the meal can be logged again like any package. The package is private unless another visibility is given; LINK packages get a shareToken that others pass to view, clone or log them.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                   true  "Meal Entry ID"
+// @Param        package  body      promoteMealEntryRequest  true  "Package details"
+// @Success      201      {object}  models.MealPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /meals/entries/{id}/promote [post]
+func (h *MealHandler) PromoteMealEntry(c *gin.Context) {
+	var req promoteMealEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	entry, err := h.loadMealEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !entry.Custom {
+		c.Error(db.NewValidationError("entry", "Only custom entries can be saved as packages; clone the entry's package instead"))
+		return
+	}
+
+	goalType := models.GoalTypeBoth
+	if req.GoalType != "" {
+		if goalType, err = parsePackageGoalType(req.GoalType); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = entry.Name
+	}
+
+	pkg := models.MealPackage{
+		ID:           utils.GenerateID(),
+		Name:         name,
+		Description:  req.Description,
+		GoalType:     goalType,
+		MealType:     entry.MealType,
+		BaseCalories: entry.Calories,
+		BaseProtein:  entry.Protein,
+		BaseCarbs:    entry.Carbs,
+		BaseFat:      entry.Fat,
+		Ingredients:  req.Ingredients,
+		OwnerID:      entry.UserID,
+	}
+	if pkg.Visibility, pkg.ShareToken, err = packageVisibility(pkg.OwnerID, req.Visibility, ""); err != nil {
+		c.Error(err)
+		return
+	}
+
+	created, err := h.store.CreateMealPackage(c.Request.Context(), pkg)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// cloneMealPackageRequest defines the changes made to a cloned meal package.
+// Omitted fields keep the source package's values.
+type cloneMealPackageRequest struct {
+	Name             *string             `json:"name" binding:"omitempty,min=1,max=100" example:"Lighter Protein Bowl"`
+	Description      *string             `json:"description" binding:"omitempty,max=500"`
+	GoalType         *string             `json:"goalType" example:"LOSE" enums:"LOSE,GAIN,BOTH"`
+	MealType         *string             `json:"mealType" example:"BREAKFAST" enums:"BREAKFAST,LUNCH,DINNER,SNACK"`
+	BaseCalories     *int                `json:"baseCalories" binding:"omitempty,min=1,max=5000" example:"250"` // Without a recipe, the result must be within 15% of the calories implied by the macros
+	BaseProtein      *int                `json:"baseProtein" binding:"omitempty,min=0,max=500" example:"25"`
+	BaseCarbs        *int                `json:"baseCarbs" binding:"omitempty,min=0,max=1000" example:"20"`
+	BaseFat          *int                `json:"baseFat" binding:"omitempty,min=0,max=500" example:"5"`
+	ImageURL         *string             `json:"imageUrl" binding:"omitempty,url"`
+	PreparationSteps []string            `json:"preparationSteps"`
+	Ingredients      []string            `json:"ingredients"`
+	Recipe           []recipeItemRequest `json:"recipe" binding:"omitempty,max=50,dive"`                   // Replaces the recipe; an empty list removes it
+	Visibility       string              `json:"visibility" example:"PRIVATE" enums:"PRIVATE,LINK,PUBLIC"` // Defaults to PRIVATE
+}
+
+// macros reports whether the request changes calories or macros
+func (r cloneMealPackageRequest) macros() bool {
+	return r.BaseCalories != nil || r.BaseProtein != nil || r.BaseCarbs != nil || r.BaseFat != nil
+}
+
+// apply makes the requested changes to a copy of the source package.
+// A package with a recipe still needs recipes.Apply to compute its values.
+func (r cloneMealPackageRequest) apply(pkg *models.MealPackage) error {
+	if r.Recipe != nil {
+		pkg.Recipe = toRecipe(r.Recipe)
+	}
+	if len(pkg.Recipe) > 0 && r.macros() {
+		return db.NewValidationError("recipe", "Calories and macros are computed from the recipe; change the recipe or remove it with an empty list")
+	}
+
+	if r.Name != nil {
+		pkg.Name = strings.TrimSpace(*r.Name)
+	}
+	if r.Description != nil {
+		pkg.Description = *r.Description
+	}
+	if r.GoalType != nil {
+		goalType, err := parsePackageGoalType(*r.GoalType)
+		if err != nil {
+			return err
+		}
+		pkg.GoalType = goalType
+	}
+	if r.MealType != nil {
+		mealType, err := parseMealType(*r.MealType)
+		if err != nil {
+			return err
+		}
+		if mealType == "" {
+			return db.NewValidationError("mealType", "Must be one of BREAKFAST, LUNCH, DINNER, SNACK")
+		}
+		pkg.MealType = mealType
+	}
+	if r.BaseCalories != nil {
+		pkg.BaseCalories = *r.BaseCalories
+	}
+	if r.BaseProtein != nil {
+		pkg.BaseProtein = *r.BaseProtein
+	}
+	if r.BaseCarbs != nil {
+		pkg.BaseCarbs = *r.BaseCarbs
+	}
+	if r.BaseFat != nil {
+		pkg.BaseFat = *r.BaseFat
+	}
+	if r.ImageURL != nil {
+		pkg.ImageURL = *r.ImageURL
+	}
+	if r.PreparationSteps != nil {
+		pkg.PreparationSteps = r.PreparationSteps
+	}
+	if r.Ingredients != nil {
+		pkg.Ingredients = r.Ingredients
+	}
+	if len(pkg.Recipe) > 0 {
+		return nil
+	}
+
+	if err := nutrition.CheckMacroCalories(pkg.BaseCalories, pkg.BaseProtein, pkg.BaseCarbs, pkg.BaseFat); err != nil {
+		return db.NewValidationError("baseCalories", err.Error())
+	}
+	return nil
+}
+
+// CloneMealPackage godoc
+// @Summary      Clone a meal package
+// @Description  Copies a catalog package, a public package or one shared by link into a new package owned by the caller, with the changes in the request applied. A package with a recipe is recomputed from the current foods. The clone starts at version 1 and is private unless another visibility is given.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                   true   "Meal Package ID"
+// @Param        token    query     string                   false  "Share token of a package shared by link"
+// @Param        changes  body      cloneMealPackageRequest  true   "Fields to change in the clone"
+// @Success      201      {object}  models.MealPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /meals/packages/{id}/clone [post]
+func (h *MealHandler) CloneMealPackage(c *gin.Context) {
+	var req cloneMealPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	source, err := h.viewableMealPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if source.Archived {
+		c.Error(db.NewValidationError("id", "Package "+source.ID+" is archived"))
+		return
+	}
+
+	clone := models.MealPackage{
+		ID:               utils.GenerateID(),
+		Name:             source.Name,
+		Description:      source.Description,
+		GoalType:         source.GoalType,
+		MealType:         source.MealType,
+		BaseCalories:     source.BaseCalories,
+		BaseProtein:      source.BaseProtein,
+		BaseCarbs:        source.BaseCarbs,
+		BaseFat:          source.BaseFat,
+		ImageURL:         source.ImageURL,
+		PreparationSteps: source.PreparationSteps,
+		Ingredients:      source.Ingredients,
+		Recipe:           source.Recipe,
+		OwnerID:          currentUserID(c),
+	}
+	if err := req.apply(&clone); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := recipes.Apply(c.Request.Context(), h.store, &clone); err != nil {
+		c.Error(referenceError("recipe", err))
+		return
+	}
+	if clone.Visibility, clone.ShareToken, err = packageVisibility(clone.OwnerID, req.Visibility, ""); err != nil {
+		c.Error(err)
+		return
+	}
+
+	created, err := h.store.CreateMealPackage(c.Request.Context(), clone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// cloneWorkoutPackageRequest defines the changes made to a cloned workout package.
+// Omitted fields keep the source package's values.
+type cloneWorkoutPackageRequest struct {
+	Name                *string  `json:"name" binding:"omitempty,min=1,max=100" example:"Short HIIT"`
+	Description         *string  `json:"description" binding:"omitempty,max=500"`
+	GoalType            *string  `json:"goalType" example:"LOSE" enums:"LOSE,GAIN,BOTH"`
+	WorkoutType         *string  `json:"workoutType" binding:"omitempty,min=1,max=30" example:"HIIT"`
+	BaseDurationMinutes *int     `json:"baseDurationMinutes" binding:"omitempty,min=1,max=600" example:"20"`
+	BaseCaloriesBurn    *int     `json:"baseCaloriesBurn" binding:"omitempty,min=1,max=5000" example:"200"`
+	CaloriesBurnFormula *string  `json:"caloriesBurnFormula" binding:"omitempty,max=200"`
+	ImageURL            *string  `json:"imageUrl" binding:"omitempty,url"`
+	Instructions        []string `json:"instructions"`
+	Visibility          string   `json:"visibility" example:"PRIVATE" enums:"PRIVATE,LINK,PUBLIC"` // Defaults to PRIVATE
+}
+
+// apply makes the requested changes to a copy of the source package
+func (r cloneWorkoutPackageRequest) apply(pkg *models.WorkoutPackage) error {
+	if r.Name != nil {
+		pkg.Name = strings.TrimSpace(*r.Name)
+	}
+	if r.Description != nil {
+		pkg.Description = *r.Description
+	}
+	if r.GoalType != nil {
+		goalType, err := parsePackageGoalType(*r.GoalType)
+		if err != nil {
+			return err
+		}
+		pkg.GoalType = goalType
+	}
+	if r.WorkoutType != nil {
+		pkg.WorkoutType = strings.ToUpper(*r.WorkoutType)
+	}
+	if r.BaseDurationMinutes != nil {
+		pkg.BaseDurationMinutes = *r.BaseDurationMinutes
+	}
+	if r.BaseCaloriesBurn != nil {
+		pkg.BaseCaloriesBurn = *r.BaseCaloriesBurn
+	}
+	if r.CaloriesBurnFormula != nil {
+		pkg.CaloriesBurnFormula = *r.CaloriesBurnFormula
+	}
+	if pkg.CaloriesBurnFormula == "" {
+		pkg.CaloriesBurnFormula = models.DefaultCaloriesBurnFormula
+	}
+	if r.ImageURL != nil {
+		pkg.ImageURL = *r.ImageURL
+	}
+	if r.Instructions != nil {
+		pkg.Instructions = r.Instructions
+	}
+	return nil
+}
+
+// CloneWorkoutPackage godoc
+// @Summary      Clone a workout package
+// @Description  Copies a catalog package, a public package or one shared by link into a new package owned by the caller, with the changes in the request applied. The clone starts at version 1 and is private unless another visibility is given.
+// @Tags         workouts
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string                      true   "Workout Package ID"
+// @Param        token    query     string                      false  "Share token of a package shared by link"
+// @Param        changes  body      cloneWorkoutPackageRequest  true   "Fields to change in the clone"
+// @Success      201      {object}  models.WorkoutPackage
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /workouts/packages/{id}/clone [post]
+func (h *WorkoutHandler) CloneWorkoutPackage(c *gin.Context) {
+	var req cloneWorkoutPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	source, err := h.viewableWorkoutPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if source.Archived {
+		c.Error(db.NewValidationError("id", "Package "+source.ID+" is archived"))
+		return
+	}
+
+	clone := models.WorkoutPackage{
+		ID:                  utils.GenerateID(),
+		Name:                source.Name,
+		Description:         source.Description,
+		GoalType:            source.GoalType,
+		WorkoutType:         source.WorkoutType,
+		BaseDurationMinutes: source.BaseDurationMinutes,
+		BaseCaloriesBurn:    source.BaseCaloriesBurn,
+		CaloriesBurnFormula: source.CaloriesBurnFormula,
+		ImageURL:            source.ImageURL,
+		Instructions:        source.Instructions,
+		OwnerID:             currentUserID(c),
+	}
+	if err := req.apply(&clone); err != nil {
+		c.Error(err)
+		return
+	}
+	if clone.Visibility, clone.ShareToken, err = packageVisibility(clone.OwnerID, req.Visibility, ""); err != nil {
+		c.Error(err)
+		return
+	}
+
+	created, err := h.store.CreateWorkoutPackage(c.Request.Context(), clone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
@@ -5,11 +5,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/auth"
 	"github.com/zhenyili/BalanceLife/src/db"
 	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
 	"github.com/zhenyili/BalanceLife/src/utils"
 )
 
+// maxAge bounds the age implied by a birth date
+const maxAge = 120
+
 // UserHandler handles user-related requests
 type UserHandler struct {
 	store db.Store
@@ -22,43 +27,68 @@ func NewUserHandler(store db.Store) *UserHandler {
 	}
 }
 
-// RegisterRoutes registers user routes to the router
+// RegisterRoutes registers the authenticated user routes to the router
 func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
-		users.GET("", h.GetUsers)
+		users.GET("", RequireRole(models.RoleAdmin), h.GetUsers)
 		users.GET("/:id", h.GetUser)
-		users.POST("", h.CreateUser)
+		users.PATCH("/:id", h.UpdateUser)
+		users.POST("/:id/macros/preview", h.PreviewMacros)
 		users.DELETE("/:id", h.DeleteUser)
 	}
 }
 
+// RegisterPublicRoutes registers the user routes that do not require authentication
+func (h *UserHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.POST("/users", h.CreateUser)
+}
+
 // GetUsers godoc
 // @Summary      Get all users
-// @Description  Returns a list of all users in the system
+// @Description  Returns a list of all users in the system. Requires the ADMIN role.
 // @Tags         users
 // @Produce      json
+// @Security     ApiKeyAuth
 // @Success      200  {array}   models.User
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
 // @Router       /users [get]
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	users := h.store.GetUsers()
+	users, err := h.store.GetUsers(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
 	c.JSON(http.StatusOK, users)
 }
 
 // GetUser godoc
 // @Summary      Get a user by ID
-// @Description  Returns details of a specific user
+// @Description  Returns details of a specific user. Users may only read their own profile.
 // @Tags         users
 // @Produce      json
+// @Security     ApiKeyAuth
 // @Param        id   path      string  true  "User ID"
 // @Success      200  {object}  models.User
-// @Failure      404  {object}  map[string]string
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
 // @Router       /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id := c.Param("id")
-	user, err := h.store.GetUser(id)
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -66,15 +96,19 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // userRegistrationRequest defines the structure for user registration
 type userRegistrationRequest struct {
-	Name          string  `json:"name" binding:"required" example:"John Doe"`
-	Email         string  `json:"email" binding:"required,email" example:"john@example.com"`
-	Password      string  `json:"password" binding:"required,min=6" example:"SecurePassword123"`
-	Gender        string  `json:"gender" binding:"required" example:"MALE" enums:"MALE,FEMALE,OTHER"`
-	BirthDate     string  `json:"birthDate" binding:"required" example:"1990-01-01"`
-	Height        float64 `json:"height" binding:"required" example:"180.0"`
-	Weight        float64 `json:"weight" binding:"required" example:"80.0"`
-	ActivityLevel string  `json:"activityLevel" binding:"required" example:"MODERATE" enums:"LOW,MODERATE,HIGH"`
-	Goal          string  `json:"goal" binding:"required" example:"LOSE" enums:"LOSE,GAIN"`
+	Name           string             `json:"name" binding:"required" example:"John Doe"`
+	Email          string             `json:"email" binding:"required,email" example:"john@example.com"`
+	Password       string             `json:"password" binding:"required,min=6,max=72" example:"SecurePassword123"`
+	Gender         string             `json:"gender" binding:"required" example:"MALE" enums:"MALE,FEMALE,OTHER"`
+	BirthDate      string             `json:"birthDate" binding:"required" example:"1990-01-01"`
+	Height         float64            `json:"height" binding:"required,gt=0,lte=300" example:"180.0"`
+	Weight         float64            `json:"weight" binding:"required,gt=0,lte=700" example:"80.0"`
+	BodyFatPercent float64            `json:"bodyFatPercent" binding:"omitempty,gt=0,lt=100" example:"18.5"`
+	ActivityLevel  string             `json:"activityLevel" binding:"required" example:"MODERATE" enums:"SEDENTARY,LOW,MODERATE,HIGH,VERY_HIGH"`
+	Goal           string             `json:"goal" binding:"required" example:"LOSE" enums:"LOSE,GAIN"`
+	BMRFormula     string             `json:"bmrFormula" example:"MIFFLIN_ST_JEOR" enums:"HARRIS_BENEDICT,MIFFLIN_ST_JEOR,KATCH_MCARDLE"`
+	MacroProfile   string             `json:"macroProfile" example:"HIGH_PROTEIN_CUT" enums:"BALANCED,HIGH_PROTEIN_CUT,LEAN_BULK,KETO,LOW_FAT,CUSTOM"`
+	CustomMacros   *models.MacroSplit `json:"customMacros"`
 }
 
 // CreateUser godoc
@@ -85,139 +119,441 @@ type userRegistrationRequest struct {
 // @Produce      json
 // @Param        user  body      userRegistrationRequest  true  "User details"
 // @Success      201   {object}  models.User
-// @Failure      400   {object}  map[string]string
+// @Failure      400   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
 // @Router       /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req userRegistrationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindingError(err))
 		return
 	}
 
-	// Parse birth date
-	birthDate, err := time.Parse("2006-01-02", req.BirthDate)
+	birthDate, err := parseBirthDate(req.BirthDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid birth date format, use YYYY-MM-DD"})
+		c.Error(err)
 		return
 	}
 
-	// Validate gender
-	gender := models.Gender(req.Gender)
-	if gender != models.GenderMale && gender != models.GenderFemale && gender != models.GenderOther {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gender"})
+	gender, err := parseGender(req.Gender)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Validate activity level
-	activityLevel := models.ActivityLevel(req.ActivityLevel)
-	if activityLevel != models.ActivityLow && activityLevel != models.ActivityModerate && activityLevel != models.ActivityHigh {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity level"})
+	activityLevel, err := parseActivityLevel(req.ActivityLevel)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Validate goal
-	goalType := models.GoalType(req.Goal)
-	if goalType != models.GoalTypeLose && goalType != models.GoalTypeGain {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal type"})
+	goalType, err := parseGoalType(req.Goal)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Calculate default target values based on user's data
-	// Note: In a real application, this would be more sophisticated
-	baseCalories := calculateBaseCalories(req.Weight, req.Height, birthDate, gender, activityLevel)
+	var formula models.BMRFormula
+	if req.BMRFormula != "" {
+		if formula, err = parseBMRFormula(req.BMRFormula); err != nil {
+			c.Error(err)
+			return
+		}
+	}
 
-	var targetCalories int
-	if goalType == models.GoalTypeLose {
-		targetCalories = baseCalories - 500 // 500 calorie deficit for weight loss
-	} else {
-		targetCalories = baseCalories + 500 // 500 calorie surplus for muscle gain
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
 	// Create a new user
 	newUser := models.User{
-		ID:            utils.GenerateID(), // Simple ID generation
-		Name:          req.Name,
-		Email:         req.Email,
-		Password:      req.Password, // In a real app, you would hash this
-		Gender:        gender,
-		BirthDate:     birthDate,
-		Height:        req.Height,
-		Weight:        req.Weight,
-		ActivityLevel: activityLevel,
+		ID:             utils.GenerateID(), // Simple ID generation
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       passwordHash,
+		Role:           models.RoleUser,
+		Gender:         gender,
+		BirthDate:      birthDate,
+		Height:         req.Height,
+		Weight:         req.Weight,
+		BodyFatPercent: req.BodyFatPercent,
+		ActivityLevel:  activityLevel,
+		BMRFormula:     formula,
 		Goal: models.GoalInfo{
-			Type:           goalType,
-			TargetCalories: targetCalories,
-			TargetProtein:  int(float64(targetCalories) * 0.25 / 4), // 25% from protein (4 calories per gram)
-			TargetCarbs:    int(float64(targetCalories) * 0.5 / 4),  // 50% from carbs (4 calories per gram)
-			TargetFat:      int(float64(targetCalories) * 0.25 / 9), // 25% from fat (9 calories per gram)
-			StartDate:      time.Now(),
-			StartWeight:    req.Weight,
+			Type:        goalType,
+			TargetMode:  models.TargetModeAuto,
+			StartDate:   time.Now(),
+			StartWeight: req.Weight,
 		},
 		CreatedAt:   time.Now(),
 		LastLoginAt: time.Now(),
 	}
 
-	createdUser, err := h.store.CreateUser(newUser)
+	if err := applyMacroChoice(&newUser, req.MacroProfile, req.CustomMacros); err != nil {
+		c.Error(err)
+		return
+	}
+	nutrition.ApplyTargets(&newUser, time.Now())
+
+	createdUser, err := h.store.CreateUser(c.Request.Context(), newUser)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
+	recordMetrics(c.Request.Context(), h.store, createdUser)
 
 	c.JSON(http.StatusCreated, createdUser)
 }
 
+// updateUserRequest defines the profile fields that can be changed.
+// Omitted fields keep their current values.
+type updateUserRequest struct {
+	Name            *string            `json:"name" binding:"omitempty,min=1" example:"John Doe"`
+	Gender          *string            `json:"gender" example:"MALE" enums:"MALE,FEMALE,OTHER"`
+	BirthDate       *string            `json:"birthDate" example:"1990-01-01"`
+	Height          *float64           `json:"height" binding:"omitempty,gt=0,lte=300" example:"180.0"`
+	Weight          *float64           `json:"weight" binding:"omitempty,gt=0,lte=700" example:"78.5"`
+	BodyFatPercent  *float64           `json:"bodyFatPercent" binding:"omitempty,gte=0,lt=100" example:"18.5"` // 0 clears it
+	ActivityLevel   *string            `json:"activityLevel" example:"HIGH" enums:"SEDENTARY,LOW,MODERATE,HIGH,VERY_HIGH"`
+	BMRFormula      *string            `json:"bmrFormula" example:"KATCH_MCARDLE" enums:"HARRIS_BENEDICT,MIFFLIN_ST_JEOR,KATCH_MCARDLE"`
+	MacroProfile    *string            `json:"macroProfile" example:"LEAN_BULK" enums:"BALANCED,HIGH_PROTEIN_CUT,LEAN_BULK,KETO,LOW_FAT,CUSTOM"`
+	CustomMacros    *models.MacroSplit `json:"customMacros"`
+	Goal            *string            `json:"goal" example:"LOSE" enums:"LOSE,GAIN"`
+	AdaptiveTargets *bool              `json:"adaptiveTargets" example:"true"` // Base AUTO targets on the adaptive TDEE estimate
+	TargetWeight    *float64           `json:"targetWeight" binding:"omitempty,gte=0,lte=700" example:"72.0"`
+	TargetMode      *string            `json:"targetMode" example:"MANUAL" enums:"AUTO,MANUAL"`
+	TargetCalories  *int               `json:"targetCalories" binding:"omitempty,min=800,max=10000" example:"2200"`
+	TargetProtein   *int               `json:"targetProtein" binding:"omitempty,min=0,max=1000" example:"160"`
+	TargetCarbs     *int               `json:"targetCarbs" binding:"omitempty,min=0,max=1500" example:"220"`
+	TargetFat       *int               `json:"targetFat" binding:"omitempty,min=0,max=500" example:"70"`
+}
+
+// hasTargets reports whether the request sets any custom goal target
+func (r updateUserRequest) hasTargets() bool {
+	return r.TargetCalories != nil || r.TargetProtein != nil || r.TargetCarbs != nil || r.TargetFat != nil
+}
+
+// UpdateUser godoc
+// @Summary      Update a user profile
+// @Description  Changes body metrics, activity level or goal. In AUTO target mode the calorie and macro targets are recalculated, from the adaptive TDEE estimate when adaptiveTargets is on and the estimate is confident; in MANUAL mode the targets given by the user are kept. Users may only update their own profile.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      string             true  "User ID"
+// @Param        user  body      updateUserRequest  true  "Fields to change"
+// @Success      200   {object}  models.User
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
+// @Router       /users/{id} [patch]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req updateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Gender != nil {
+		if user.Gender, err = parseGender(*req.Gender); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.BirthDate != nil {
+		if user.BirthDate, err = parseBirthDate(*req.BirthDate); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.Height != nil {
+		user.Height = *req.Height
+	}
+	if req.Weight != nil {
+		user.Weight = *req.Weight
+	}
+	if req.BodyFatPercent != nil {
+		user.BodyFatPercent = *req.BodyFatPercent
+	}
+	if req.ActivityLevel != nil {
+		if user.ActivityLevel, err = parseActivityLevel(*req.ActivityLevel); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.BMRFormula != nil {
+		if user.BMRFormula, err = parseBMRFormula(*req.BMRFormula); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.MacroProfile != nil || req.CustomMacros != nil {
+		profile := ""
+		if req.MacroProfile != nil {
+			profile = *req.MacroProfile
+		}
+		if err := applyMacroChoice(&user, profile, req.CustomMacros); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if req.Goal != nil {
+		goalType, err := parseGoalType(*req.Goal)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		// Switching goals starts a new goal period from the current weight
+		if goalType != user.Goal.Type {
+			user.Goal.Type = goalType
+			user.Goal.StartDate = time.Now()
+			user.Goal.StartWeight = user.Weight
+		}
+	}
+	if req.TargetWeight != nil {
+		user.Goal.TargetWeight = *req.TargetWeight
+	}
+	if req.AdaptiveTargets != nil {
+		user.AdaptiveTargets = *req.AdaptiveTargets
+	}
+	if req.TargetMode != nil {
+		if user.Goal.TargetMode, err = parseTargetMode(*req.TargetMode); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	// Custom targets are only accepted in MANUAL mode, so that a later
+	// recalculation never silently discards them
+	if req.hasTargets() {
+		if !user.Goal.IsManual() {
+			c.Error(db.NewValidationError("targetMode", "Must be MANUAL to set custom targets"))
+			return
+		}
+		if req.TargetCalories != nil {
+			user.Goal.TargetCalories = *req.TargetCalories
+		}
+		if req.TargetProtein != nil {
+			user.Goal.TargetProtein = *req.TargetProtein
+		}
+		if req.TargetCarbs != nil {
+			user.Goal.TargetCarbs = *req.TargetCarbs
+		}
+		if req.TargetFat != nil {
+			user.Goal.TargetFat = *req.TargetFat
+		}
+	}
+
+	nutrition.ApplyTargets(&user, time.Now())
+
+	updatedUser, err := h.store.UpdateUser(c.Request.Context(), user)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	recordMetrics(c.Request.Context(), h.store, updatedUser)
+
+	c.JSON(http.StatusOK, updatedUser)
+}
+
+// macroPreviewRequest defines a macro profile choice to preview
+type macroPreviewRequest struct {
+	MacroProfile string             `json:"macroProfile" example:"KETO" enums:"BALANCED,HIGH_PROTEIN_CUT,LEAN_BULK,KETO,LOW_FAT,CUSTOM"`
+	CustomMacros *models.MacroSplit `json:"customMacros"`
+	Calories     int                `json:"calories" binding:"omitempty,min=800,max=10000" example:"2200"` // Defaults to the current calorie target
+}
+
+// PreviewMacros godoc
+// @Summary      Preview macro targets
+// @Description  Validates a macro profile or custom percentages and returns the resulting daily grams for the user's weight and calorie target, without saving anything
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      string               true  "User ID"
+// @Param        profile  body      macroPreviewRequest  true  "Macro profile to preview"
+// @Success      200      {object}  models.MacroTargets
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /users/{id}/macros/preview [post]
+func (h *UserHandler) PreviewMacros(c *gin.Context) {
+	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req macroPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	user, err := h.store.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := applyMacroChoice(&user, req.MacroProfile, req.CustomMacros); err != nil {
+		c.Error(err)
+		return
+	}
+
+	calories := req.Calories
+	if calories == 0 {
+		calories = user.Goal.TargetCalories
+	}
+
+	c.JSON(http.StatusOK, nutrition.Macros(calories, user.Weight, user.Goal.Type, user.MacroProfile, user.CustomMacros))
+}
+
 // DeleteUser godoc
 // @Summary      Delete a user
-// @Description  Deletes a user by ID
+// @Description  Deletes a user by ID. Users may only delete their own account.
 // @Tags         users
 // @Produce      json
+// @Security     ApiKeyAuth
 // @Param        id   path      string  true  "User ID"
 // @Success      200  {object}  models.User
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
 // @Router       /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
+	if err := authorizeUser(c, id); err != nil {
+		c.Error(err)
+		return
+	}
 
 	// Call the store to delete the user
-	deletedUser, err := h.store.DeleteUser(id)
+	deletedUser, err := h.store.DeleteUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, deletedUser)
 }
 
-// calculateBaseCalories calculates base calorie needs using a simplified formula
-// In a real application, you would use more sophisticated formulas like Harris-Benedict
-func calculateBaseCalories(weight float64, height float64, birthDate time.Time, gender models.Gender, activityLevel models.ActivityLevel) int {
-	// Calculate age
-	now := time.Now()
-	age := now.Year() - birthDate.Year()
-	if now.YearDay() < birthDate.YearDay() {
-		age--
+// parseGender validates a gender value from a request
+func parseGender(value string) (models.Gender, error) {
+	gender := models.Gender(value)
+	if gender != models.GenderMale && gender != models.GenderFemale && gender != models.GenderOther {
+		return "", db.NewValidationError("gender", "Must be one of MALE, FEMALE, OTHER")
 	}
+	return gender, nil
+}
 
-	var bmr float64
-	if gender == models.GenderMale {
-		bmr = 88.362 + (13.397 * weight) + (4.799 * height) - (5.677 * float64(age))
-	} else {
-		bmr = 447.593 + (9.247 * weight) + (3.098 * height) - (4.330 * float64(age))
+// parseBirthDate validates a birth date from a request. The BMR formulas need an
+// age, so the date must be in the past and at most maxAge years ago.
+func parseBirthDate(value string) (time.Time, error) {
+	birthDate, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, db.NewValidationError("birthDate", "Invalid date format, use YYYY-MM-DD")
 	}
+	if age := nutrition.Age(birthDate, time.Now()); !birthDate.Before(time.Now()) || age > maxAge {
+		return time.Time{}, db.NewValidationError("birthDate", "Must be in the past and at most 120 years ago")
+	}
+	return birthDate, nil
+}
 
-	// Apply activity multiplier
-	var activityMultiplier float64
+// parseActivityLevel validates an activity level value from a request
+func parseActivityLevel(value string) (models.ActivityLevel, error) {
+	activityLevel := models.ActivityLevel(value)
 	switch activityLevel {
-	case models.ActivityLow:
-		activityMultiplier = 1.2
-	case models.ActivityModerate:
-		activityMultiplier = 1.55
-	case models.ActivityHigh:
-		activityMultiplier = 1.9
+	case models.ActivitySedentary, models.ActivityLow, models.ActivityModerate, models.ActivityHigh, models.ActivityVeryHigh:
+		return activityLevel, nil
+	}
+	return "", db.NewValidationError("activityLevel", "Must be one of SEDENTARY, LOW, MODERATE, HIGH, VERY_HIGH")
+}
+
+// parseBMRFormula validates a BMR formula name from a request
+func parseBMRFormula(value string) (models.BMRFormula, error) {
+	formula := models.BMRFormula(value)
+	if _, ok := nutrition.Lookup(formula); !ok || formula == "" {
+		return "", db.NewValidationError("bmrFormula", "Must be one of HARRIS_BENEDICT, MIFFLIN_ST_JEOR, KATCH_MCARDLE")
+	}
+	return formula, nil
+}
+
+// parseGoalType validates a goal value from a request
+func parseGoalType(value string) (models.GoalType, error) {
+	goalType := models.GoalType(value)
+	if goalType != models.GoalTypeLose && goalType != models.GoalTypeGain {
+		return "", db.NewValidationError("goal", "Must be one of LOSE, GAIN")
+	}
+	return goalType, nil
+}
+
+// applyMacroChoice validates and applies a requested macro profile and custom
+// percentages. An empty profile with custom percentages selects CUSTOM.
+func applyMacroChoice(user *models.User, profile string, custom *models.MacroSplit) error {
+	macroProfile := models.MacroProfile(profile)
+	if profile != "" && !nutrition.ValidMacroProfile(macroProfile) {
+		return db.NewValidationError("macroProfile", "Must be one of BALANCED, HIGH_PROTEIN_CUT, LEAN_BULK, KETO, LOW_FAT, CUSTOM")
+	}
+
+	if custom != nil {
+		if profile != "" && macroProfile != models.MacroCustom {
+			return db.NewValidationError("customMacros", "Only allowed with the CUSTOM macro profile")
+		}
+		if err := nutrition.CheckSplit(*custom); err != nil {
+			return db.NewValidationError("customMacros", err.Error())
+		}
+		user.MacroProfile = models.MacroCustom
+		user.CustomMacros = custom
+		return nil
+	}
+
+	switch {
+	case profile == "":
+	case macroProfile == models.MacroCustom:
+		if user.CustomMacros == nil {
+			return db.NewValidationError("customMacros", "Is required for the CUSTOM macro profile")
+		}
+		user.MacroProfile = models.MacroCustom
 	default:
-		activityMultiplier = 1.4
+		user.MacroProfile = macroProfile
+		user.CustomMacros = nil
 	}
+	return nil
+}
 
-	return int(bmr * activityMultiplier)
+// parseTargetMode validates a target mode value from a request
+func parseTargetMode(value string) (models.TargetMode, error) {
+	mode := models.TargetMode(value)
+	if mode != models.TargetModeAuto && mode != models.TargetModeManual {
+		return "", db.NewValidationError("targetMode", "Must be one of AUTO, MANUAL")
+	}
+	return mode, nil
 }
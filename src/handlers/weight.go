@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhenyili/BalanceLife/src/analytics"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"github.com/zhenyili/BalanceLife/src/utils"
+)
+
+// WeightHandler handles weight and body measurement log requests
+type WeightHandler struct {
+	store db.Store
+}
+
+// NewWeightHandler creates a new weight handler
+func NewWeightHandler(store db.Store) *WeightHandler {
+	return &WeightHandler{
+		store: store,
+	}
+}
+
+// RegisterRoutes registers weight routes to the router
+func (h *WeightHandler) RegisterRoutes(router *gin.RouterGroup) {
+	weight := router.Group("/weight")
+	{
+		weight.POST("/entries", h.CreateWeightEntry)
+		weight.GET("/entries", h.GetWeightHistory)
+		weight.GET("/entries/:id", h.GetWeightEntry)
+		weight.DELETE("/entries/:id", h.DeleteWeightEntry)
+	}
+}
+
+// bodyMeasurementsRequest defines optional girth measurements in centimeters
+type bodyMeasurementsRequest struct {
+	Neck  float64 `json:"neck" binding:"omitempty,gt=0,lte=300" example:"38"`
+	Chest float64 `json:"chest" binding:"omitempty,gt=0,lte=300" example:"102"`
+	Waist float64 `json:"waist" binding:"omitempty,gt=0,lte=300" example:"86"`
+	Hips  float64 `json:"hips" binding:"omitempty,gt=0,lte=300" example:"98"`
+	Arm   float64 `json:"arm" binding:"omitempty,gt=0,lte=300" example:"34"`
+	Thigh float64 `json:"thigh" binding:"omitempty,gt=0,lte=300" example:"58"`
+}
+
+// weightEntryRequest defines the structure for weight entry creation
+type weightEntryRequest struct {
+	Weight         float64                  `json:"weight" binding:"required,gt=0,lte=700" example:"79.4"`
+	BodyFatPercent float64                  `json:"bodyFatPercent" binding:"omitempty,gt=0,lt=100" example:"21.5"`
+	Measurements   *bodyMeasurementsRequest `json:"measurements"`
+	Note           string                   `json:"note" binding:"max=500" example:"Morning, before breakfast"`
+	Date           string                   `json:"date" binding:"required" example:"2023-03-18"`
+	UpdateProfile  bool                     `json:"updateProfile" example:"true"` // Also set the profile weight and body fat if this is the latest entry
+}
+
+// weightEntryResponse is a created weight entry and whether the profile weight was updated
+type weightEntryResponse struct {
+	models.WeightEntry
+	ProfileUpdated bool `json:"profileUpdated"`
+}
+
+// CreateWeightEntry godoc
+// @Summary      Log a weight entry
+// @Description  Logs body weight and optional body fat percentage and girth measurements for the authenticated user. With updateProfile, the profile weight and body fat are set as well when the entry is the most recent one, which recalculates AUTO goal targets.
+// @Tags         weight
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        entry  body      weightEntryRequest  true  "Weight entry details"
+// @Success      201    {object}  weightEntryResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /weight/entries [post]
+func (h *WeightHandler) CreateWeightEntry(c *gin.Context) {
+	var req weightEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.Error(db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+
+	newEntry := models.WeightEntry{
+		ID:             utils.GenerateID(),
+		UserID:         currentUserID(c),
+		Weight:         req.Weight,
+		BodyFatPercent: req.BodyFatPercent,
+		Note:           req.Note,
+		Date:           date,
+		Timestamp:      time.Now(),
+		CreatedAt:      time.Now(),
+	}
+	if m := req.Measurements; m != nil {
+		newEntry.Measurements = &models.BodyMeasurements{
+			Neck:  m.Neck,
+			Chest: m.Chest,
+			Waist: m.Waist,
+			Hips:  m.Hips,
+			Arm:   m.Arm,
+			Thigh: m.Thigh,
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	createdEntry, err := h.store.CreateWeightEntry(ctx, newEntry)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response := weightEntryResponse{WeightEntry: createdEntry}
+
+	if req.UpdateProfile {
+		// Back-filled entries must not overwrite a more recent weight
+		latest, err := h.store.GetLatestWeightEntry(ctx, createdEntry.UserID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		if latest.ID == createdEntry.ID {
+			user, err := h.store.GetUser(ctx, createdEntry.UserID)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+
+			user.Weight = createdEntry.Weight
+			if createdEntry.BodyFatPercent > 0 {
+				user.BodyFatPercent = createdEntry.BodyFatPercent
+			}
+			nutrition.ApplyTargets(&user, time.Now())
+
+			updatedUser, err := h.store.UpdateUser(ctx, user)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			recordMetrics(ctx, h.store, updatedUser)
+			response.ProfileUpdated = true
+		}
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetWeightHistory godoc
+// @Summary      Get weight history
+// @Description  Returns the authenticated user's weight entries within a date range with trailing moving averages, plus the change since the goal start date and progress toward the target weight
+// @Tags         weight
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        startDate  query     string  false  "Start date (YYYY-MM-DD), defaults to 30 days before endDate"
+// @Param        endDate    query     string  false  "End date (YYYY-MM-DD), defaults to today"
+// @Param        window     query     int     false  "Moving average window in days (1-90), defaults to 7"
+// @Success      200        {object}  models.WeightHistory
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
+// @Router       /weight/entries [get]
+func (h *WeightHandler) GetWeightHistory(c *gin.Context) {
+	userID := currentUserID(c)
+
+	endDateStr := c.DefaultQuery("endDate", time.Now().Format("2006-01-02"))
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("endDate", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+
+	startDateStr := c.DefaultQuery("startDate", endDate.AddDate(0, 0, -30).Format("2006-01-02"))
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		c.Error(db.NewValidationError("startDate", "Invalid date format, use YYYY-MM-DD"))
+		return
+	}
+	if endDate.Before(startDate) {
+		c.Error(db.NewValidationError("endDate", "End date must not be before start date"))
+		return
+	}
+
+	window := analytics.DefaultWeightWindowDays
+	if windowStr := c.Query("window"); windowStr != "" {
+		window, err = strconv.Atoi(windowStr)
+		if err != nil || window < 1 || window > 90 {
+			c.Error(db.NewValidationError("window", "Must be a number of days between 1 and 90"))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.store.GetUser(ctx, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Fetch the days before the range as well so the first moving averages are complete
+	entries, err := h.store.GetWeightEntriesByUserAndDateRange(ctx, userID, startDate.AddDate(0, 0, -(window-1)), endDate.Add(24*time.Hour-time.Second))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var latest *models.WeightEntry
+	if entry, err := h.store.GetLatestWeightEntry(ctx, userID); err == nil {
+		latest = &entry
+	} else if !errors.Is(err, db.ErrNotFound) {
+		c.Error(err)
+		return
+	}
+
+	history := analytics.WeightHistory(user, startDate, endDate, window, entries, latest)
+	c.JSON(http.StatusOK, history)
+}
+
+// loadWeightEntry fetches a weight entry and checks that the caller may access it
+func (h *WeightHandler) loadWeightEntry(c *gin.Context) (models.WeightEntry, error) {
+	entry, err := h.store.GetWeightEntry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		return models.WeightEntry{}, err
+	}
+	if err := authorizeUser(c, entry.UserID); err != nil {
+		return models.WeightEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetWeightEntry godoc
+// @Summary      Get a weight entry by ID
+// @Description  Returns one of the authenticated user's weight entries
+// @Tags         weight
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Weight Entry ID"
+// @Success      200  {object}  models.WeightEntry
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /weight/entries/{id} [get]
+func (h *WeightHandler) GetWeightEntry(c *gin.Context) {
+	entry, err := h.loadWeightEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteWeightEntry godoc
+// @Summary      Delete a weight entry
+// @Description  Removes one of the authenticated user's weight entries. The profile weight is left unchanged.
+// @Tags         weight
+// @Security     ApiKeyAuth
+// @Param        id   path  string  true  "Weight Entry ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /weight/entries/{id} [delete]
+func (h *WeightHandler) DeleteWeightEntry(c *gin.Context) {
+	entry, err := h.loadWeightEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := h.store.DeleteWeightEntry(c.Request.Context(), entry.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
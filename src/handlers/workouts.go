@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,118 +30,191 @@ func (h *WorkoutHandler) RegisterRoutes(router *gin.RouterGroup) {
 		// Workout packages
 		workouts.GET("/packages", h.GetWorkoutPackages)
 		workouts.GET("/packages/:id", h.GetWorkoutPackage)
+		workouts.GET("/packages/:id/versions/:version", h.GetWorkoutPackageVersion)
+		workouts.POST("/packages/:id/clone", h.CloneWorkoutPackage)
+
+		// Package management. Admins manage the catalog; owners manage their own packages.
+		workouts.POST("/packages", RequireRole(models.RoleAdmin), h.CreateWorkoutPackage)
+		workouts.PUT("/packages/:id", h.UpdateWorkoutPackage)
+		workouts.DELETE("/packages/:id", h.ArchiveWorkoutPackage)
+		workouts.POST("/packages/:id/restore", h.RestoreWorkoutPackage)
 
 		// Workout entries
 		workouts.POST("/entries", h.CreateWorkoutEntry)
 		workouts.GET("/entries", h.GetWorkoutEntries)
+		workouts.GET("/entries/:id", h.GetWorkoutEntry)
+		workouts.PATCH("/entries/:id", h.UpdateWorkoutEntry)
+		workouts.DELETE("/entries/:id", h.DeleteWorkoutEntry)
 	}
 }
 
 // GetWorkoutPackages godoc
-// @Summary      Get all workout packages
-// @Description  Returns a list of all workout packages, optionally filtered by goal type
+// @Summary      List workout packages
+// @Description  Returns one page of workout packages matching the filters. Filtering on LOSE or GAIN includes packages for BOTH goals, and suitsGoal marks the packages that fit the caller's current goal. Text search matches words in the name, description and instructions. Lists include the catalog, the caller's own packages and other users' public packages unless scope narrows them. Pass nextCursor from a response as cursor to get the next page.
 // @Tags         workouts
 // @Produce      json
-// @Param        goalType  query     string  false  "Goal type filter (LOSE, GAIN, ALL)"
-// @Success      200       {array}   models.WorkoutPackage
+// @Security     ApiKeyAuth
+// @Param        goalType         query     string  false  "Goal type filter (LOSE, GAIN, BOTH, ALL)"
+// @Param        workoutType      query     string  false  "Workout type filter, e.g. HIIT or CARDIO"
+// @Param        q                query     string  false  "Text search"
+// @Param        minCalories      query     int     false  "Minimum calories burned"
+// @Param        maxCalories      query     int     false  "Maximum calories burned"
+// @Param        minDuration      query     int     false  "Minimum duration in minutes"
+// @Param        maxDuration      query     int     false  "Maximum duration in minutes"
+// @Param        sort             query     string  false  "Sort key (name, calories, duration, burnRate), prefix with - for descending"
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Page size (1-100), defaults to 20"
+// @Param        scope            query     string  false  "Packages to list: all (default) for the catalog, your own and public packages; catalog; or mine"
+// @Param        includeArchived  query     bool    false  "Include archived packages (ADMIN only)"
+// @Success      200              {object}  models.WorkoutPackagePage
+// @Failure      400              {object}  ErrorResponse
+// @Failure      401              {object}  ErrorResponse
+// @Failure      403              {object}  ErrorResponse
+// @Failure      500              {object}  ErrorResponse
+// @Failure      503              {object}  ErrorResponse
 // @Router       /workouts/packages [get]
 func (h *WorkoutHandler) GetWorkoutPackages(c *gin.Context) {
-	goalType := models.GoalType(c.Query("goalType"))
-	packages := h.store.GetWorkoutPackages(goalType)
-	c.JSON(http.StatusOK, packages)
+	params, err := parsePackageListParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	query := db.WorkoutPackageQuery{
+		GoalType:        params.GoalType,
+		WorkoutType:     strings.ToUpper(c.Query("workoutType")),
+		Search:          params.Search,
+		Sort:            params.Sort,
+		Viewer:          currentUserID(c),
+		Scope:           params.Scope,
+		IncludeArchived: params.IncludeArchived,
+		Descending:      params.Descending,
+		Cursor:          params.Cursor,
+		Limit:           params.Limit,
+	}
+	if query.Calories, err = parseRange(c, "Calories"); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Duration, err = parseRange(c, "Duration"); err != nil {
+		c.Error(err)
+		return
+	}
+
+	goal, err := callerGoal(c, h.store)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.store.GetWorkoutPackages(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	for i := range page.Packages {
+		page.Packages[i].SuitsGoal = page.Packages[i].GoalType.Suits(goal)
+	}
+	c.JSON(http.StatusOK, page)
 }
 
 // GetWorkoutPackage godoc
 // @Summary      Get a workout package by ID
-// @Description  Returns details of a specific workout package
+// @Description  Returns details of a specific workout package, with suitsGoal set for the caller's current goal. Private packages are visible only to their owner, and packages shared by link need their share token.
 // @Tags         workouts
 // @Produce      json
-// @Param        id   path      string  true  "Workout Package ID"
-// @Success      200  {object}  models.WorkoutPackage
-// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Param        id     path      string  true   "Workout Package ID"
+// @Param        token  query     string  false  "Share token of a package shared by link"
+// @Success      200    {object}  models.WorkoutPackage
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
 // @Router       /workouts/packages/{id} [get]
 func (h *WorkoutHandler) GetWorkoutPackage(c *gin.Context) {
-	id := c.Param("id")
-	pkg, err := h.store.GetWorkoutPackage(id)
+	goal, err := callerGoal(c, h.store)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
+
+	pkg, err := h.viewableWorkoutPackage(c, c.Param("id"), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	pkg.SuitsGoal = pkg.GoalType.Suits(goal)
 	c.JSON(http.StatusOK, pkg)
 }
 
 // workoutEntryRequest defines the structure for workout entry creation
 type workoutEntryRequest struct {
-	UserID              string  `json:"userId" binding:"required" example:"usr1"`
 	PackageID           string  `json:"packageId" binding:"required" example:"workout1"`
 	IntensityMultiplier float64 `json:"intensityMultiplier" binding:"required,min=0.5,max=2" example:"1.0"`
 	DurationMinutes     int     `json:"durationMinutes" binding:"required,min=5,max=180" example:"30"`
 	Date                string  `json:"date" binding:"required" example:"2023-03-18"`
+	ShareToken          string  `json:"shareToken"` // Needed for packages shared by link
 }
 
 // CreateWorkoutEntry godoc
 // @Summary      Create a new workout entry
-// @Description  Logs a workout for a user with specified intensity and duration
+// @Description  Logs a workout for the authenticated user with specified intensity and duration
 // @Tags         workouts
 // @Accept       json
 // @Produce      json
+// @Security     ApiKeyAuth
 // @Param        entry  body      workoutEntryRequest  true  "Workout entry details"
 // @Success      201    {object}  models.WorkoutEntry
-// @Failure      400    {object}  map[string]string
-// @Failure      500    {object}  map[string]string
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
 // @Router       /workouts/entries [post]
 func (h *WorkoutHandler) CreateWorkoutEntry(c *gin.Context) {
 	var req workoutEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(bindingError(err))
 		return
 	}
 
 	// Parse date
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		c.Error(db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD"))
 		return
 	}
 
 	// Get workout package
-	pkg, err := h.store.GetWorkoutPackage(req.PackageID)
+	pkg, err := h.loggableWorkoutPackage(c, req.PackageID, req.ShareToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workout package: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	// Get user information for calorie calculation
-	user, err := h.store.GetUser(req.UserID)
+	user, err := h.store.GetUser(c.Request.Context(), currentUserID(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
-	// Calculate calories burned based on workout package, duration, intensity, and user weight
-	// Note: In a real app, you would use the actual formula from the package
-	// For MVP, we'll use a simple formula
-	caloriesBurned := int(float64(pkg.BaseCaloriesBurn) *
-		(float64(req.DurationMinutes) / float64(pkg.BaseDurationMinutes)) *
-		req.IntensityMultiplier *
-		(user.Weight / 70.0)) // Adjust for user weight relative to 70kg reference
-
 	newEntry := models.WorkoutEntry{
 		ID:                  utils.GenerateID(),
-		UserID:              req.UserID,
+		UserID:              currentUserID(c),
 		PackageID:           req.PackageID,
 		IntensityMultiplier: req.IntensityMultiplier,
 		DurationMinutes:     req.DurationMinutes,
-		CaloriesBurned:      caloriesBurned,
 		Date:                date,
 		Timestamp:           time.Now(),
 		CreatedAt:           time.Now(),
 	}
+	applyWorkoutPackage(&newEntry, pkg, user)
 
 	// Save the entry
-	createdEntry, err := h.store.CreateWorkoutEntry(newEntry)
+	createdEntry, err := h.store.CreateWorkoutEntry(c.Request.Context(), newEntry)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save workout entry: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -148,22 +222,21 @@ func (h *WorkoutHandler) CreateWorkoutEntry(c *gin.Context) {
 }
 
 // GetWorkoutEntries godoc
-// @Summary      Get workout entries for a user
-// @Description  Returns workout entries for a user within a date range
+// @Summary      Get workout entries for the caller
+// @Description  Returns the authenticated user's workout entries within a date range
 // @Tags         workouts
 // @Produce      json
-// @Param        userId     query     string  true   "User ID"
+// @Security     ApiKeyAuth
 // @Param        startDate  query     string  false  "Start date (YYYY-MM-DD)"
 // @Param        endDate    query     string  false  "End date (YYYY-MM-DD)"
 // @Success      200        {array}   models.WorkoutEntry
-// @Failure      400        {object}  map[string]string
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
 // @Router       /workouts/entries [get]
 func (h *WorkoutHandler) GetWorkoutEntries(c *gin.Context) {
-	userID := c.Query("userId")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
-		return
-	}
+	userID := currentUserID(c)
 
 	// Parse start and end dates
 	startDateStr := c.DefaultQuery("startDate", time.Now().Format("2006-01-02"))
@@ -171,19 +244,211 @@ func (h *WorkoutHandler) GetWorkoutEntries(c *gin.Context) {
 
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid startDate format, use YYYY-MM-DD"})
+		c.Error(db.NewValidationError("startDate", "Invalid date format, use YYYY-MM-DD"))
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endDate format, use YYYY-MM-DD"})
+		c.Error(db.NewValidationError("endDate", "Invalid date format, use YYYY-MM-DD"))
 		return
 	}
 
 	// Make sure the end date is inclusive by setting it to the end of the day
 	endDate = endDate.Add(24*time.Hour - time.Second)
 
-	entries := h.store.GetWorkoutEntriesByUserAndDateRange(userID, startDate, endDate)
+	entries, err := h.store.GetWorkoutEntriesByUserAndDateRange(c.Request.Context(), userID, startDate, endDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 	c.JSON(http.StatusOK, entries)
 }
+
+// applyWorkoutPackage sets an entry's calories burned from its package, duration, intensity and the user's weight
+func applyWorkoutPackage(entry *models.WorkoutEntry, pkg models.WorkoutPackage, user models.User) {
+	entry.PackageVersion = pkg.Version
+	// Note: In a real app, you would use the actual formula from the package
+	// For MVP, we'll use a simple formula
+	entry.CaloriesBurned = int(float64(pkg.BaseCaloriesBurn) *
+		(float64(entry.DurationMinutes) / float64(pkg.BaseDurationMinutes)) *
+		entry.IntensityMultiplier *
+		(user.Weight / 70.0)) // Adjust for user weight relative to 70kg reference
+}
+
+// loggableWorkoutPackage fetches the current version of a package for a new entry, rejecting
+// archived packages and packages the caller may not see. token is the package's share token, if any.
+func (h *WorkoutHandler) loggableWorkoutPackage(c *gin.Context, id, token string) (models.WorkoutPackage, error) {
+	pkg, err := h.viewableWorkoutPackage(c, id, token)
+	if err != nil {
+		return models.WorkoutPackage{}, referenceError("packageId", err)
+	}
+	if pkg.Archived {
+		return models.WorkoutPackage{}, archivedPackageError(id)
+	}
+	return pkg, nil
+}
+
+// entryWorkoutPackage fetches the package version an entry was logged with.
+// Entries logged before versioning use the current version.
+func (h *WorkoutHandler) entryWorkoutPackage(c *gin.Context, entry models.WorkoutEntry) (models.WorkoutPackage, error) {
+	var pkg models.WorkoutPackage
+	var err error
+	if entry.PackageVersion == 0 {
+		pkg, err = h.store.GetWorkoutPackage(c.Request.Context(), entry.PackageID)
+	} else {
+		pkg, err = h.store.GetWorkoutPackageVersion(c.Request.Context(), entry.PackageID, entry.PackageVersion)
+	}
+	if err != nil {
+		return models.WorkoutPackage{}, referenceError("packageId", err)
+	}
+	return pkg, nil
+}
+
+// loadWorkoutEntry fetches a workout entry and checks that the caller may access it
+func (h *WorkoutHandler) loadWorkoutEntry(c *gin.Context) (models.WorkoutEntry, error) {
+	entry, err := h.store.GetWorkoutEntry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		return models.WorkoutEntry{}, err
+	}
+	if err := authorizeUser(c, entry.UserID); err != nil {
+		return models.WorkoutEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetWorkoutEntry godoc
+// @Summary      Get a workout entry by ID
+// @Description  Returns one of the authenticated user's workout entries
+// @Tags         workouts
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Workout Entry ID"
+// @Success      200  {object}  models.WorkoutEntry
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /workouts/entries/{id} [get]
+func (h *WorkoutHandler) GetWorkoutEntry(c *gin.Context) {
+	entry, err := h.loadWorkoutEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// updateWorkoutEntryRequest defines the fields of a workout entry that can be changed.
+// Omitted fields keep their current values.
+type updateWorkoutEntryRequest struct {
+	PackageID           *string  `json:"packageId" binding:"omitempty,min=1" example:"workout2"`
+	IntensityMultiplier *float64 `json:"intensityMultiplier" binding:"omitempty,min=0.5,max=2" example:"1.2"`
+	DurationMinutes     *int     `json:"durationMinutes" binding:"omitempty,min=5,max=180" example:"45"`
+	Date                *string  `json:"date" example:"2023-03-18"`
+	ShareToken          string   `json:"shareToken"` // Needed when changing to a package shared by link
+}
+
+// UpdateWorkoutEntry godoc
+// @Summary      Update a workout entry
+// @Description  Changes the package, intensity, duration or date of a workout entry. Calories burned are recomputed from the package version the entry was logged with, or from the current version when the package changes.
+// @Tags         workouts
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id     path      string                     true  "Workout Entry ID"
+// @Param        entry  body      updateWorkoutEntryRequest  true  "Fields to change"
+// @Success      200    {object}  models.WorkoutEntry
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      403    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /workouts/entries/{id} [patch]
+func (h *WorkoutHandler) UpdateWorkoutEntry(c *gin.Context) {
+	var req updateWorkoutEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	entry, err := h.loadWorkoutEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.Date != nil {
+		date, err := time.Parse("2006-01-02", *req.Date)
+		if err != nil {
+			c.Error(db.NewValidationError("date", "Invalid date format, use YYYY-MM-DD"))
+			return
+		}
+		entry.Date = date
+	}
+	if req.IntensityMultiplier != nil {
+		entry.IntensityMultiplier = *req.IntensityMultiplier
+	}
+	if req.DurationMinutes != nil {
+		entry.DurationMinutes = *req.DurationMinutes
+	}
+
+	// The entry keeps the package version it was logged with unless the package changes
+	var pkg models.WorkoutPackage
+	if req.PackageID != nil && *req.PackageID != entry.PackageID {
+		pkg, err = h.loggableWorkoutPackage(c, *req.PackageID, req.ShareToken)
+	} else {
+		pkg, err = h.entryWorkoutPackage(c, entry)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	entry.PackageID = pkg.ID
+
+	// Calories burned depend on the weight of the entry's owner
+	user, err := h.store.GetUser(c.Request.Context(), entry.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	applyWorkoutPackage(&entry, pkg, user)
+
+	updatedEntry, err := h.store.UpdateWorkoutEntry(c.Request.Context(), entry)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedEntry)
+}
+
+// DeleteWorkoutEntry godoc
+// @Summary      Delete a workout entry
+// @Description  Removes one of the authenticated user's workout entries
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Param        id   path  string  true  "Workout Entry ID"
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Router       /workouts/entries/{id} [delete]
+func (h *WorkoutHandler) DeleteWorkoutEntry(c *gin.Context) {
+	entry, err := h.loadWorkoutEntry(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := h.store.DeleteWorkoutEntry(c.Request.Context(), entry.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
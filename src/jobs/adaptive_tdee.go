@@ -0,0 +1,133 @@
+// Package jobs contains background work run alongside the API server
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/analytics"
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// userTimeout bounds the work done for a single user in one run
+const userTimeout = 10 * time.Second
+
+// EstimateTDEE loads a user's logged intake and weight entries for the
+// windowDays days ending yesterday and estimates their adaptive TDEE.
+// Today is left out because its meals are usually not all logged yet.
+func EstimateTDEE(ctx context.Context, store db.Store, user models.User, windowDays int, now time.Time) (models.TDEEEstimate, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	startDate := today.AddDate(0, 0, -windowDays)
+	endDate := today.Add(-time.Second)
+
+	totals, err := store.GetDailyTotals(ctx, user.ID, startDate, endDate)
+	if err != nil {
+		return models.TDEEEstimate{}, err
+	}
+
+	weights, err := store.GetWeightEntriesByUserAndDateRange(ctx, user.ID, startDate, endDate)
+	if err != nil {
+		return models.TDEEEstimate{}, err
+	}
+
+	return analytics.EstimateTDEE(startDate, endDate.Truncate(24*time.Hour), totals, weights, now), nil
+}
+
+// AdaptiveTDEEJob periodically refreshes every user's adaptive TDEE estimate
+// and recalculates the targets of users who opted in to adaptive targets
+type AdaptiveTDEEJob struct {
+	store    db.Store
+	interval time.Duration
+}
+
+// NewAdaptiveTDEEJob creates a job that runs every interval
+func NewAdaptiveTDEEJob(store db.Store, interval time.Duration) *AdaptiveTDEEJob {
+	return &AdaptiveTDEEJob{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Run refreshes the estimates immediately and then on every tick until ctx is cancelled
+func (j *AdaptiveTDEEJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("Adaptive TDEE job failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce refreshes the estimate of every user with logged data.
+// Failures for one user are logged and do not stop the run.
+func (j *AdaptiveTDEEJob) RunOnce(ctx context.Context) error {
+	users, err := j.store.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var updated int
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		changed, err := j.refreshUser(ctx, user)
+		if err != nil {
+			log.Printf("Adaptive TDEE job: user %s: %v", user.ID, err)
+			continue
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	log.Printf("Adaptive TDEE job: refreshed %d of %d users", updated, len(users))
+	return nil
+}
+
+// refreshUser stores a new estimate on the user and reapplies their targets.
+// Users with nothing logged in the window and no earlier estimate are left untouched.
+// The user loaded at the start of the run may be stale by now, so the targets are
+// calculated from a fresh copy and only the estimate and targets are written back.
+func (j *AdaptiveTDEEJob) refreshUser(ctx context.Context, user models.User) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, userTimeout)
+	defer cancel()
+
+	now := time.Now()
+	estimate, err := EstimateTDEE(ctx, j.store, user, analytics.DefaultTDEEWindowDays, now)
+	if err != nil {
+		return false, err
+	}
+	if user.AdaptiveTDEE == nil && estimate.IntakeDays == 0 && estimate.WeighIns == 0 {
+		return false, nil
+	}
+
+	user, err = j.store.GetUser(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	user.AdaptiveTDEE = &estimate
+
+	var targets *models.GoalInfo
+	if !user.Goal.IsManual() {
+		nutrition.ApplyTargets(&user, now)
+		targets = &user.Goal
+	}
+
+	if _, err := j.store.UpdateAdaptiveTDEE(ctx, user.ID, estimate, targets); err != nil {
+		return false, err
+	}
+	return true, nil
+}
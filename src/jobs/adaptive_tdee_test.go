@@ -0,0 +1,250 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// newTestUser creates a user with AUTO targets, calculated from their profile
+func newTestUser(t *testing.T, store db.Store, id string, adaptive bool) models.User {
+	t.Helper()
+	user := models.User{
+		ID:              id,
+		Email:           id + "@example.com",
+		Password:        "hash",
+		Gender:          models.GenderMale,
+		BirthDate:       time.Now().AddDate(-30, 0, -1),
+		Height:          180,
+		Weight:          80,
+		ActivityLevel:   models.ActivityModerate,
+		AdaptiveTargets: adaptive,
+		Goal:            models.GoalInfo{Type: models.GoalTypeLose},
+	}
+	nutrition.ApplyTargets(&user, time.Now())
+	created, err := store.CreateUser(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+// logWindow logs 2500 kcal on each day of the estimate window and a weight that
+// falls 0.05 kg a day, which puts the TDEE at 2500 + 0.05 * 7700 = 2885 kcal
+func logWindow(t *testing.T, store db.Store, userID string) {
+	t.Helper()
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := 1; i <= 28; i++ {
+		day := today.AddDate(0, 0, -i)
+		if _, err := store.CreateMealEntry(ctx, models.MealEntry{UserID: userID, Custom: true, Calories: 2500, Date: day}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.CreateWeightEntry(ctx, models.WeightEntry{UserID: userID, Weight: 80 + 0.05*float64(i), Date: day}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAdaptiveTDEEJobAppliesEstimate(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	newTestUser(t, store, "adaptive", true)
+	logWindow(t, store, "adaptive")
+	idle := newTestUser(t, store, "idle", true)
+
+	if err := NewAdaptiveTDEEJob(store, time.Hour).RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	user, err := store.GetUser(ctx, "adaptive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.AdaptiveTDEE == nil || !user.AdaptiveTDEE.Sufficient || user.AdaptiveTDEE.TDEE != 2885 {
+		t.Fatalf("AdaptiveTDEE = %+v, want a sufficient estimate of 2885 kcal", user.AdaptiveTDEE)
+	}
+	if !user.Goal.AdaptiveTDEE || user.Goal.TDEE != 2885 || user.Goal.TargetCalories != 2385 {
+		t.Errorf("goal = adaptive %v TDEE %d, %d kcal; want adaptive TDEE 2885, 2385 kcal",
+			user.Goal.AdaptiveTDEE, user.Goal.TDEE, user.Goal.TargetCalories)
+	}
+
+	// Users with nothing logged are left alone
+	unchanged, err := store.GetUser(ctx, "idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchanged.AdaptiveTDEE != nil || unchanged.Goal != idle.Goal {
+		t.Errorf("user without logs was changed: %+v", unchanged)
+	}
+}
+
+func TestAdaptiveTDEEJobKeepsManualTargets(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	user := newTestUser(t, store, "manual", true)
+	user.Goal.TargetMode = models.TargetModeManual
+	user.Goal.TargetCalories = 2100
+	if _, err := store.UpdateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	logWindow(t, store, "manual")
+
+	if err := NewAdaptiveTDEEJob(store, time.Hour).RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	got, err := store.GetUser(ctx, "manual")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AdaptiveTDEE == nil || got.AdaptiveTDEE.TDEE != 2885 {
+		t.Errorf("AdaptiveTDEE = %+v, want an estimate of 2885 kcal", got.AdaptiveTDEE)
+	}
+	if got.Goal != user.Goal {
+		t.Errorf("MANUAL goal changed to %+v, want %+v", got.Goal, user.Goal)
+	}
+}
+
+// racingStore changes the user while the job is estimating, as a request
+// handled during the run would
+type racingStore struct {
+	*db.MemoryStore
+	change func()
+}
+
+func (s *racingStore) GetDailyTotals(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyTotals, error) {
+	if s.change != nil {
+		s.change()
+		s.change = nil
+	}
+	return s.MemoryStore.GetDailyTotals(ctx, userID, startDate, endDate)
+}
+
+func TestAdaptiveTDEEJobKeepsConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{MemoryStore: db.NewMemoryStore()}
+	newTestUser(t, store, "racing", true)
+	logWindow(t, store, "racing")
+
+	loginAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	store.change = func() {
+		user, err := store.GetUser(ctx, "racing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		user.Weight = 90
+		if _, err := store.UpdateUser(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.ChangePassword(ctx, "racing", "new-hash"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.RecordLogin(ctx, "racing", loginAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := NewAdaptiveTDEEJob(store, time.Hour).RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	got, err := store.GetUser(ctx, "racing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Password != "new-hash" || got.TokenVersion != 1 || got.Weight != 90 || !got.LastLoginAt.Equal(loginAt) {
+		t.Errorf("changes made during the run were reverted: password %q, token version %d, weight %v, last login %v",
+			got.Password, got.TokenVersion, got.Weight, got.LastLoginAt)
+	}
+	if got.AdaptiveTDEE == nil || got.AdaptiveTDEE.TDEE != 2885 {
+		t.Fatalf("AdaptiveTDEE = %+v, want an estimate of 2885 kcal", got.AdaptiveTDEE)
+	}
+
+	// Protein targets follow body weight, so they must come from the new weight
+	want := nutrition.Macros(2385, 90, models.GoalTypeLose, "", nil)
+	if got.Goal.TargetProtein != want.Protein {
+		t.Errorf("protein target = %d g, want %d g for the new weight", got.Goal.TargetProtein, want.Protein)
+	}
+}
+
+// lateChangeStore changes the user right before the job writes its estimate,
+// after the job has read the user for the last time
+type lateChangeStore struct {
+	*db.MemoryStore
+	change func(user *models.User)
+}
+
+func (s *lateChangeStore) UpdateAdaptiveTDEE(ctx context.Context, id string, estimate models.TDEEEstimate, targets *models.GoalInfo) (models.User, error) {
+	user, err := s.MemoryStore.GetUser(ctx, id)
+	if err != nil {
+		return models.User{}, err
+	}
+	s.change(&user)
+	if _, err := s.MemoryStore.UpdateUser(ctx, user); err != nil {
+		return models.User{}, err
+	}
+	return s.MemoryStore.UpdateAdaptiveTDEE(ctx, id, estimate, targets)
+}
+
+func TestAdaptiveTDEEJobKeepsLateManualTargets(t *testing.T) {
+	ctx := context.Background()
+	store := &lateChangeStore{MemoryStore: db.NewMemoryStore()}
+	newTestUser(t, store, "late", true)
+	logWindow(t, store, "late")
+
+	var manual models.GoalInfo
+	store.change = func(user *models.User) {
+		user.Goal.TargetMode = models.TargetModeManual
+		user.Goal.TargetCalories = 2100
+		user.Goal.TargetProtein = 150
+		manual = user.Goal
+	}
+
+	if err := NewAdaptiveTDEEJob(store, time.Hour).RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	got, err := store.GetUser(ctx, "late")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Goal != manual {
+		t.Errorf("MANUAL goal set during the run changed to %+v, want %+v", got.Goal, manual)
+	}
+	if got.AdaptiveTDEE == nil || got.AdaptiveTDEE.TDEE != 2885 {
+		t.Errorf("AdaptiveTDEE = %+v, want an estimate of 2885 kcal", got.AdaptiveTDEE)
+	}
+}
+
+func TestAdaptiveTDEEJobKeepsLateFormulaAndMacroProfile(t *testing.T) {
+	ctx := context.Background()
+	store := &lateChangeStore{MemoryStore: db.NewMemoryStore()}
+	newTestUser(t, store, "late", true)
+	logWindow(t, store, "late")
+
+	store.change = func(user *models.User) {
+		user.Goal.Formula = models.FormulaKatchMcArdle
+		user.Goal.MacroProfile = models.MacroKeto
+	}
+
+	if err := NewAdaptiveTDEEJob(store, time.Hour).RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	got, err := store.GetUser(ctx, "late")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Goal.Formula != models.FormulaKatchMcArdle || got.Goal.MacroProfile != models.MacroKeto {
+		t.Errorf("formula %s, macro profile %s; want the KATCH_MCARDLE and KETO set during the run",
+			got.Goal.Formula, got.Goal.MacroProfile)
+	}
+	if got.Goal.TDEE != 2885 || got.Goal.TargetCalories != 2385 {
+		t.Errorf("goal TDEE %d, %d kcal; want the adaptive targets 2885, 2385 kcal", got.Goal.TDEE, got.Goal.TargetCalories)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Nutrients are the nutrition values of an amount of food
+type Nutrients struct {
+	Calories float64 `json:"calories" bson:"calories" example:"165"`
+	Protein  float64 `json:"protein" bson:"protein" example:"31"`                   // Grams
+	Carbs    float64 `json:"carbs" bson:"carbs" example:"0"`                        // Grams
+	Fat      float64 `json:"fat" bson:"fat" example:"3.6"`                          // Grams
+	Fiber    float64 `json:"fiber,omitempty" bson:"fiber,omitempty" example:"0"`    // Grams
+	Sugar    float64 `json:"sugar,omitempty" bson:"sugar,omitempty" example:"0"`    // Grams
+	Sodium   float64 `json:"sodium,omitempty" bson:"sodium,omitempty" example:"74"` // Milligrams
+}
+
+// FoodSource is the dataset a food was imported from
+type FoodSource string
+
+// Food sources
+const (
+	FoodSourceOpenFoodFacts FoodSource = "OPEN_FOOD_FACTS"
+	FoodSourceUSDA          FoodSource = "USDA_FDC"
+)
+
+// Food is an ingredient with its nutrition per 100 g. Meal package recipes combine foods.
+type Food struct {
+	ID           string     `json:"foodId" bson:"_id"`
+	Name         string     `json:"name" bson:"name" example:"Chicken breast, cooked"`
+	Brand        string     `json:"brand,omitempty" bson:"brand,omitempty" example:"Acme"`
+	Barcode      string     `json:"barcode,omitempty" bson:"barcode,omitempty" example:"0036000291452"` // Normalized GTIN, see package gtin
+	Per100g      Nutrients  `json:"per100g" bson:"per100g"`
+	ServingGrams float64    `json:"servingGrams,omitempty" bson:"servingGrams,omitempty" example:"30"` // Labelled serving size, when known
+	Source       FoodSource `json:"source,omitempty" bson:"source,omitempty"`                          // Empty for foods added through the API
+	SourceID     string     `json:"sourceId,omitempty" bson:"sourceId,omitempty" example:"171077"`     // The food's ID in its source
+	CreatedAt    *time.Time `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// BarcodeFoodID is the ID of the food with a barcode, shared by imports and the API
+// so that both update the same food
+func BarcodeFoodID(barcode string) string {
+	return "gtin-" + barcode
+}
+
+// MergeImport returns the food updated with an imported copy of it: name and
+// nutrition are replaced, and the other fields only when the import has them
+func (f Food) MergeImport(imported Food) Food {
+	f.Name = imported.Name
+	f.Per100g = imported.Per100g
+	if imported.Brand != "" {
+		f.Brand = imported.Brand
+	}
+	if imported.Barcode != "" {
+		f.Barcode = imported.Barcode
+	}
+	if imported.ServingGrams > 0 {
+		f.ServingGrams = imported.ServingGrams
+	}
+	if imported.Source != "" {
+		f.Source = imported.Source
+		f.SourceID = imported.SourceID
+	}
+	return f
+}
+
+// FoodPage is one page of a food list
+type FoodPage struct {
+	Foods      []Food `json:"foods"`
+	Total      int64  `json:"total" example:"42"`                              // Foods matching the search, across all pages
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJzIjoibmFtZSJ9"` // Pass as cursor to get the next page; omitted on the last page
+}
+
+// FoodServing is a food with the nutrition of one serving
+type FoodServing struct {
+	Food         Food      `json:"food"`
+	ServingGrams float64   `json:"servingGrams" example:"30"` // The labelled serving, or 100 g when the food has none
+	PerServing   Nutrients `json:"perServing"`
+}
+
+// RecipeItem is an amount of one food in a meal package's recipe
+type RecipeItem struct {
+	FoodID string  `json:"foodId" bson:"foodId" example:"chicken-breast"`
+	Grams  float64 `json:"grams" bson:"grams" example:"150"`
+}
@@ -15,32 +15,55 @@ const (
 
 // MealPackage represents a predefined meal package in the system
 type MealPackage struct {
-	ID               string   `json:"packageId" bson:"_id"`
-	Name             string   `json:"name" bson:"name"`
-	Description      string   `json:"description" bson:"description"`
-	GoalType         GoalType `json:"goalType" bson:"goalType"` // LOSE, GAIN, or BOTH
-	MealType         MealType `json:"mealType" bson:"mealType"`
-	BaseCalories     int      `json:"baseCalories" bson:"baseCalories"`
-	BaseProtein      int      `json:"baseProtein" bson:"baseProtein"`
-	BaseCarbs        int      `json:"baseCarbs" bson:"baseCarbs"`
-	BaseFat          int      `json:"baseFat" bson:"baseFat"`
-	ImageURL         string   `json:"imageUrl" bson:"imageUrl"`
-	PreparationSteps []string `json:"preparationSteps,omitempty" bson:"preparationSteps,omitempty"`
-	Ingredients      []string `json:"ingredients,omitempty" bson:"ingredients,omitempty"`
+	ID               string            `json:"packageId" bson:"_id"`
+	Name             string            `json:"name" bson:"name"`
+	Description      string            `json:"description" bson:"description"`
+	GoalType         GoalType          `json:"goalType" bson:"goalType" enums:"LOSE,GAIN,BOTH"` // BOTH suits either goal
+	MealType         MealType          `json:"mealType" bson:"mealType"`
+	BaseCalories     int               `json:"baseCalories" bson:"baseCalories"`
+	BaseProtein      int               `json:"baseProtein" bson:"baseProtein"`
+	BaseCarbs        int               `json:"baseCarbs" bson:"baseCarbs"`
+	BaseFat          int               `json:"baseFat" bson:"baseFat"`
+	ImageURL         string            `json:"imageUrl" bson:"imageUrl"`
+	PreparationSteps []string          `json:"preparationSteps,omitempty" bson:"preparationSteps,omitempty"`
+	Ingredients      []string          `json:"ingredients,omitempty" bson:"ingredients,omitempty"` // Derived from the recipe when there is one
+	Recipe           []RecipeItem      `json:"recipe,omitempty" bson:"recipe,omitempty"`           // When set, calories and macros are computed from it
+	OwnerID          string            `json:"ownerId,omitempty" bson:"ownerId,omitempty"`         // User who owns the package; empty for catalog packages
+	Visibility       PackageVisibility `json:"visibility,omitempty" bson:"visibility,omitempty"`   // Set for user-owned packages
+	ShareToken       string            `json:"shareToken,omitempty" bson:"shareToken,omitempty"`   // Grants access to LINK packages
+	Version          int               `json:"version" bson:"version" example:"1"`                 // Incremented on every update; entries record the version they were logged with
+	Archived         bool              `json:"archived" bson:"archived,omitempty"`                 // Archived packages are hidden from lists and cannot be logged
+	ArchivedAt       *time.Time        `json:"archivedAt,omitempty" bson:"archivedAt,omitempty"`
+	CreatedAt        *time.Time        `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt        *time.Time        `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	SuitsGoal        bool              `json:"suitsGoal" bson:"-"` // Whether the package fits the requesting user's current goal; set per request
+}
+
+// MealPackagePage is one page of a meal package list
+type MealPackagePage struct {
+	Packages   []MealPackage `json:"packages"`
+	Total      int64         `json:"total" example:"42"`                              // Packages matching the filters, across all pages
+	NextCursor string        `json:"nextCursor,omitempty" example:"eyJzIjoibmFtZSJ9"` // Pass as cursor to get the next page; omitted on the last page
 }
 
 // MealEntry represents a logged meal by a user
 type MealEntry struct {
-	ID                string    `json:"entryId" bson:"_id"`
-	UserID            string    `json:"userId" bson:"userId"`
-	PackageID         string    `json:"packageId" bson:"packageId"`
-	PortionMultiplier float64   `json:"portionMultiplier" bson:"portionMultiplier"`
-	Calories          int       `json:"calories" bson:"calories"`
-	Protein           int       `json:"protein" bson:"protein"`
-	Carbs             int       `json:"carbs" bson:"carbs"`
-	Fat               int       `json:"fat" bson:"fat"`
-	MealType          MealType  `json:"mealType" bson:"mealType"`
-	Date              time.Time `json:"date" bson:"date"`
-	Timestamp         time.Time `json:"timestamp" bson:"timestamp"` // Used for querying by time range
-	CreatedAt         time.Time `json:"createdAt" bson:"createdAt"`
+	ID                string     `json:"entryId" bson:"_id"`
+	UserID            string     `json:"userId" bson:"userId"`
+	PackageID         string     `json:"packageId" bson:"packageId"`                               // Empty for custom and food entries
+	PackageVersion    int        `json:"packageVersion,omitempty" bson:"packageVersion,omitempty"` // Package version the nutrition values come from; 0 for entries logged before versioning
+	Custom            bool       `json:"custom" bson:"custom,omitempty"`                           // Entered manually rather than from a package
+	FoodID            string     `json:"foodId,omitempty" bson:"foodId,omitempty"`                 // Food a food entry was logged from, e.g. by barcode
+	ServingGrams      float64    `json:"servingGrams,omitempty" bson:"servingGrams,omitempty"`     // Grams in one serving of a food entry
+	PerServing        *Nutrients `json:"perServing,omitempty" bson:"perServing,omitempty"`         // Nutrition of one serving of a food entry, as the food was when logged
+	Name              string     `json:"name,omitempty" bson:"name,omitempty"`                     // Name of a custom or food entry
+	PortionMultiplier float64    `json:"portionMultiplier" bson:"portionMultiplier"`               // Number of servings for food entries
+	Calories          int        `json:"calories" bson:"calories"`
+	Protein           int        `json:"protein" bson:"protein"`
+	Carbs             int        `json:"carbs" bson:"carbs"`
+	Fat               int        `json:"fat" bson:"fat"`
+	MealType          MealType   `json:"mealType" bson:"mealType"`
+	Date              time.Time  `json:"date" bson:"date"`           // Day the entry applies to; used for querying by date range
+	Timestamp         time.Time  `json:"timestamp" bson:"timestamp"` // When the entry was logged
+	CreatedAt         time.Time  `json:"createdAt" bson:"createdAt"`
 }
@@ -0,0 +1,17 @@
+package models
+
+// PackageVisibility controls who can see a package owned by a user.
+// Catalog packages have no owner and no visibility; everyone can see them.
+type PackageVisibility string
+
+// Package visibilities
+const (
+	VisibilityPrivate PackageVisibility = "PRIVATE" // Only the owner
+	VisibilityLink    PackageVisibility = "LINK"    // Anyone with the package's share token
+	VisibilityPublic  PackageVisibility = "PUBLIC"  // Everyone; listed alongside the catalog
+)
+
+// Valid reports whether v is a known visibility
+func (v PackageVisibility) Valid() bool {
+	return v == VisibilityPrivate || v == VisibilityLink || v == VisibilityPublic
+}
@@ -0,0 +1,44 @@
+package models
+
+// ProjectionBasis names the calorie balance a goal projection is based on
+type ProjectionBasis string
+
+// Projection bases
+const (
+	ProjectionLogged  ProjectionBasis = "LOGGED"  // Average balance of recently logged days
+	ProjectionPlanned ProjectionBasis = "PLANNED" // Calorie target minus TDEE, used when too few days are logged
+)
+
+// ProjectionWarning flags a projection that is unsafe or unlikely to reach the target
+type ProjectionWarning struct {
+	Code    string `json:"code" example:"RATE_TOO_FAST"`
+	Message string `json:"message" example:"Losing 1.2% of body weight per week exceeds the safe rate of 1%"`
+}
+
+// ProjectionPoint is the projected weight at the end of a week
+type ProjectionPoint struct {
+	Week   int     `json:"week" example:"1"`
+	Date   string  `json:"date" example:"2023-03-25"`
+	Weight float64 `json:"weight" example:"79.4"`
+}
+
+// GoalProjection estimates when a user will reach their target weight
+type GoalProjection struct {
+	UserID              string              `json:"userId"`
+	CurrentWeight       float64             `json:"currentWeight" example:"80"`
+	TargetWeight        float64             `json:"targetWeight" example:"72"`
+	Basis               ProjectionBasis     `json:"basis" example:"LOGGED"`
+	TDEE                int                 `json:"tdee" example:"2600"`                 // Maintenance calories the balance is measured against
+	DailyBalance        int                 `json:"dailyBalance" example:"-450"`         // kcal per day the projection uses; negative is a deficit
+	PlannedDailyBalance int                 `json:"plannedDailyBalance" example:"-500"`  // Calorie target minus TDEE
+	LoggedDays          int                 `json:"loggedDays" example:"21"`             // Days with logged meals in the window
+	WindowDays          int                 `json:"windowDays" example:"28"`             // Days of logs the balance is averaged over
+	EnergyDensity       int                 `json:"energyDensity" example:"7700"`        // kcal per kg of weight change
+	WeeklyChange        float64             `json:"weeklyChange" example:"-0.41"`        // kg per week
+	WeeklyChangePercent float64             `json:"weeklyChangePercent" example:"-0.51"` // Percent of current weight per week
+	Reachable           bool                `json:"reachable"`                           // Whether the target is reached within the projection horizon
+	WeeksToTarget       float64             `json:"weeksToTarget,omitempty" example:"19.5"`
+	ProjectedDate       string              `json:"projectedDate,omitempty" example:"2023-08-01"`
+	Curve               []ProjectionPoint   `json:"curve"`
+	Warnings            []ProjectionWarning `json:"warnings"`
+}
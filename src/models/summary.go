@@ -0,0 +1,87 @@
+package models
+
+// CalorieBalance summarizes a day's calorie intake and expenditure against the target
+type CalorieBalance struct {
+	Target        int     `json:"target"`
+	Consumed      int     `json:"consumed"`
+	Burned        int     `json:"burned"`
+	Net           int     `json:"net"`           // Consumed minus burned
+	Remaining     int     `json:"remaining"`     // Target minus net; negative when over target
+	PercentOfGoal float64 `json:"percentOfGoal"` // Net as a percentage of target
+}
+
+// MacroBalance summarizes a day's intake of one macronutrient in grams
+type MacroBalance struct {
+	Target        int     `json:"target"`
+	Consumed      int     `json:"consumed"`
+	Remaining     int     `json:"remaining"`
+	PercentOfGoal float64 `json:"percentOfGoal"`
+}
+
+// DailySummary is the calorie balance dashboard for a single day
+type DailySummary struct {
+	UserID       string         `json:"userId"`
+	Date         string         `json:"date" example:"2023-03-18"`
+	GoalType     GoalType       `json:"goalType"`
+	Calories     CalorieBalance `json:"calories"`
+	Protein      MacroBalance   `json:"protein"`
+	Carbs        MacroBalance   `json:"carbs"`
+	Fat          MacroBalance   `json:"fat"`
+	MealCount    int            `json:"mealCount"`
+	WorkoutCount int            `json:"workoutCount"`
+}
+
+// DailyTotals aggregates all meal and workout entries logged for one day
+type DailyTotals struct {
+	Date         string `json:"date" bson:"_id"` // YYYY-MM-DD
+	Calories     int    `json:"calories" bson:"calories"`
+	Protein      int    `json:"protein" bson:"protein"`
+	Carbs        int    `json:"carbs" bson:"carbs"`
+	Fat          int    `json:"fat" bson:"fat"`
+	Burned       int    `json:"burned" bson:"burned"`
+	MealCount    int    `json:"mealCount" bson:"mealCount"`
+	WorkoutCount int    `json:"workoutCount" bson:"workoutCount"`
+}
+
+// TrendDay is one day in a trend series. Days without any logs are included with zero values.
+type TrendDay struct {
+	Date    string `json:"date" example:"2023-03-18"`
+	Intake  int    `json:"intake"`
+	Burned  int    `json:"burned"`
+	Net     int    `json:"net"`     // Intake minus burned
+	Balance int    `json:"balance"` // Net minus target: positive is a surplus, negative a deficit
+	Protein int    `json:"protein"`
+	Carbs   int    `json:"carbs"`
+	Fat     int    `json:"fat"`
+	HasLogs bool   `json:"hasLogs"`
+	Target  int    `json:"target"`
+}
+
+// TrendWeek aggregates consecutive seven-day blocks of a trend.
+// Averages are taken over days with logs so that unlogged days do not look like deficits.
+type TrendWeek struct {
+	StartDate      string  `json:"startDate" example:"2023-03-12"`
+	EndDate        string  `json:"endDate" example:"2023-03-18"`
+	LoggedDays     int     `json:"loggedDays"`
+	AverageIntake  float64 `json:"averageIntake"`
+	AverageBurned  float64 `json:"averageBurned"`
+	AverageNet     float64 `json:"averageNet"`
+	AverageBalance float64 `json:"averageBalance"` // Average daily surplus (positive) or deficit (negative)
+}
+
+// MacroSplit is the share of calories from each macronutrient, in percent
+type MacroSplit struct {
+	ProteinPercent float64 `json:"proteinPercent" bson:"proteinPercent" example:"30"`
+	CarbsPercent   float64 `json:"carbsPercent" bson:"carbsPercent" example:"40"`
+	FatPercent     float64 `json:"fatPercent" bson:"fatPercent" example:"30"`
+}
+
+// TrendReport is the calorie balance trend for a user over a date range
+type TrendReport struct {
+	UserID     string      `json:"userId"`
+	StartDate  string      `json:"startDate" example:"2023-03-12"`
+	EndDate    string      `json:"endDate" example:"2023-03-18"`
+	Days       []TrendDay  `json:"days"`
+	Weeks      []TrendWeek `json:"weeks"`
+	MacroSplit MacroSplit  `json:"macroSplit"`
+}
@@ -13,56 +13,178 @@ type ActivityLevel string
 // GoalType represents the user's fitness goal type
 type GoalType string
 
+// Role represents the user's permission level
+type Role string
+
+// BMRFormula names the equation used to estimate basal metabolic rate
+type BMRFormula string
+
+// MacroProfile names a rule for splitting a calorie target into macronutrients
+type MacroProfile string
+
+// TargetMode controls whether goal targets are calculated or set by the user
+type TargetMode string
+
 // Constants for Gender, ActivityLevel, and GoalType
 const (
 	GenderMale   Gender = "MALE"
 	GenderFemale Gender = "FEMALE"
 	GenderOther  Gender = "OTHER"
 
-	ActivityLow      ActivityLevel = "LOW"
-	ActivityModerate ActivityLevel = "MODERATE"
-	ActivityHigh     ActivityLevel = "HIGH"
+	ActivitySedentary ActivityLevel = "SEDENTARY"
+	ActivityLow       ActivityLevel = "LOW"
+	ActivityModerate  ActivityLevel = "MODERATE"
+	ActivityHigh      ActivityLevel = "HIGH"
+	ActivityVeryHigh  ActivityLevel = "VERY_HIGH"
 
 	GoalTypeLose GoalType = "LOSE"
 	GoalTypeGain GoalType = "GAIN"
-	GoalTypeAll  GoalType = "" // Used for filtering all goal types
+	GoalTypeBoth GoalType = "BOTH" // Packages only: suits either goal
+	GoalTypeAll  GoalType = ""     // Used for filtering all goal types
+
+	RoleUser  Role = "USER"
+	RoleAdmin Role = "ADMIN"
+
+	FormulaHarrisBenedict BMRFormula = "HARRIS_BENEDICT"
+	FormulaMifflinStJeor  BMRFormula = "MIFFLIN_ST_JEOR"
+	FormulaKatchMcArdle   BMRFormula = "KATCH_MCARDLE"
+
+	MacroBalanced       MacroProfile = "BALANCED"
+	MacroHighProteinCut MacroProfile = "HIGH_PROTEIN_CUT"
+	MacroLeanBulk       MacroProfile = "LEAN_BULK"
+	MacroKeto           MacroProfile = "KETO"
+	MacroLowFat         MacroProfile = "LOW_FAT"
+	MacroCustom         MacroProfile = "CUSTOM" // User-supplied percentages
+
+	TargetModeAuto   TargetMode = "AUTO"   // Targets are recalculated when body metrics or the goal change
+	TargetModeManual TargetMode = "MANUAL" // Targets are pinned by the user and never recalculated
 )
 
 // GoalInfo represents a user's fitness goal
 type GoalInfo struct {
-	Type           GoalType  `json:"type" bson:"type"`
-	TargetCalories int       `json:"targetCalories" bson:"targetCalories"`
-	TargetProtein  int       `json:"targetProtein" bson:"targetProtein"`
-	TargetCarbs    int       `json:"targetCarbs" bson:"targetCarbs"`
-	TargetFat      int       `json:"targetFat" bson:"targetFat"`
-	StartDate      time.Time `json:"startDate" bson:"startDate"`
-	StartWeight    float64   `json:"startWeight,omitempty" bson:"startWeight,omitempty"`
-	TargetWeight   float64   `json:"targetWeight,omitempty" bson:"targetWeight,omitempty"`
+	Type           GoalType     `json:"type" bson:"type"`
+	TargetMode     TargetMode   `json:"targetMode" bson:"targetMode,omitempty"` // Empty is treated as AUTO
+	TargetCalories int          `json:"targetCalories" bson:"targetCalories"`
+	TargetProtein  int          `json:"targetProtein" bson:"targetProtein"`
+	TargetCarbs    int          `json:"targetCarbs" bson:"targetCarbs"`
+	TargetFat      int          `json:"targetFat" bson:"targetFat"`
+	Formula        BMRFormula   `json:"formula,omitempty" bson:"formula,omitempty"`           // Formula that produced the AUTO targets
+	MacroProfile   MacroProfile `json:"macroProfile,omitempty" bson:"macroProfile,omitempty"` // Macro profile that produced the AUTO targets
+	BMR            int          `json:"bmr,omitempty" bson:"bmr,omitempty"`
+	TDEE           int          `json:"tdee,omitempty" bson:"tdee,omitempty"`
+	AdaptiveTDEE   bool         `json:"adaptiveTdee,omitempty" bson:"adaptiveTdee,omitempty"` // TDEE comes from the adaptive estimate rather than the formula
+	StartDate      time.Time    `json:"startDate" bson:"startDate"`
+	StartWeight    float64      `json:"startWeight,omitempty" bson:"startWeight,omitempty"`
+	TargetWeight   float64      `json:"targetWeight,omitempty" bson:"targetWeight,omitempty"`
 }
 
 // User represents a user in the system
 type User struct {
-	ID            string        `json:"userId" bson:"_id"`
-	Name          string        `json:"name" bson:"name"`
-	Email         string        `json:"email" bson:"email"`
-	Password      string        `json:"-" bson:"password"` // Never expose password
-	Gender        Gender        `json:"gender" bson:"gender"`
-	BirthDate     time.Time     `json:"birthDate" bson:"birthDate"`
-	CreatedAt     time.Time     `json:"createdAt" bson:"createdAt"`
-	LastLoginAt   time.Time     `json:"lastLoginAt" bson:"lastLoginAt"`
-	Height        float64       `json:"height" bson:"height"`
-	Weight        float64       `json:"weight" bson:"weight"`
-	ActivityLevel ActivityLevel `json:"activityLevel" bson:"activityLevel"`
-	Goal          GoalInfo      `json:"goal" bson:"goal"`
+	ID              string        `json:"userId" bson:"_id"`
+	Name            string        `json:"name" bson:"name"`
+	Email           string        `json:"email" bson:"email"`
+	Password        string        `json:"-" bson:"password"`               // Never expose password
+	TokenVersion    int           `json:"-" bson:"tokenVersion,omitempty"` // Incremented on password change; tokens of older versions are rejected
+	Role            Role          `json:"role" bson:"role"`
+	Gender          Gender        `json:"gender" bson:"gender"`
+	BirthDate       time.Time     `json:"birthDate" bson:"birthDate"`
+	CreatedAt       time.Time     `json:"createdAt" bson:"createdAt"`
+	LastLoginAt     time.Time     `json:"lastLoginAt" bson:"lastLoginAt"`
+	Height          float64       `json:"height" bson:"height"`
+	Weight          float64       `json:"weight" bson:"weight"`
+	BodyFatPercent  float64       `json:"bodyFatPercent,omitempty" bson:"bodyFatPercent,omitempty"`
+	ActivityLevel   ActivityLevel `json:"activityLevel" bson:"activityLevel"`
+	BMRFormula      BMRFormula    `json:"bmrFormula,omitempty" bson:"bmrFormula,omitempty"`     // Empty selects the default formula
+	MacroProfile    MacroProfile  `json:"macroProfile,omitempty" bson:"macroProfile,omitempty"` // Empty selects a profile for the goal type
+	CustomMacros    *MacroSplit   `json:"customMacros,omitempty" bson:"customMacros,omitempty"` // Percentages for the CUSTOM profile
+	AdaptiveTargets bool          `json:"adaptiveTargets" bson:"adaptiveTargets,omitempty"`     // Base AUTO targets on the adaptive TDEE estimate when it is confident
+	AdaptiveTDEE    *TDEEEstimate `json:"adaptiveTdee,omitempty" bson:"adaptiveTdee,omitempty"` // Latest estimate from the background job
+	Goal            GoalInfo      `json:"goal" bson:"goal"`
+}
+
+// Suits reports whether a package with this goal type fits a user pursuing goal
+func (g GoalType) Suits(goal GoalType) bool {
+	return g == goal || g == GoalTypeBoth
+}
+
+// WithTargets returns the goal with the calculated targets of another: the calorie
+// and macro targets and the BMR and TDEE they came from. The target mode, formula,
+// macro profile, goal type, dates and weights are the user's and are kept.
+func (g GoalInfo) WithTargets(targets GoalInfo) GoalInfo {
+	g.TargetCalories = targets.TargetCalories
+	g.TargetProtein = targets.TargetProtein
+	g.TargetCarbs = targets.TargetCarbs
+	g.TargetFat = targets.TargetFat
+	g.BMR = targets.BMR
+	g.TDEE = targets.TDEE
+	g.AdaptiveTDEE = targets.AdaptiveTDEE
+	return g
 }
+
+// IsManual reports whether the goal targets are pinned by the user
+func (g GoalInfo) IsManual() bool {
+	return g.TargetMode == TargetModeManual
+}
+
+// MacroTargets are daily macronutrient targets derived from a calorie target and a macro profile
+type MacroTargets struct {
+	Profile  MacroProfile `json:"profile" example:"HIGH_PROTEIN_CUT"`
+	Calories int          `json:"calories" example:"2200"`
+	Protein  int          `json:"protein" example:"176"` // Grams
+	Carbs    int          `json:"carbs" example:"198"`   // Grams
+	Fat      int          `json:"fat" example:"61"`      // Grams
+	Split    MacroSplit   `json:"split"`                 // Resulting share of calories
+}
+
+// TDEEEstimate is an estimate of a user's actual energy expenditure, inferred
+// from logged intake and the trend of their weight over a recent window
+type TDEEEstimate struct {
+	TDEE                int       `json:"tdee" bson:"tdee" example:"2650"`                                // kcal per day; 0 when the data is insufficient
+	Confidence          float64   `json:"confidence" bson:"confidence" example:"0.72"`                    // 0 (none) to 1 (high)
+	Sufficient          bool      `json:"sufficient" bson:"sufficient"`                                   // Whether there was enough data for an estimate
+	Reason              string    `json:"reason,omitempty" bson:"reason,omitempty"`                       // Why the data is insufficient
+	AverageIntake       int       `json:"averageIntake" bson:"averageIntake" example:"2150"`              // kcal per logged day
+	WeightChangePerWeek float64   `json:"weightChangePerWeek" bson:"weightChangePerWeek" example:"-0.45"` // kg per week from the weight trend
+	IntakeDays          int       `json:"intakeDays" bson:"intakeDays" example:"24"`                      // Days with logged meals
+	WeighIns            int       `json:"weighIns" bson:"weighIns" example:"12"`                          // Weight entries in the window
+	WindowDays          int       `json:"windowDays" bson:"windowDays" example:"28"`
+	StartDate           string    `json:"startDate" bson:"startDate" example:"2023-02-18"`
+	EndDate             string    `json:"endDate" bson:"endDate" example:"2023-03-17"`
+	ComputedAt          time.Time `json:"computedAt" bson:"computedAt"`
+}
+
+// BMICategory is the WHO classification of a body mass index
+type BMICategory string
+
+// WHO BMI categories for adults
+const (
+	BMIUnderweight BMICategory = "UNDERWEIGHT"     // Below 18.5
+	BMINormal      BMICategory = "NORMAL"          // 18.5 to 24.9
+	BMIOverweight  BMICategory = "OVERWEIGHT"      // 25 to 29.9
+	BMIObeseClass1 BMICategory = "OBESE_CLASS_I"   // 30 to 34.9
+	BMIObeseClass2 BMICategory = "OBESE_CLASS_II"  // 35 to 39.9
+	BMIObeseClass3 BMICategory = "OBESE_CLASS_III" // 40 and above
+)
+
+// UserInfo is a snapshot of a user's body metrics and the health metrics derived from them.
+// A snapshot is stored whenever the profile or weight changes.
 type UserInfo struct {
-	ID            string        `json:"_id" bson:"_id"`
-	UserId        string        `json:"userId" bson:"userId"`
-	Height        float64       `json:"height" bson:"height"`
-	Weight        float64       `json:"weight" bson:"weight"`
-	ActivityLevel ActivityLevel `json:"activityLevel" bson:"activityLevel"`
-	Goal          GoalInfo      `json:"goal" bson:"goal"`
-	BMIRate       float64       `json:"bmiRate" bson:"bmiRate"`
-	MetabolicRate float64       `json:"metabolicRate" bson:"metabolicRate"`
-	CreatedAt     time.Time     `json:"createdAt" bson:"createdAt"`
+	ID                 string        `json:"snapshotId,omitempty" bson:"_id"` // Empty for metrics computed on request
+	UserID             string        `json:"userId" bson:"userId"`
+	Height             float64       `json:"height" bson:"height"`
+	Weight             float64       `json:"weight" bson:"weight"`
+	BodyFatPercent     float64       `json:"bodyFatPercent,omitempty" bson:"bodyFatPercent,omitempty"`
+	ActivityLevel      ActivityLevel `json:"activityLevel" bson:"activityLevel"`
+	Goal               GoalInfo      `json:"goal" bson:"goal"`
+	BMIRate            float64       `json:"bmiRate" bson:"bmiRate"`
+	BMICategory        BMICategory   `json:"bmiCategory" bson:"bmiCategory"`
+	MetabolicRate      float64       `json:"metabolicRate" bson:"metabolicRate"` // BMR in kcal per day
+	TDEE               float64       `json:"tdee" bson:"tdee"`                   // Total daily energy expenditure in kcal
+	Formula            BMRFormula    `json:"formula" bson:"formula"`
+	IdealWeightMin     float64       `json:"idealWeightMin" bson:"idealWeightMin"` // Weight range for a normal BMI, in kg
+	IdealWeightMax     float64       `json:"idealWeightMax" bson:"idealWeightMax"`
+	RecommendedProtein int           `json:"recommendedProtein" bson:"recommendedProtein"` // Grams per day for the current goal
+	RecommendedCarbs   int           `json:"recommendedCarbs" bson:"recommendedCarbs"`
+	RecommendedFat     int           `json:"recommendedFat" bson:"recommendedFat"`
+	CreatedAt          time.Time     `json:"createdAt" bson:"createdAt"`
 }
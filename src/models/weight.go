@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// BodyMeasurements holds optional girth measurements in centimeters
+type BodyMeasurements struct {
+	Neck  float64 `json:"neck,omitempty" bson:"neck,omitempty"`
+	Chest float64 `json:"chest,omitempty" bson:"chest,omitempty"`
+	Waist float64 `json:"waist,omitempty" bson:"waist,omitempty"`
+	Hips  float64 `json:"hips,omitempty" bson:"hips,omitempty"`
+	Arm   float64 `json:"arm,omitempty" bson:"arm,omitempty"`
+	Thigh float64 `json:"thigh,omitempty" bson:"thigh,omitempty"`
+}
+
+// WeightEntry represents a logged body weight and optional body composition measurements
+type WeightEntry struct {
+	ID             string            `json:"entryId" bson:"_id"`
+	UserID         string            `json:"userId" bson:"userId"`
+	Weight         float64           `json:"weight" bson:"weight"` // Kilograms
+	BodyFatPercent float64           `json:"bodyFatPercent,omitempty" bson:"bodyFatPercent,omitempty"`
+	Measurements   *BodyMeasurements `json:"measurements,omitempty" bson:"measurements,omitempty"`
+	Note           string            `json:"note,omitempty" bson:"note,omitempty"`
+	Date           time.Time         `json:"date" bson:"date"`           // Day the entry applies to; used for querying by date range
+	Timestamp      time.Time         `json:"timestamp" bson:"timestamp"` // When the entry was logged
+	CreatedAt      time.Time         `json:"createdAt" bson:"createdAt"`
+}
+
+// WeightHistoryEntry is a weight entry with the trailing moving average at its date
+type WeightHistoryEntry struct {
+	WeightEntry   `bson:",inline"`
+	MovingAverage float64 `json:"movingAverage"`
+}
+
+// WeightProgress compares the current weight with the goal's start and target weights
+type WeightProgress struct {
+	StartDate        time.Time `json:"startDate"`
+	StartWeight      float64   `json:"startWeight"`
+	CurrentWeight    float64   `json:"currentWeight"`
+	TargetWeight     float64   `json:"targetWeight,omitempty"`
+	ChangeSinceStart float64   `json:"changeSinceStart"`          // Negative when weight was lost
+	PercentToTarget  *float64  `json:"percentToTarget,omitempty"` // Omitted when no target weight is set
+}
+
+// WeightHistory is a user's weight log over a date range
+type WeightHistory struct {
+	UserID     string               `json:"userId"`
+	StartDate  string               `json:"startDate" example:"2023-03-01"`
+	EndDate    string               `json:"endDate" example:"2023-03-18"`
+	WindowDays int                  `json:"windowDays" example:"7"` // Moving average window
+	Entries    []WeightHistoryEntry `json:"entries"`
+	Progress   WeightProgress       `json:"progress"`
+}
@@ -2,18 +2,37 @@ package models
 
 import "time"
 
+// DefaultCaloriesBurnFormula describes how calories burned are computed from a workout package
+const DefaultCaloriesBurnFormula = "baseCaloriesBurn * (durationMinutes / baseDurationMinutes) * intensityMultiplier * (weight / 70)"
+
 // WorkoutPackage represents a predefined workout package in the system
 type WorkoutPackage struct {
-	ID                  string   `json:"packageId" bson:"_id"`
-	Name                string   `json:"name" bson:"name"`
-	Description         string   `json:"description" bson:"description"`
-	GoalType            GoalType `json:"goalType" bson:"goalType"` // LOSE, GAIN, or BOTH
-	WorkoutType         string   `json:"workoutType" bson:"workoutType"`
-	BaseDurationMinutes int      `json:"baseDurationMinutes" bson:"baseDurationMinutes"`
-	BaseCaloriesBurn    int      `json:"baseCaloriesBurn" bson:"baseCaloriesBurn"`
-	CaloriesBurnFormula string   `json:"caloriesBurnFormula" bson:"caloriesBurnFormula"`
-	ImageURL            string   `json:"imageUrl" bson:"imageUrl"`
-	Instructions        []string `json:"instructions,omitempty" bson:"instructions,omitempty"`
+	ID                  string            `json:"packageId" bson:"_id"`
+	Name                string            `json:"name" bson:"name"`
+	Description         string            `json:"description" bson:"description"`
+	GoalType            GoalType          `json:"goalType" bson:"goalType" enums:"LOSE,GAIN,BOTH"` // BOTH suits either goal
+	WorkoutType         string            `json:"workoutType" bson:"workoutType"`
+	BaseDurationMinutes int               `json:"baseDurationMinutes" bson:"baseDurationMinutes"`
+	BaseCaloriesBurn    int               `json:"baseCaloriesBurn" bson:"baseCaloriesBurn"`
+	CaloriesBurnFormula string            `json:"caloriesBurnFormula" bson:"caloriesBurnFormula"`
+	ImageURL            string            `json:"imageUrl" bson:"imageUrl"`
+	Instructions        []string          `json:"instructions,omitempty" bson:"instructions,omitempty"`
+	OwnerID             string            `json:"ownerId,omitempty" bson:"ownerId,omitempty"`       // User who owns the package; empty for catalog packages
+	Visibility          PackageVisibility `json:"visibility,omitempty" bson:"visibility,omitempty"` // Set for user-owned packages
+	ShareToken          string            `json:"shareToken,omitempty" bson:"shareToken,omitempty"` // Grants access to LINK packages
+	Version             int               `json:"version" bson:"version" example:"1"`               // Incremented on every update; entries record the version they were logged with
+	Archived            bool              `json:"archived" bson:"archived,omitempty"`               // Archived packages are hidden from lists and cannot be logged
+	ArchivedAt          *time.Time        `json:"archivedAt,omitempty" bson:"archivedAt,omitempty"`
+	CreatedAt           *time.Time        `json:"createdAt,omitempty" bson:"createdAt,omitempty"`
+	UpdatedAt           *time.Time        `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	SuitsGoal           bool              `json:"suitsGoal" bson:"-"` // Whether the package fits the requesting user's current goal; set per request
+}
+
+// WorkoutPackagePage is one page of a workout package list
+type WorkoutPackagePage struct {
+	Packages   []WorkoutPackage `json:"packages"`
+	Total      int64            `json:"total" example:"12"`                              // Packages matching the filters, across all pages
+	NextCursor string           `json:"nextCursor,omitempty" example:"eyJzIjoibmFtZSJ9"` // Pass as cursor to get the next page; omitted on the last page
 }
 
 // WorkoutEntry represents a logged workout by a user
@@ -21,10 +40,11 @@ type WorkoutEntry struct {
 	ID                  string    `json:"entryId" bson:"_id"`
 	UserID              string    `json:"userId" bson:"userId"`
 	PackageID           string    `json:"packageId" bson:"packageId"`
+	PackageVersion      int       `json:"packageVersion,omitempty" bson:"packageVersion,omitempty"` // Package version the burn values come from; 0 for entries logged before versioning
 	IntensityMultiplier float64   `json:"intensityMultiplier" bson:"intensityMultiplier"`
 	DurationMinutes     int       `json:"durationMinutes" bson:"durationMinutes"`
 	CaloriesBurned      int       `json:"caloriesBurned" bson:"caloriesBurned"`
-	Date                time.Time `json:"date" bson:"date"`
-	Timestamp           time.Time `json:"timestamp" bson:"timestamp"` // Used for querying by time range
+	Date                time.Time `json:"date" bson:"date"`           // Day the entry applies to; used for querying by date range
+	Timestamp           time.Time `json:"timestamp" bson:"timestamp"` // When the entry was logged
 	CreatedAt           time.Time `json:"createdAt" bson:"createdAt"`
 }
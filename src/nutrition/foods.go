@@ -0,0 +1,82 @@
+package nutrition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// maxMacroGramsPer100g bounds the macronutrients of 100 g of food
+const maxMacroGramsPer100g = 100
+
+// ForGrams scales nutrients given per 100 g to an amount in grams
+func ForGrams(per100g models.Nutrients, grams float64) models.Nutrients {
+	factor := grams / 100
+	return models.Nutrients{
+		Calories: per100g.Calories * factor,
+		Protein:  per100g.Protein * factor,
+		Carbs:    per100g.Carbs * factor,
+		Fat:      per100g.Fat * factor,
+		Fiber:    per100g.Fiber * factor,
+		Sugar:    per100g.Sugar * factor,
+		Sodium:   per100g.Sodium * factor,
+	}
+}
+
+// RoundNutrients rounds nutrients to one decimal, for display
+func RoundNutrients(n models.Nutrients) models.Nutrients {
+	round := func(value float64) float64 { return math.Round(value*10) / 10 }
+	return models.Nutrients{
+		Calories: round(n.Calories),
+		Protein:  round(n.Protein),
+		Carbs:    round(n.Carbs),
+		Fat:      round(n.Fat),
+		Fiber:    round(n.Fiber),
+		Sugar:    round(n.Sugar),
+		Sodium:   round(n.Sodium),
+	}
+}
+
+// AddNutrients returns the sum of two sets of nutrients
+func AddNutrients(a, b models.Nutrients) models.Nutrients {
+	return models.Nutrients{
+		Calories: a.Calories + b.Calories,
+		Protein:  a.Protein + b.Protein,
+		Carbs:    a.Carbs + b.Carbs,
+		Fat:      a.Fat + b.Fat,
+		Fiber:    a.Fiber + b.Fiber,
+		Sugar:    a.Sugar + b.Sugar,
+		Sodium:   a.Sodium + b.Sodium,
+	}
+}
+
+// RecipeNutrients totals the nutrients of a recipe. foods holds every food the recipe uses, by ID.
+func RecipeNutrients(recipe []models.RecipeItem, foods map[string]models.Food) (models.Nutrients, error) {
+	var total models.Nutrients
+	for _, item := range recipe {
+		food, ok := foods[item.FoodID]
+		if !ok {
+			return models.Nutrients{}, fmt.Errorf("food %s is missing", item.FoodID)
+		}
+		total = AddNutrients(total, ForGrams(food.Per100g, item.Grams))
+	}
+	return total, nil
+}
+
+// CheckPer100g verifies that nutrients per 100 g are physically possible: no negative
+// values, and no more than 100 g of protein, carbs and fat together
+func CheckPer100g(n models.Nutrients) error {
+	for _, value := range []float64{n.Calories, n.Protein, n.Carbs, n.Fat, n.Fiber, n.Sugar, n.Sodium} {
+		if value < 0 || math.IsNaN(value) {
+			return fmt.Errorf("values must not be negative")
+		}
+	}
+	if n.Protein+n.Carbs+n.Fat > maxMacroGramsPer100g {
+		return fmt.Errorf("protein, carbs and fat add up to more than 100 g")
+	}
+	if n.Sugar > n.Carbs {
+		return fmt.Errorf("sugar must not exceed carbs")
+	}
+	return nil
+}
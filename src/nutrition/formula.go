@@ -0,0 +1,139 @@
+// Package nutrition estimates energy needs and derives calorie and macro targets
+package nutrition
+
+import (
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// Profile holds the body metrics used by BMR formulas
+type Profile struct {
+	Gender         models.Gender
+	Age            int     // Whole years
+	WeightKg       float64 // Kilograms
+	HeightCm       float64 // Centimeters
+	BodyFatPercent float64 // 0 when unknown
+}
+
+// Formula estimates basal metabolic rate in kcal per day
+type Formula interface {
+	Name() models.BMRFormula
+	BMR(p Profile) float64
+}
+
+// Default is used when a user has not chosen a formula
+var Default Formula = MifflinStJeor{}
+
+// formulas lists every supported formula by name
+var formulas = map[models.BMRFormula]Formula{
+	models.FormulaHarrisBenedict: HarrisBenedict{},
+	models.FormulaMifflinStJeor:  MifflinStJeor{},
+	models.FormulaKatchMcArdle:   KatchMcArdle{},
+}
+
+// Lookup returns the formula with the given name. An empty name selects Default.
+func Lookup(name models.BMRFormula) (Formula, bool) {
+	if name == "" {
+		return Default, true
+	}
+	f, ok := formulas[name]
+	return f, ok
+}
+
+// HarrisBenedict is the Harris-Benedict equation as revised by Roza and Shizgal (1984).
+// A 30-year-old, 80 kg, 180 cm man has a BMR of about 1854 kcal.
+type HarrisBenedict struct{}
+
+// Name implements Formula
+func (HarrisBenedict) Name() models.BMRFormula { return models.FormulaHarrisBenedict }
+
+// BMR implements Formula
+func (HarrisBenedict) BMR(p Profile) float64 {
+	male := 88.362 + 13.397*p.WeightKg + 4.799*p.HeightCm - 5.677*float64(p.Age)
+	female := 447.593 + 9.247*p.WeightKg + 3.098*p.HeightCm - 4.330*float64(p.Age)
+	return bySex(p.Gender, male, female)
+}
+
+// MifflinStJeor is the Mifflin-St Jeor equation (1990).
+// A 30-year-old, 80 kg, 180 cm man has a BMR of 1780 kcal; a woman with the same metrics 1614 kcal.
+type MifflinStJeor struct{}
+
+// Name implements Formula
+func (MifflinStJeor) Name() models.BMRFormula { return models.FormulaMifflinStJeor }
+
+// BMR implements Formula
+func (MifflinStJeor) BMR(p Profile) float64 {
+	base := 10*p.WeightKg + 6.25*p.HeightCm - 5*float64(p.Age)
+	return bySex(p.Gender, base+5, base-161)
+}
+
+// KatchMcArdle estimates BMR from lean body mass: 370 + 21.6 * LBM.
+// An 80 kg person with 15% body fat has a BMR of about 1839 kcal.
+// Without a measured body fat percentage, it is estimated from BMI.
+type KatchMcArdle struct{}
+
+// Name implements Formula
+func (KatchMcArdle) Name() models.BMRFormula { return models.FormulaKatchMcArdle }
+
+// BMR implements Formula
+func (KatchMcArdle) BMR(p Profile) float64 {
+	bodyFat := p.BodyFatPercent
+	if bodyFat <= 0 {
+		bodyFat = EstimateBodyFat(p)
+	}
+	leanMass := p.WeightKg * (1 - bodyFat/100)
+	return 370 + 21.6*leanMass
+}
+
+// EstimateBodyFat estimates body fat percentage from BMI, age and sex using
+// the Deurenberg (1991) equation, clamped to a physiologically plausible range
+func EstimateBodyFat(p Profile) float64 {
+	bmi := BMI(p.WeightKg, p.HeightCm)
+	sex := bySex(p.Gender, 1, 0)
+	bodyFat := 1.20*bmi + 0.23*float64(p.Age) - 10.8*sex - 5.4
+	return math.Min(math.Max(bodyFat, 3), 60)
+}
+
+// BMI returns the body mass index for a weight in kg and height in cm
+func BMI(weightKg, heightCm float64) float64 {
+	if heightCm <= 0 {
+		return 0
+	}
+	heightM := heightCm / 100
+	return weightKg / (heightM * heightM)
+}
+
+// bySex picks the male or female value of an equation. The equations have no
+// variant for other genders, so the average of both is used.
+func bySex(gender models.Gender, male, female float64) float64 {
+	switch gender {
+	case models.GenderMale:
+		return male
+	case models.GenderFemale:
+		return female
+	default:
+		return (male + female) / 2
+	}
+}
+
+// Age returns the age in whole years on the given day
+func Age(birthDate, on time.Time) int {
+	age := on.Year() - birthDate.Year()
+	if on.Month() < birthDate.Month() || (on.Month() == birthDate.Month() && on.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// ProfileFor returns the BMR profile of a user on the given day
+func ProfileFor(user models.User, on time.Time) Profile {
+	return Profile{
+		Gender:         user.Gender,
+		Age:            Age(user.BirthDate, on),
+		WeightKg:       user.Weight,
+		HeightCm:       user.Height,
+		BodyFatPercent: user.BodyFatPercent,
+	}
+}
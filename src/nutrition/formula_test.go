@@ -0,0 +1,113 @@
+package nutrition
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// referenceMan is the profile the published reference values are given for
+var referenceMan = Profile{Gender: models.GenderMale, Age: 30, WeightKg: 80, HeightCm: 180}
+
+func TestFormulaReferenceValues(t *testing.T) {
+	woman := referenceMan
+	woman.Gender = models.GenderFemale
+	lean := referenceMan
+	lean.BodyFatPercent = 15
+
+	tests := []struct {
+		name    string
+		formula Formula
+		profile Profile
+		want    float64 // kcal per day, rounded
+	}{
+		{"Harris-Benedict man", HarrisBenedict{}, referenceMan, 1854},
+		{"Mifflin-St Jeor man", MifflinStJeor{}, referenceMan, 1780},
+		{"Mifflin-St Jeor woman", MifflinStJeor{}, woman, 1614},
+		{"Katch-McArdle 15% body fat", KatchMcArdle{}, lean, 1839},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := math.Round(tt.formula.BMR(tt.profile)); got != tt.want {
+				t.Errorf("BMR = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKatchMcArdleEstimatesBodyFat(t *testing.T) {
+	// Deurenberg: 1.2 * 24.69 BMI + 0.23 * 30 - 10.8 - 5.4 = 20.33% body fat
+	want := 370 + 21.6*80*(1-EstimateBodyFat(referenceMan)/100)
+	if got := (KatchMcArdle{}).BMR(referenceMan); math.Abs(got-want) > 1e-9 {
+		t.Errorf("BMR without body fat = %v, want %v", got, want)
+	}
+	if got := math.Round(EstimateBodyFat(referenceMan)*10) / 10; got != 20.3 {
+		t.Errorf("EstimateBodyFat = %v, want 20.3", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name models.BMRFormula
+		want models.BMRFormula
+		ok   bool
+	}{
+		{"", models.FormulaMifflinStJeor, true},
+		{models.FormulaHarrisBenedict, models.FormulaHarrisBenedict, true},
+		{models.FormulaKatchMcArdle, models.FormulaKatchMcArdle, true},
+		{"UNKNOWN", "", false},
+	}
+	for _, tt := range tests {
+		formula, ok := Lookup(tt.name)
+		if ok != tt.ok {
+			t.Errorf("Lookup(%q) ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && formula.Name() != tt.want {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.name, formula.Name(), tt.want)
+		}
+	}
+}
+
+func TestActivityFactor(t *testing.T) {
+	tests := []struct {
+		level models.ActivityLevel
+		want  float64
+	}{
+		{models.ActivitySedentary, 1.2},
+		{models.ActivityLow, 1.375},
+		{models.ActivityModerate, 1.55},
+		{models.ActivityHigh, 1.725},
+		{models.ActivityVeryHigh, 1.9},
+		{"", 1.55}, // Unknown levels count as moderate
+	}
+	for _, tt := range tests {
+		if got := ActivityFactor(tt.level); got != tt.want {
+			t.Errorf("ActivityFactor(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+// referenceDay is the day referenceUser is 30, matching referenceMan
+var referenceDay = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// referenceUser is referenceMan as a user who wants to lose weight
+var referenceUser = models.User{
+	Gender:        models.GenderMale,
+	BirthDate:     time.Date(1994, 1, 15, 0, 0, 0, 0, time.UTC),
+	Weight:        80,
+	Height:        180,
+	ActivityLevel: models.ActivityModerate,
+	Goal:          models.GoalInfo{Type: models.GoalTypeLose},
+}
+
+func TestCalculateTargets(t *testing.T) {
+	got := CalculateTargets(referenceUser, referenceDay)
+	// 1780 kcal BMR * 1.55 = 2759 kcal TDEE, less 500 kcal to lose weight
+	if got.Formula != models.FormulaMifflinStJeor || got.BMR != 1780 || got.TDEE != 2759 || got.Calories != 2259 {
+		t.Errorf("CalculateTargets = %s BMR %d TDEE %d, %d kcal; want MIFFLIN_ST_JEOR BMR 1780 TDEE 2759, 2259 kcal",
+			got.Formula, got.BMR, got.TDEE, got.Calories)
+	}
+}
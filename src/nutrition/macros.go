@@ -0,0 +1,136 @@
+package nutrition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// macroRule defines a macro profile: protein is set in grams per kg of body
+// weight, one other macronutrient as a share of calories, and the last one
+// takes the calories that remain
+type macroRule struct {
+	proteinPerKg float64
+	fatPercent   float64 // Share of calories from fat; carbs take the rest
+	carbsPercent float64 // Share of calories from carbs; fat takes the rest. Used when fatPercent is 0.
+}
+
+// macroRules lists the named macro profiles
+var macroRules = map[models.MacroProfile]macroRule{
+	models.MacroBalanced:       {proteinPerKg: 1.6, fatPercent: 30},
+	models.MacroHighProteinCut: {proteinPerKg: 2.2, fatPercent: 25},
+	models.MacroLeanBulk:       {proteinPerKg: 1.8, fatPercent: 25},
+	models.MacroKeto:           {proteinPerKg: 1.6, carbsPercent: 5},
+	models.MacroLowFat:         {proteinPerKg: 1.6, fatPercent: 20},
+}
+
+// splitTolerance is how far custom percentages may be from adding up to 100
+const splitTolerance = 0.1
+
+// macroCalorieTolerance is how far stated calories may be from the calories implied
+// by the macros, as a share of the stated calories. Fibre, alcohol and rounding on
+// labels make exact agreement rare.
+const macroCalorieTolerance = 0.15
+
+// DefaultMacroProfile returns the profile used when a user has not chosen one
+func DefaultMacroProfile(goal models.GoalType) models.MacroProfile {
+	switch goal {
+	case models.GoalTypeLose:
+		return models.MacroHighProteinCut
+	case models.GoalTypeGain:
+		return models.MacroLeanBulk
+	default:
+		return models.MacroBalanced
+	}
+}
+
+// ValidMacroProfile reports whether a profile name is known, including CUSTOM
+func ValidMacroProfile(profile models.MacroProfile) bool {
+	_, ok := macroRules[profile]
+	return ok || profile == models.MacroCustom
+}
+
+// CheckSplit verifies that custom macro percentages are non-negative and add up to 100
+func CheckSplit(split models.MacroSplit) error {
+	if split.ProteinPercent < 0 || split.CarbsPercent < 0 || split.FatPercent < 0 {
+		return fmt.Errorf("percentages must not be negative")
+	}
+	if total := split.ProteinPercent + split.CarbsPercent + split.FatPercent; math.Abs(total-100) > splitTolerance {
+		return fmt.Errorf("percentages must add up to 100, got %g", round1(total))
+	}
+	return nil
+}
+
+// MacroCalories returns the calories implied by macronutrient grams
+func MacroCalories(protein, carbs, fat int) int {
+	return protein*CaloriesPerGramProtein + carbs*CaloriesPerGramCarbs + fat*CaloriesPerGramFat
+}
+
+// CheckMacroCalories verifies that stated calories agree with the macros within the tolerance
+func CheckMacroCalories(calories, protein, carbs, fat int) error {
+	implied := MacroCalories(protein, carbs, fat)
+	if math.Abs(float64(calories-implied)) > float64(calories)*macroCalorieTolerance {
+		return fmt.Errorf("must be within %.0f%% of the %d kcal implied by the macros", macroCalorieTolerance*100, implied)
+	}
+	return nil
+}
+
+// Macros splits a daily calorie target into macronutrient grams.
+// An empty profile selects DefaultMacroProfile for the goal; CUSTOM uses the
+// custom percentages, falling back to the default if they are missing or invalid.
+func Macros(calories int, weightKg float64, goal models.GoalType, profile models.MacroProfile, custom *models.MacroSplit) models.MacroTargets {
+	if profile == models.MacroCustom && (custom == nil || CheckSplit(*custom) != nil) {
+		profile = ""
+	}
+	if profile == "" || !ValidMacroProfile(profile) {
+		profile = DefaultMacroProfile(goal)
+	}
+
+	kcal := math.Max(float64(calories), 0)
+	var proteinKcal, carbsKcal, fatKcal float64
+
+	if profile == models.MacroCustom {
+		proteinKcal = kcal * custom.ProteinPercent / 100
+		carbsKcal = kcal * custom.CarbsPercent / 100
+		fatKcal = kcal * custom.FatPercent / 100
+	} else {
+		rule := macroRules[profile]
+		fixedPercent := rule.fatPercent
+		if fixedPercent == 0 {
+			fixedPercent = rule.carbsPercent
+		}
+
+		// Protein never takes more than the calories left after the fixed share
+		fixedKcal := kcal * fixedPercent / 100
+		proteinKcal = math.Min(rule.proteinPerKg*weightKg*CaloriesPerGramProtein, kcal-fixedKcal)
+		remainingKcal := kcal - fixedKcal - proteinKcal
+
+		if rule.fatPercent > 0 {
+			fatKcal, carbsKcal = fixedKcal, remainingKcal
+		} else {
+			carbsKcal, fatKcal = fixedKcal, remainingKcal
+		}
+	}
+
+	return models.MacroTargets{
+		Profile:  profile,
+		Calories: calories,
+		Protein:  int(math.Round(proteinKcal / CaloriesPerGramProtein)),
+		Carbs:    int(math.Round(carbsKcal / CaloriesPerGramCarbs)),
+		Fat:      int(math.Round(fatKcal / CaloriesPerGramFat)),
+		Split: models.MacroSplit{
+			ProteinPercent: share(proteinKcal, kcal),
+			CarbsPercent:   share(carbsKcal, kcal),
+			FatPercent:     share(fatKcal, kcal),
+		},
+	}
+}
+
+// share returns part as a percentage of total, rounded to one decimal place
+func share(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round1(part / total * 100)
+}
@@ -0,0 +1,70 @@
+package nutrition
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+func TestMacros(t *testing.T) {
+	custom := &models.MacroSplit{ProteinPercent: 30, CarbsPercent: 40, FatPercent: 30}
+	invalid := &models.MacroSplit{ProteinPercent: 50, CarbsPercent: 50, FatPercent: 50}
+
+	tests := []struct {
+		name    string
+		goal    models.GoalType
+		profile models.MacroProfile
+		custom  *models.MacroSplit
+		want    models.MacroTargets // Split is not compared
+	}{
+		// 2.2 g/kg protein, 25% fat, carbs take the rest
+		{"default for losing", models.GoalTypeLose, "", nil,
+			models.MacroTargets{Profile: models.MacroHighProteinCut, Protein: 176, Carbs: 199, Fat: 56}},
+		// 1.8 g/kg protein, 25% fat
+		{"default for gaining", models.GoalTypeGain, "", nil,
+			models.MacroTargets{Profile: models.MacroLeanBulk, Protein: 144, Carbs: 231, Fat: 56}},
+		// 1.6 g/kg protein, 30% fat
+		{"balanced", models.GoalTypeLose, models.MacroBalanced, nil,
+			models.MacroTargets{Profile: models.MacroBalanced, Protein: 128, Carbs: 222, Fat: 67}},
+		// 1.6 g/kg protein, 5% carbs, fat takes the rest
+		{"keto", models.GoalTypeLose, models.MacroKeto, nil,
+			models.MacroTargets{Profile: models.MacroKeto, Protein: 128, Carbs: 25, Fat: 154}},
+		// 1.6 g/kg protein, 20% fat
+		{"low fat", models.GoalTypeLose, models.MacroLowFat, nil,
+			models.MacroTargets{Profile: models.MacroLowFat, Protein: 128, Carbs: 272, Fat: 44}},
+		{"custom", models.GoalTypeLose, models.MacroCustom, custom,
+			models.MacroTargets{Profile: models.MacroCustom, Protein: 150, Carbs: 200, Fat: 67}},
+		{"custom not adding up falls back to the default", models.GoalTypeGain, models.MacroCustom, invalid,
+			models.MacroTargets{Profile: models.MacroLeanBulk, Protein: 144, Carbs: 231, Fat: 56}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Macros(2000, 80, tt.goal, tt.profile, tt.custom)
+			if got.Profile != tt.want.Profile || got.Protein != tt.want.Protein || got.Carbs != tt.want.Carbs || got.Fat != tt.want.Fat {
+				t.Errorf("Macros = %s %dP/%dC/%dF, want %s %dP/%dC/%dF",
+					got.Profile, got.Protein, got.Carbs, got.Fat,
+					tt.want.Profile, tt.want.Protein, tt.want.Carbs, tt.want.Fat)
+			}
+			if split := got.Split.ProteinPercent + got.Split.CarbsPercent + got.Split.FatPercent; math.Abs(split-100) > 0.2 {
+				t.Errorf("split adds up to %v, want 100", split)
+			}
+		})
+	}
+}
+
+func TestMacrosProteinCappedByCalories(t *testing.T) {
+	// 2.2 g/kg for 150 kg is 1320 kcal, more than the 900 kcal left after 25% fat
+	got := Macros(1200, 150, models.GoalTypeLose, models.MacroHighProteinCut, nil)
+	if got.Protein != 225 || got.Carbs != 0 || got.Fat != 33 {
+		t.Errorf("Macros = %dP/%dC/%dF, want 225P/0C/33F", got.Protein, got.Carbs, got.Fat)
+	}
+}
+
+func TestCalculateTargetsMacroProfile(t *testing.T) {
+	got := CalculateTargets(referenceUser, referenceDay)
+	// The default profile for losing weight, 2.2 g/kg protein
+	if got.MacroProfile != models.MacroHighProteinCut || got.Protein != 176 {
+		t.Errorf("CalculateTargets macros = %s %d g protein, want HIGH_PROTEIN_CUT 176 g", got.MacroProfile, got.Protein)
+	}
+}
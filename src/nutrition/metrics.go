@@ -0,0 +1,71 @@
+package nutrition
+
+import (
+	"math"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// Normal BMI range used for the ideal weight range
+const (
+	normalBMIMin = 18.5
+	normalBMIMax = 24.9
+)
+
+// ClassifyBMI returns the WHO category of a body mass index
+func ClassifyBMI(bmi float64) models.BMICategory {
+	switch {
+	case bmi < 18.5:
+		return models.BMIUnderweight
+	case bmi < 25:
+		return models.BMINormal
+	case bmi < 30:
+		return models.BMIOverweight
+	case bmi < 35:
+		return models.BMIObeseClass1
+	case bmi < 40:
+		return models.BMIObeseClass2
+	default:
+		return models.BMIObeseClass3
+	}
+}
+
+// IdealWeightRange returns the weights in kg that give a normal BMI at the given height
+func IdealWeightRange(heightCm float64) (min, max float64) {
+	heightM := heightCm / 100
+	return round1(normalBMIMin * heightM * heightM), round1(normalBMIMax * heightM * heightM)
+}
+
+// Metrics computes a snapshot of a user's health metrics on the given day.
+// The recommended macros are the AUTO targets, even when the user has pinned MANUAL targets.
+func Metrics(user models.User, on time.Time) models.UserInfo {
+	bmi := round1(BMI(user.Weight, user.Height))
+	idealMin, idealMax := IdealWeightRange(user.Height)
+	targets := CalculateTargets(user, on)
+
+	return models.UserInfo{
+		UserID:             user.ID,
+		Height:             user.Height,
+		Weight:             user.Weight,
+		BodyFatPercent:     user.BodyFatPercent,
+		ActivityLevel:      user.ActivityLevel,
+		Goal:               user.Goal,
+		BMIRate:            bmi,
+		BMICategory:        ClassifyBMI(bmi),
+		MetabolicRate:      float64(targets.BMR),
+		TDEE:               float64(targets.TDEE),
+		Formula:            targets.Formula,
+		IdealWeightMin:     idealMin,
+		IdealWeightMax:     idealMax,
+		RecommendedProtein: targets.Protein,
+		RecommendedCarbs:   targets.Carbs,
+		RecommendedFat:     targets.Fat,
+		CreatedAt:          on,
+	}
+}
+
+// round1 rounds to one decimal place
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,127 @@
+package nutrition
+
+import (
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+// Calories per gram of each macronutrient
+const (
+	CaloriesPerGramProtein = 4
+	CaloriesPerGramCarbs   = 4
+	CaloriesPerGramFat     = 9
+)
+
+// EnergyPerKg is the approximate energy content of a kilogram of body weight change
+const EnergyPerKg = 7700
+
+// Limits on when an adaptive TDEE estimate replaces the formula TDEE
+const (
+	MinAdaptiveConfidence = 0.5
+	maxAdaptiveAge        = 14 * 24 * time.Hour
+)
+
+// goalAdjustment is the daily deficit or surplus applied for weight loss or gain
+const goalAdjustment = 500
+
+// activityFactors are the standard physical activity multipliers applied to BMR
+var activityFactors = map[models.ActivityLevel]float64{
+	models.ActivitySedentary: 1.2,   // Little or no exercise
+	models.ActivityLow:       1.375, // Light exercise 1-3 days a week
+	models.ActivityModerate:  1.55,  // Moderate exercise 3-5 days a week
+	models.ActivityHigh:      1.725, // Hard exercise 6-7 days a week
+	models.ActivityVeryHigh:  1.9,   // Very hard exercise or a physical job
+}
+
+// ActivityFactor returns the TDEE multiplier for an activity level
+func ActivityFactor(level models.ActivityLevel) float64 {
+	if factor, ok := activityFactors[level]; ok {
+		return factor
+	}
+	return activityFactors[models.ActivityModerate]
+}
+
+// Targets are the energy estimates and daily targets calculated for a user
+type Targets struct {
+	Formula      models.BMRFormula
+	MacroProfile models.MacroProfile
+	BMR          int
+	TDEE         int
+	Adaptive     bool // TDEE is the adaptive estimate rather than BMR times the activity factor
+	Calories     int
+	Protein      int
+	Carbs        int
+	Fat          int
+}
+
+// CalculateTargets estimates a user's BMR and TDEE with their chosen formula
+// and derives daily calorie and macro targets for their goal and macro profile.
+// Users who opted in to adaptive targets use their adaptive TDEE estimate
+// instead of the formula TDEE while the estimate is usable.
+func CalculateTargets(user models.User, on time.Time) Targets {
+	formula, ok := Lookup(user.BMRFormula)
+	if !ok {
+		formula = Default
+	}
+
+	bmr := formula.BMR(ProfileFor(user, on))
+	tdee := bmr * ActivityFactor(user.ActivityLevel)
+
+	adaptive := user.AdaptiveTargets && UsableEstimate(user.AdaptiveTDEE, on)
+	if adaptive {
+		tdee = float64(user.AdaptiveTDEE.TDEE)
+	}
+
+	calories := int(tdee)
+	switch user.Goal.Type {
+	case models.GoalTypeLose:
+		calories -= goalAdjustment
+	case models.GoalTypeGain:
+		calories += goalAdjustment
+	}
+
+	macros := Macros(calories, user.Weight, user.Goal.Type, user.MacroProfile, user.CustomMacros)
+
+	return Targets{
+		Formula:      formula.Name(),
+		MacroProfile: macros.Profile,
+		BMR:          int(bmr),
+		TDEE:         int(tdee),
+		Adaptive:     adaptive,
+		Calories:     calories,
+		Protein:      macros.Protein,
+		Carbs:        macros.Carbs,
+		Fat:          macros.Fat,
+	}
+}
+
+// UsableEstimate reports whether an adaptive TDEE estimate is sufficient,
+// confident enough and recent enough to base targets on
+func UsableEstimate(estimate *models.TDEEEstimate, on time.Time) bool {
+	return estimate != nil &&
+		estimate.Sufficient &&
+		estimate.Confidence >= MinAdaptiveConfidence &&
+		on.Sub(estimate.ComputedAt) <= maxAdaptiveAge
+}
+
+// ApplyTargets recalculates a user's calorie and macro targets from their
+// body metrics, goal and BMR formula, unless the targets are pinned in MANUAL mode
+func ApplyTargets(user *models.User, on time.Time) {
+	if user.Goal.IsManual() {
+		return
+	}
+
+	targets := CalculateTargets(*user, on)
+
+	user.Goal.TargetMode = models.TargetModeAuto
+	user.Goal.TargetCalories = targets.Calories
+	user.Goal.TargetProtein = targets.Protein
+	user.Goal.TargetCarbs = targets.Carbs
+	user.Goal.TargetFat = targets.Fat
+	user.Goal.Formula = targets.Formula
+	user.Goal.MacroProfile = targets.MacroProfile
+	user.Goal.BMR = targets.BMR
+	user.Goal.TDEE = targets.TDEE
+	user.Goal.AdaptiveTDEE = targets.Adaptive
+}
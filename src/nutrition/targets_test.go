@@ -0,0 +1,20 @@
+package nutrition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+)
+
+func TestCalculateTargetsAdaptive(t *testing.T) {
+	user := referenceUser
+	user.AdaptiveTargets = true
+	user.AdaptiveTDEE = &models.TDEEEstimate{TDEE: 2500, Sufficient: true, Confidence: 0.8, ComputedAt: referenceDay.Add(-24 * time.Hour)}
+
+	// The estimate replaces the 2759 kcal formula TDEE
+	if got := CalculateTargets(user, referenceDay); !got.Adaptive || got.TDEE != 2500 || got.Calories != 2000 {
+		t.Errorf("adaptive CalculateTargets = adaptive %v TDEE %d, %d kcal; want adaptive TDEE 2500, 2000 kcal",
+			got.Adaptive, got.TDEE, got.Calories)
+	}
+}
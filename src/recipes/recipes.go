@@ -0,0 +1,117 @@
+// Package recipes derives meal package nutrition from recipes of foods and keeps
+// packages in step when a food's nutrition data is corrected
+package recipes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+)
+
+// maxAttempts bounds how often a recompute retries after losing a race with another update
+const maxAttempts = 3
+
+// Apply sets a package's calories, macros and ingredients from its recipe, loading
+// the foods from the store. Packages without a recipe are left unchanged. A food
+// that does not exist fails with db.ErrNotFound.
+func Apply(ctx context.Context, store db.Store, pkg *models.MealPackage) error {
+	if len(pkg.Recipe) == 0 {
+		return nil
+	}
+
+	foods := make(map[string]models.Food, len(pkg.Recipe))
+	for _, item := range pkg.Recipe {
+		if _, ok := foods[item.FoodID]; ok {
+			continue
+		}
+		food, err := store.GetFood(ctx, item.FoodID)
+		if err != nil {
+			return err
+		}
+		foods[item.FoodID] = food
+	}
+
+	return derive(pkg, foods)
+}
+
+// derive sets a package's calories, macros and ingredients from its recipe and the given foods
+func derive(pkg *models.MealPackage, foods map[string]models.Food) error {
+	total, err := nutrition.RecipeNutrients(pkg.Recipe, foods)
+	if err != nil {
+		return err
+	}
+
+	pkg.BaseCalories = int(math.Round(total.Calories))
+	pkg.BaseProtein = int(math.Round(total.Protein))
+	pkg.BaseCarbs = int(math.Round(total.Carbs))
+	pkg.BaseFat = int(math.Round(total.Fat))
+
+	// Ingredients stay a list of names so that text search keeps working
+	pkg.Ingredients = make([]string, 0, len(pkg.Recipe))
+	for _, item := range pkg.Recipe {
+		amount := strconv.FormatFloat(item.Grams, 'f', -1, 64)
+		pkg.Ingredients = append(pkg.Ingredients, fmt.Sprintf("%s (%s g)", foods[item.FoodID].Name, amount))
+	}
+	return nil
+}
+
+// RecomputeForFoods recomputes every meal package whose recipe uses one of the foods and
+// stores a new version of each package whose values change, so that entries logged earlier
+// keep theirs. It returns the IDs of the updated packages, including those updated before an error.
+func RecomputeForFoods(ctx context.Context, store db.Store, foodIDs ...string) ([]string, error) {
+	packages, err := store.GetMealPackagesByFoods(ctx, foodIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		changed, err := recompute(ctx, store, pkg)
+		if err != nil {
+			return updated, fmt.Errorf("meal package %s: %w", pkg.ID, err)
+		}
+		if changed {
+			updated = append(updated, pkg.ID)
+		}
+	}
+	return updated, nil
+}
+
+// recompute re-derives one package and stores it if its values changed.
+// An update that loses a race with another one is retried on the new version.
+func recompute(ctx context.Context, store db.Store, pkg models.MealPackage) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		next := pkg
+		if err := Apply(ctx, store, &next); err != nil {
+			return false, err
+		}
+		if sameNutrition(pkg, next) {
+			return false, nil
+		}
+
+		_, err := store.UpdateMealPackage(ctx, next)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, db.ErrConflict) || attempt == maxAttempts {
+			return false, err
+		}
+
+		if pkg, err = store.GetMealPackage(ctx, pkg.ID); err != nil {
+			return false, err
+		}
+	}
+}
+
+// sameNutrition reports whether two packages have the same derived values
+func sameNutrition(a, b models.MealPackage) bool {
+	return a.BaseCalories == b.BaseCalories && a.BaseProtein == b.BaseProtein &&
+		a.BaseCarbs == b.BaseCarbs && a.BaseFat == b.BaseFat && slices.Equal(a.Ingredients, b.Ingredients)
+}
@@ -0,0 +1,255 @@
+// Package seed loads package catalog fixtures and upserts them into a store
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/nutrition"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatVersion is the fixture file format this package reads
+const FormatVersion = 1
+
+// BaseEnv is the fixture set loaded for every environment
+const BaseEnv = "base"
+
+// File is the content of one fixture file. Packages use the same field names as the API.
+type File struct {
+	Version         int                     `json:"version"`
+	MealPackages    []models.MealPackage    `json:"mealPackages"`
+	WorkoutPackages []models.WorkoutPackage `json:"workoutPackages"`
+}
+
+// Set is the catalog described by the fixtures of an environment, in file order
+type Set struct {
+	MealPackages    []models.MealPackage
+	WorkoutPackages []models.WorkoutPackage
+}
+
+// Load reads the fixtures in dir/base and then dir/env. Files are read in name order
+// and may be JSON (.json) or YAML (.yaml, .yml). A package in the environment's
+// fixtures replaces the base package with the same ID.
+func Load(dir, env string) (Set, error) {
+	layers := []string{BaseEnv}
+	if env != "" && env != BaseEnv {
+		if info, err := os.Stat(filepath.Join(dir, env)); err != nil || !info.IsDir() {
+			return Set{}, fmt.Errorf("unknown fixture environment %q: no directory %s", env, filepath.Join(dir, env))
+		}
+		layers = append(layers, env)
+	}
+
+	var set Set
+	mealIndex := make(map[string]int)
+	workoutIndex := make(map[string]int)
+
+	for _, layer := range layers {
+		files, err := fixtureFiles(filepath.Join(dir, layer))
+		if err != nil {
+			return Set{}, err
+		}
+
+		// IDs may repeat across layers but not within one
+		seen := make(map[string]string)
+		for _, path := range files {
+			file, err := ReadFile(path)
+			if err != nil {
+				return Set{}, err
+			}
+
+			for _, pkg := range file.MealPackages {
+				key := "meal:" + pkg.ID
+				if other, ok := seen[key]; ok {
+					return Set{}, fmt.Errorf("%s: meal package %s is also defined in %s", path, pkg.ID, other)
+				}
+				seen[key] = path
+
+				if i, ok := mealIndex[pkg.ID]; ok {
+					set.MealPackages[i] = pkg
+				} else {
+					mealIndex[pkg.ID] = len(set.MealPackages)
+					set.MealPackages = append(set.MealPackages, pkg)
+				}
+			}
+
+			for _, pkg := range file.WorkoutPackages {
+				key := "workout:" + pkg.ID
+				if other, ok := seen[key]; ok {
+					return Set{}, fmt.Errorf("%s: workout package %s is also defined in %s", path, pkg.ID, other)
+				}
+				seen[key] = path
+
+				if i, ok := workoutIndex[pkg.ID]; ok {
+					set.WorkoutPackages[i] = pkg
+				} else {
+					workoutIndex[pkg.ID] = len(set.WorkoutPackages)
+					set.WorkoutPackages = append(set.WorkoutPackages, pkg)
+				}
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// fixtureFiles lists the fixture files of one layer in name order.
+// A missing base directory is treated as empty.
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadFile reads and validates one fixture file
+func ReadFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, err
+	}
+
+	file, err := Decode(filepath.Ext(path), data)
+	if err != nil {
+		return File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Decode parses fixture data in the format named by ext and validates it.
+// YAML is converted to JSON first so that both formats use the JSON field names.
+func Decode(ext string, data []byte) (File, error) {
+	switch strings.ToLower(ext) {
+	case ".json":
+	case ".yaml", ".yml":
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return File{}, err
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return File{}, err
+		}
+		data = converted
+	default:
+		return File{}, fmt.Errorf("unsupported fixture format %q", ext)
+	}
+
+	// Unknown fields are rejected so that typos do not silently drop values
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var file File
+	if err := decoder.Decode(&file); err != nil {
+		return File{}, err
+	}
+	if file.Version != FormatVersion {
+		return File{}, fmt.Errorf("unsupported fixture version %d, expected %d", file.Version, FormatVersion)
+	}
+
+	for i := range file.MealPackages {
+		if err := validateMealPackage(file.MealPackages[i]); err != nil {
+			return File{}, err
+		}
+	}
+	for i := range file.WorkoutPackages {
+		pkg := &file.WorkoutPackages[i]
+		if pkg.CaloriesBurnFormula == "" {
+			pkg.CaloriesBurnFormula = models.DefaultCaloriesBurnFormula
+		}
+		if err := validateWorkoutPackage(*pkg); err != nil {
+			return File{}, err
+		}
+	}
+
+	return file, nil
+}
+
+// validateMealPackage applies the rules the admin API enforces on meal packages
+func validateMealPackage(pkg models.MealPackage) error {
+	if pkg.ID == "" {
+		return fmt.Errorf("meal package %q: packageId is required so that seeding is repeatable", pkg.Name)
+	}
+	if pkg.Name == "" {
+		return fmt.Errorf("meal package %s: name is required", pkg.ID)
+	}
+	if pkg.OwnerID != "" || pkg.Visibility != "" || pkg.ShareToken != "" {
+		return fmt.Errorf("meal package %s: fixtures define catalog packages, which have no ownerId, visibility or shareToken", pkg.ID)
+	}
+	if !validGoalType(pkg.GoalType) {
+		return fmt.Errorf("meal package %s: goalType must be one of LOSE, GAIN, BOTH", pkg.ID)
+	}
+	switch pkg.MealType {
+	case models.MealTypeBreakfast, models.MealTypeLunch, models.MealTypeDinner, models.MealTypeSnack:
+	default:
+		return fmt.Errorf("meal package %s: mealType must be one of BREAKFAST, LUNCH, DINNER, SNACK", pkg.ID)
+	}
+
+	// Packages with a recipe get their calories and macros from its foods when seeded
+	if len(pkg.Recipe) > 0 {
+		for _, item := range pkg.Recipe {
+			if item.FoodID == "" || item.Grams <= 0 {
+				return fmt.Errorf("meal package %s: recipe items need a foodId and positive grams", pkg.ID)
+			}
+		}
+		return nil
+	}
+
+	if pkg.BaseCalories <= 0 || pkg.BaseProtein < 0 || pkg.BaseCarbs < 0 || pkg.BaseFat < 0 {
+		return fmt.Errorf("meal package %s: calories must be positive and macros non-negative", pkg.ID)
+	}
+	if err := nutrition.CheckMacroCalories(pkg.BaseCalories, pkg.BaseProtein, pkg.BaseCarbs, pkg.BaseFat); err != nil {
+		return fmt.Errorf("meal package %s: baseCalories %w", pkg.ID, err)
+	}
+	return nil
+}
+
+// validateWorkoutPackage applies the rules the admin API enforces on workout packages
+func validateWorkoutPackage(pkg models.WorkoutPackage) error {
+	if pkg.ID == "" {
+		return fmt.Errorf("workout package %q: packageId is required so that seeding is repeatable", pkg.Name)
+	}
+	if pkg.Name == "" {
+		return fmt.Errorf("workout package %s: name is required", pkg.ID)
+	}
+	if pkg.OwnerID != "" || pkg.Visibility != "" || pkg.ShareToken != "" {
+		return fmt.Errorf("workout package %s: fixtures define catalog packages, which have no ownerId, visibility or shareToken", pkg.ID)
+	}
+	if !validGoalType(pkg.GoalType) {
+		return fmt.Errorf("workout package %s: goalType must be one of LOSE, GAIN, BOTH", pkg.ID)
+	}
+	if pkg.WorkoutType == "" {
+		return fmt.Errorf("workout package %s: workoutType is required", pkg.ID)
+	}
+	if pkg.BaseDurationMinutes <= 0 || pkg.BaseCaloriesBurn <= 0 {
+		return fmt.Errorf("workout package %s: baseDurationMinutes and baseCaloriesBurn must be positive", pkg.ID)
+	}
+	return nil
+}
+
+// validGoalType reports whether a package goal type is one packages may have
+func validGoalType(goal models.GoalType) bool {
+	return goal == models.GoalTypeLose || goal == models.GoalTypeGain || goal == models.GoalTypeBoth
+}
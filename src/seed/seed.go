@@ -0,0 +1,230 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/zhenyili/BalanceLife/src/db"
+	"github.com/zhenyili/BalanceLife/src/models"
+	"github.com/zhenyili/BalanceLife/src/recipes"
+)
+
+// Action is what seeding does to one package
+type Action string
+
+// Seed actions
+const (
+	ActionCreate    Action = "CREATE"
+	ActionUpdate    Action = "UPDATE"
+	ActionUnchanged Action = "UNCHANGED"
+)
+
+// FieldChange is one field whose stored value differs from the fixture
+type FieldChange struct {
+	Field string
+	Old   any // nil when the field is not set
+	New   any
+}
+
+// Change describes how seeding brings one package in line with its fixture
+type Change struct {
+	Kind   string // "meal" or "workout"
+	ID     string
+	Name   string
+	Action Action
+	Fields []FieldChange // Set for updates
+
+	meal    *models.MealPackage
+	workout *models.WorkoutPackage
+	version int // Current version, which an update replaces
+}
+
+// Plan compares a fixture set with the store and returns the change for every package.
+// It does not modify the store, so it doubles as a dry run.
+func Plan(ctx context.Context, store db.Store, set Set) ([]Change, error) {
+	changes := make([]Change, 0, len(set.MealPackages)+len(set.WorkoutPackages))
+
+	for _, pkg := range set.MealPackages {
+		// Recipes are computed from the foods already in the store
+		if err := recipes.Apply(ctx, store, &pkg); err != nil {
+			return nil, fmt.Errorf("meal package %s: recipe: %w", pkg.ID, err)
+		}
+		change := Change{Kind: "meal", ID: pkg.ID, Name: pkg.Name, meal: &pkg}
+
+		current, err := store.GetMealPackage(ctx, pkg.ID)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			change.Action = ActionCreate
+		case err != nil:
+			return nil, fmt.Errorf("meal package %s: %w", pkg.ID, err)
+		default:
+			change.version = current.Version
+			change.Fields, err = diffFields(catalogMealPackage(current), catalogMealPackage(pkg))
+			if err != nil {
+				return nil, err
+			}
+			change.Action = actionFor(change.Fields)
+		}
+		changes = append(changes, change)
+	}
+
+	for _, pkg := range set.WorkoutPackages {
+		change := Change{Kind: "workout", ID: pkg.ID, Name: pkg.Name, workout: &pkg}
+
+		current, err := store.GetWorkoutPackage(ctx, pkg.ID)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			change.Action = ActionCreate
+		case err != nil:
+			return nil, fmt.Errorf("workout package %s: %w", pkg.ID, err)
+		default:
+			change.version = current.Version
+			change.Fields, err = diffFields(catalogWorkoutPackage(current), catalogWorkoutPackage(pkg))
+			if err != nil {
+				return nil, err
+			}
+			change.Action = actionFor(change.Fields)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// Apply makes the changes of a plan through the store. Packages are created at
+// version 1 or updated to a new version; the archived flag is applied separately
+// because it does not create a version. Unchanged packages are skipped, so applying
+// a fresh plan again does nothing.
+func Apply(ctx context.Context, store db.Store, changes []Change) error {
+	for _, change := range changes {
+		if err := apply(ctx, store, change); err != nil {
+			return fmt.Errorf("%s package %s: %w", change.Kind, change.ID, err)
+		}
+	}
+	return nil
+}
+
+// apply makes one change
+func apply(ctx context.Context, store db.Store, change Change) error {
+	if change.Action == ActionUnchanged {
+		return nil
+	}
+
+	// The archived flag is not part of a version, so it is set on its own and a
+	// change to it alone does not update the package
+	archivedChanged := false
+	for _, field := range change.Fields {
+		if field.Field == "archived" {
+			archivedChanged = true
+		}
+	}
+	update := change.Action == ActionUpdate && (len(change.Fields) > 1 || !archivedChanged)
+
+	switch change.Kind {
+	case "meal":
+		pkg := *change.meal
+		var err error
+		if change.Action == ActionCreate {
+			_, err = store.CreateMealPackage(ctx, pkg)
+		} else if update {
+			pkg.Version = change.version
+			_, err = store.UpdateMealPackage(ctx, pkg)
+		}
+		if err == nil && (archivedChanged || change.Action == ActionCreate && pkg.Archived) {
+			_, err = store.ArchiveMealPackage(ctx, pkg.ID, pkg.Archived)
+		}
+		return err
+
+	case "workout":
+		pkg := *change.workout
+		var err error
+		if change.Action == ActionCreate {
+			_, err = store.CreateWorkoutPackage(ctx, pkg)
+		} else if update {
+			pkg.Version = change.version
+			_, err = store.UpdateWorkoutPackage(ctx, pkg)
+		}
+		if err == nil && (archivedChanged || change.Action == ActionCreate && pkg.Archived) {
+			_, err = store.ArchiveWorkoutPackage(ctx, pkg.ID, pkg.Archived)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unknown package kind %q", change.Kind)
+}
+
+// actionFor returns the action for a package with the given field differences
+func actionFor(fields []FieldChange) Action {
+	if len(fields) == 0 {
+		return ActionUnchanged
+	}
+	return ActionUpdate
+}
+
+// catalogMealPackage clears the fields that the store manages, leaving those a fixture defines
+func catalogMealPackage(pkg models.MealPackage) models.MealPackage {
+	pkg.Version = 0
+	pkg.ArchivedAt = nil
+	pkg.CreatedAt = nil
+	pkg.UpdatedAt = nil
+	pkg.SuitsGoal = false
+	return pkg
+}
+
+// catalogWorkoutPackage clears the fields that the store manages, leaving those a fixture defines
+func catalogWorkoutPackage(pkg models.WorkoutPackage) models.WorkoutPackage {
+	pkg.Version = 0
+	pkg.ArchivedAt = nil
+	pkg.CreatedAt = nil
+	pkg.UpdatedAt = nil
+	pkg.SuitsGoal = false
+	return pkg
+}
+
+// diffFields compares the JSON fields of two packages and returns those that differ, by name
+func diffFields(current, desired any) ([]FieldChange, error) {
+	old, err := jsonFields(current)
+	if err != nil {
+		return nil, err
+	}
+	next, err := jsonFields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(next))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range next {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if !reflect.DeepEqual(old[name], next[name]) {
+			changes = append(changes, FieldChange{Field: name, Old: old[name], New: next[name]})
+		}
+	}
+	return changes, nil
+}
+
+// jsonFields encodes a value as a JSON object and returns its fields
+func jsonFields(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"time"
@@ -18,3 +20,14 @@ func GenerateID() string {
 	// In production, use UUID or similar library
 	return fmt.Sprintf("%d%d", time.Now().UnixNano(), rnd.Intn(1000))
 }
+
+// GenerateToken generates an unguessable token, e.g. for share links.
+// Unlike GenerateID it uses a cryptographic random source.
+func GenerateToken() string {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(fmt.Sprintf("failed to generate token: %v", err))
+	}
+	return hex.EncodeToString(b)
+}